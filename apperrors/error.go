@@ -8,31 +8,122 @@ import (
 type appError struct {
 	code       code
 	httpStatus int
+	// 利用者向けメッセージを引くためのキー
+	messageKey string
+	details    FieldErrors
+	// ログに出すための元のエラー。レスポンスには含めない
+	cause error
+}
+
+func newAppError(c code, httpStatus int) *appError {
+	return &appError{
+		code:       c,
+		httpStatus: httpStatus,
+		messageKey: "errors." + c.value(),
+	}
 }
 
 var (
-	InvalidParameter    = &appError{code: InvalidParameterCode, httpStatus: http.StatusBadRequest}
-	InternalServerError = &appError{code: InternalServerErrorCode, httpStatus: http.StatusInternalServerError}
-	TodoNotFound        = &appError{code: TodoNotFoundCode, httpStatus: http.StatusNotFound}
+	InvalidParameter      = newAppError(InvalidParameterCode, http.StatusBadRequest)
+	InternalServerError   = newAppError(InternalServerErrorCode, http.StatusInternalServerError)
+	TodoNotFound          = newAppError(TodoNotFoundCode, http.StatusNotFound)
+	TagNotFound           = newAppError(TagNotFoundCode, http.StatusNotFound)
+	ChecklistItemNotFound = newAppError(ChecklistItemNotFoundCode, http.StatusNotFound)
+	UnsupportedMediaType  = newAppError(UnsupportedMediaTypeCode, http.StatusUnsupportedMediaType)
+	PreconditionFailed    = newAppError(PreconditionFailedCode, http.StatusPreconditionFailed)
+
+	// 認証
+	Unauthorized       = newAppError(UnauthorizedCode, http.StatusUnauthorized)
+	InvalidCredentials = newAppError(InvalidCredentialsCode, http.StatusUnauthorized)
+	UserNotFound       = newAppError(UserNotFoundCode, http.StatusNotFound)
+
+	// プロジェクトの権限
+	Forbidden            = newAppError(ForbiddenCode, http.StatusForbidden)
+	ProjectNotFound      = newAppError(ProjectNotFoundCode, http.StatusNotFound)
+	ProjectOwnerRequired = newAppError(ProjectOwnerRequiredCode, http.StatusConflict)
+
+	// APIキー
+	APIKeyNotFound = newAppError(APIKeyNotFoundCode, http.StatusNotFound)
+
+	// DBの制約違反・接続エラー
+	InvalidReference   = newAppError(InvalidReferenceCode, http.StatusBadRequest)
+	Conflict           = newAppError(ConflictCode, http.StatusConflict)
+	ServiceUnavailable = newAppError(ServiceUnavailableCode, http.StatusServiceUnavailable)
+
+	// Todo集約の不変条件違反
+	ImplementationDateAfterDueDate = newAppError(ImplementationDateAfterDueDateCode, http.StatusUnprocessableEntity)
+	DoneTodoDatesLocked            = newAppError(DoneTodoDatesLockedCode, http.StatusUnprocessableEntity)
+	InvalidStatusTransition        = newAppError(InvalidStatusTransitionCode, http.StatusConflict)
+	TooManyChecklistItems          = newAppError(TooManyChecklistItemsCode, http.StatusUnprocessableEntity)
+	InvalidChecklistOrder          = newAppError(InvalidChecklistOrderCode, http.StatusUnprocessableEntity)
 )
 
 func (e *appError) Error() string {
+	if e.cause != nil {
+		return e.code.value() + ": " + e.cause.Error()
+	}
+
+	return e.code.value()
+}
+
+// Is は詳細・原因の有無にかかわらずコードが一致すれば同じエラーとみなす
+func (e *appError) Is(target error) bool {
+	t, ok := target.(*appError)
+	if !ok {
+		return false
+	}
+
+	return e.code == t.code
+}
+
+func (e *appError) Unwrap() error {
+	return e.cause
+}
+
+// Wrap は原因のエラーを付与したコピーを返す。定義済みのエラー自体は変更しない。
+func (e *appError) Wrap(cause error) error {
+	c := *e
+	c.cause = cause
+	return &c
+}
+
+// WithDetails は項目ごとの詳細を付与したコピーを返す
+func (e *appError) WithDetails(details FieldErrors) error {
+	c := *e
+	c.details = details
+	return &c
+}
+
+// Code はクライアントが判定に使う変わらない識別子を返す
+func (e *appError) Code() string {
 	return e.code.value()
 }
 
+func (e *appError) MessageKey() string {
+	return e.messageKey
+}
+
 func (e *appError) StatusCode() int {
 	return e.httpStatus
 }
 
+func (e *appError) Details() FieldErrors {
+	return e.details
+}
+
 func AsAppError(err error) *appError {
 	var e *appError
 	if errors.As(err, &e) {
 		return e
 	}
 
-	// アサーションに失敗した場合InternalServerError
-	return &appError{
-		code:       InternalServerErrorCode,
-		httpStatus: http.StatusInternalServerError,
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return InvalidParameter
 	}
+
+	// アサーションに失敗した場合は元のエラーを原因としたInternalServerError
+	c := *InternalServerError
+	c.cause = err
+	return &c
 }
@@ -3,9 +3,33 @@ package apperrors
 type code string
 
 const (
-	InvalidParameterCode    code = "InvalidParameter"
-	InternalServerErrorCode code = "InternalServerError"
-	TodoNotFoundCode        code = "TodoNotFound"
+	InvalidParameterCode      code = "InvalidParameter"
+	InternalServerErrorCode   code = "InternalServerError"
+	TodoNotFoundCode          code = "TodoNotFound"
+	TagNotFoundCode           code = "TagNotFound"
+	ChecklistItemNotFoundCode code = "ChecklistItemNotFound"
+	UnsupportedMediaTypeCode  code = "UnsupportedMediaType"
+	PreconditionFailedCode    code = "PreconditionFailed"
+
+	UnauthorizedCode       code = "Unauthorized"
+	InvalidCredentialsCode code = "InvalidCredentials"
+	UserNotFoundCode       code = "UserNotFound"
+
+	ForbiddenCode            code = "Forbidden"
+	ProjectNotFoundCode      code = "ProjectNotFound"
+	ProjectOwnerRequiredCode code = "ProjectOwnerRequired"
+
+	APIKeyNotFoundCode code = "APIKeyNotFound"
+
+	InvalidReferenceCode   code = "InvalidReference"
+	ConflictCode           code = "Conflict"
+	ServiceUnavailableCode code = "ServiceUnavailable"
+
+	ImplementationDateAfterDueDateCode code = "ImplementationDateAfterDueDate"
+	DoneTodoDatesLockedCode            code = "DoneTodoDatesLocked"
+	InvalidStatusTransitionCode        code = "InvalidStatusTransition"
+	TooManyChecklistItemsCode          code = "TooManyChecklistItems"
+	InvalidChecklistOrderCode          code = "InvalidChecklistOrder"
 )
 
 func (c code) value() string {
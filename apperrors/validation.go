@@ -0,0 +1,66 @@
+package apperrors
+
+// ValidationError は値オブジェクトの生成時に違反したルールを表す。
+// どの項目の違反かは呼び出し側が FieldErrors.Add で付与する。
+type ValidationError struct {
+	Rule    string
+	Value   interface{}
+	Message string
+}
+
+func NewValidationError(rule string, value interface{}, message string) *ValidationError {
+	return &ValidationError{
+		Rule:    rule,
+		Value:   value,
+		Message: message,
+	}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == InvalidParameter
+}
+
+type FieldError struct {
+	Field   string      `json:"field"`
+	Rule    string      `json:"rule"`
+	Value   interface{} `json:"value"`
+	Message string      `json:"message"`
+}
+
+type FieldErrors []*FieldError
+
+// Add はerrがnilでなければfieldの違反として追加する
+func (fe *FieldErrors) Add(field string, err error) {
+	if err == nil {
+		return
+	}
+
+	if e, ok := err.(*ValidationError); ok {
+		*fe = append(*fe, &FieldError{
+			Field:   field,
+			Rule:    e.Rule,
+			Value:   e.Value,
+			Message: e.Message,
+		})
+		return
+	}
+
+	*fe = append(*fe, &FieldError{
+		Field:   field,
+		Rule:    "invalid",
+		Message: err.Error(),
+	})
+}
+
+// Err は違反がなければnil、あればInvalidParameterとして違反の一覧を返す
+func (fe FieldErrors) Err() error {
+	if len(fe) == 0 {
+		return nil
+	}
+
+	return InvalidParameter.WithDetails(fe)
+}
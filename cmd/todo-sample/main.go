@@ -1,13 +1,25 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"log"
+	"os"
 
+	"github.com/kazumakawahara/todo-sample/config"
 	"github.com/kazumakawahara/todo-sample/infrastructure/router"
 )
 
 func main() {
-	if err := router.Run(); err != nil {
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if err := router.Run(cfg); err != nil {
 		log.Fatalln(err)
 	}
 }
@@ -0,0 +1,435 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DriverMySQL  = "mysql"
+	DriverSQLite = "sqlite"
+	DriverMemory = "memory"
+)
+
+type Config struct {
+	Env    string
+	Server Server
+	DB     DB
+	CORS   CORS
+	Trash  Trash
+	Auth   Auth
+}
+
+type Server struct {
+	Host            string
+	Port            int
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
+	// 存在しないtodoの削除も成功とするDELETEのルート
+	IdempotentDeleteRoutes []string
+}
+
+// 冪等な削除を設定できるDELETEのルート
+const (
+	RouteDeleteTodo       = "/todos/{id}"
+	RouteBatchDeleteTodos = "/todos:batch"
+)
+
+var idempotentDeleteRoutes = []string{RouteDeleteTodo, RouteBatchDeleteTodos}
+
+func (s Server) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+// IdempotentDelete はrouteのDELETEで存在しないtodoの削除も成功とするかを返す
+func (s Server) IdempotentDelete(route string) bool {
+	return containsString(s.IdempotentDeleteRoutes, route)
+}
+
+type DB struct {
+	Driver          string
+	MySQLDSN        string
+	SQLitePath      string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	// 1リクエストあたりのクエリ実行の制限時間。0なら無制限
+	QueryTimeout time.Duration
+	// ユーザー管理の導入前に作成された所有者のないtodoと変更履歴を引き継ぐユーザーのメールアドレス。空なら引き継がない
+	LegacyTodoOwner string
+}
+
+type CORS struct {
+	AllowedOrigins []string
+}
+
+type Trash struct {
+	// ゴミ箱のtodoを完全に削除するまでの保持期間
+	Retention time.Duration
+}
+
+// トークンの署名鍵の最小のバイト数 (HS256の鍵長)
+const authTokenSecretMinBytes = 32
+
+type Auth struct {
+	// トークンの署名鍵。空なら起動ごとに生成するため、再起動すると発行済みのトークンは無効になる
+	TokenSecret string
+	// トークンの有効期間
+	TokenTTL time.Duration
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Env: "local",
+		Server: Server{
+			Port:            8080,
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 10 * time.Second,
+		},
+		DB: DB{
+			Driver:          DriverMySQL,
+			MySQLDSN:        "root:root@tcp(127.0.0.1:3306)/test_db",
+			SQLitePath:      "todo.db",
+			MaxOpenConns:    10,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 5 * time.Minute,
+			QueryTimeout:    5 * time.Second,
+		},
+		CORS: CORS{
+			AllowedOrigins: []string{"*"},
+		},
+		Trash: Trash{
+			Retention: 30 * 24 * time.Hour,
+		},
+		Auth: Auth{
+			TokenTTL: 24 * time.Hour,
+		},
+	}
+}
+
+// setting は1つの設定項目を表す。
+// key は設定ファイルのキー、環境変数名(GO_ENVのプレフィックス付き)、フラグ名の元になる。
+type setting struct {
+	key   string
+	usage string
+	set   func(c *Config, v string) error
+}
+
+var settings = []setting{
+	{"SERVER_HOST", "listen host", func(c *Config, v string) error {
+		c.Server.Host = v
+		return nil
+	}},
+	{"SERVER_PORT", "listen port", func(c *Config, v string) error {
+		return parseInt(v, &c.Server.Port)
+	}},
+	{"SERVER_READ_TIMEOUT", "http read timeout (e.g. 10s)", func(c *Config, v string) error {
+		return parseDuration(v, &c.Server.ReadTimeout)
+	}},
+	{"SERVER_WRITE_TIMEOUT", "http write timeout (e.g. 10s)", func(c *Config, v string) error {
+		return parseDuration(v, &c.Server.WriteTimeout)
+	}},
+	{"SERVER_IDLE_TIMEOUT", "http idle timeout (e.g. 60s)", func(c *Config, v string) error {
+		return parseDuration(v, &c.Server.IdleTimeout)
+	}},
+	{"SERVER_SHUTDOWN_TIMEOUT", "graceful shutdown timeout (e.g. 10s)", func(c *Config, v string) error {
+		return parseDuration(v, &c.Server.ShutdownTimeout)
+	}},
+	{"SERVER_IDEMPOTENT_DELETE_ROUTES", "comma separated list of DELETE routes that treat a missing todo as success (/todos/{id}, /todos:batch)", func(c *Config, v string) error {
+		c.Server.IdempotentDeleteRoutes = splitList(v)
+		return nil
+	}},
+	{"DB_DRIVER", "repository driver (mysql, sqlite, memory)", func(c *Config, v string) error {
+		c.DB.Driver = v
+		return nil
+	}},
+	{"MYSQL_DSN", "mysql data source name", func(c *Config, v string) error {
+		c.DB.MySQLDSN = v
+		return nil
+	}},
+	{"SQLITE_PATH", "sqlite database file path", func(c *Config, v string) error {
+		c.DB.SQLitePath = v
+		return nil
+	}},
+	{"DB_MAX_OPEN_CONNS", "maximum number of open db connections (0 is unlimited)", func(c *Config, v string) error {
+		return parseInt(v, &c.DB.MaxOpenConns)
+	}},
+	{"DB_MAX_IDLE_CONNS", "maximum number of idle db connections", func(c *Config, v string) error {
+		return parseInt(v, &c.DB.MaxIdleConns)
+	}},
+	{"DB_CONN_MAX_LIFETIME", "maximum lifetime of a db connection (0 is unlimited)", func(c *Config, v string) error {
+		return parseDuration(v, &c.DB.ConnMaxLifetime)
+	}},
+	{"DB_QUERY_TIMEOUT", "timeout for db queries of a request (0 is unlimited)", func(c *Config, v string) error {
+		return parseDuration(v, &c.DB.QueryTimeout)
+	}},
+	{"DB_LEGACY_TODO_OWNER", "email of the registered user who takes over todos created before user accounts (none if empty)", func(c *Config, v string) error {
+		c.DB.LegacyTodoOwner = strings.TrimSpace(v)
+		return nil
+	}},
+	{"CORS_ALLOWED_ORIGINS", "comma separated list of allowed origins", func(c *Config, v string) error {
+		c.CORS.AllowedOrigins = splitList(v)
+		return nil
+	}},
+	{"TRASH_RETENTION", "how long trashed todos are kept before purge (e.g. 720h)", func(c *Config, v string) error {
+		return parseDuration(v, &c.Trash.Retention)
+	}},
+	{"AUTH_TOKEN_SECRET", "secret key to sign access tokens (at least 32 bytes, random if empty)", func(c *Config, v string) error {
+		c.Auth.TokenSecret = v
+		return nil
+	}},
+	{"AUTH_TOKEN_TTL", "lifetime of access tokens (e.g. 24h)", func(c *Config, v string) error {
+		return parseDuration(v, &c.Auth.TokenTTL)
+	}},
+}
+
+// Load はデフォルト値 < 設定ファイル < 環境変数 < コマンドライン引数 の優先順位で設定を読み込む。
+// 環境変数は GO_ENV (デフォルト local) を大文字にしたプレフィックスを付けて参照する。(例: LOCAL_MYSQL_DSN)
+func Load(args []string) (*Config, error) {
+	cfg := defaultConfig()
+	if env := os.Getenv("GO_ENV"); env != "" {
+		cfg.Env = env
+	}
+	prefix := strings.ToUpper(cfg.Env) + "_"
+
+	fs := flag.NewFlagSet("todo-sample", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv(prefix+"CONFIG_FILE"), "optional config file (KEY=VALUE per line)")
+	flagValues := make(map[string]*string, len(settings))
+	for _, s := range settings {
+		flagValues[s.key] = fs.String(flagName(s.key), "", s.usage+" (env "+prefix+s.key+")")
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var errs []string
+	apply := func(source, key, value string) {
+		for _, s := range settings {
+			if s.key != key {
+				continue
+			}
+
+			if err := s.set(cfg, value); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: invalid value %q for %s: %v", source, value, key, err))
+			}
+
+			return
+		}
+	}
+
+	if *configFile != "" {
+		fileValues, err := readFile(*configFile)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+
+		for _, s := range settings {
+			if v, ok := fileValues[s.key]; ok {
+				apply(*configFile, s.key, v)
+			}
+		}
+
+		// MYSQL_DSN がなければ docker-compose 用の development.env の値から組み立てる
+		if _, ok := fileValues["MYSQL_DSN"]; !ok && fileValues["MYSQL_DATABASE"] != "" {
+			cfg.DB.MySQLDSN = mysqlDSNFromEnvFile(fileValues)
+		}
+	}
+
+	for _, s := range settings {
+		if v, ok := os.LookupEnv(prefix + s.key); ok {
+			apply("env "+prefix+s.key, s.key, v)
+		}
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if flagName(s.key) == f.Name {
+				apply("flag -"+f.Name, s.key, *flagValues[s.key])
+			}
+		}
+	})
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("config: %s", strings.Join(errs, "; "))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	var errs []string
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Sprintf("SERVER_PORT must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	for _, d := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"SERVER_READ_TIMEOUT", c.Server.ReadTimeout},
+		{"SERVER_WRITE_TIMEOUT", c.Server.WriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", c.Server.IdleTimeout},
+		{"DB_CONN_MAX_LIFETIME", c.DB.ConnMaxLifetime},
+		{"DB_QUERY_TIMEOUT", c.DB.QueryTimeout},
+		{"TRASH_RETENTION", c.Trash.Retention},
+	} {
+		if d.value < 0 {
+			errs = append(errs, fmt.Sprintf("%s must not be negative, got %s", d.key, d.value))
+		}
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Sprintf("SERVER_SHUTDOWN_TIMEOUT must be positive, got %s", c.Server.ShutdownTimeout))
+	}
+	for _, route := range c.Server.IdempotentDeleteRoutes {
+		if !containsString(idempotentDeleteRoutes, route) {
+			errs = append(errs, fmt.Sprintf("SERVER_IDEMPOTENT_DELETE_ROUTES must be some of %s, got %q", strings.Join(idempotentDeleteRoutes, ", "), route))
+		}
+	}
+
+	switch c.DB.Driver {
+	case DriverMySQL:
+		if c.DB.MySQLDSN == "" {
+			errs = append(errs, "MYSQL_DSN is required when DB_DRIVER is mysql")
+		}
+	case DriverSQLite:
+		if c.DB.SQLitePath == "" {
+			errs = append(errs, "SQLITE_PATH is required when DB_DRIVER is sqlite")
+		}
+	case DriverMemory:
+		if c.DB.LegacyTodoOwner != "" {
+			errs = append(errs, "DB_LEGACY_TODO_OWNER cannot be used when DB_DRIVER is memory")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("DB_DRIVER must be one of mysql, sqlite, memory, got %q", c.DB.Driver))
+	}
+	if c.DB.MaxOpenConns < 0 {
+		errs = append(errs, fmt.Sprintf("DB_MAX_OPEN_CONNS must not be negative, got %d", c.DB.MaxOpenConns))
+	}
+	if c.DB.MaxIdleConns < 0 {
+		errs = append(errs, fmt.Sprintf("DB_MAX_IDLE_CONNS must not be negative, got %d", c.DB.MaxIdleConns))
+	}
+	if c.DB.MaxOpenConns > 0 && c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		errs = append(errs, fmt.Sprintf("DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)", c.DB.MaxIdleConns, c.DB.MaxOpenConns))
+	}
+
+	if len(c.CORS.AllowedOrigins) == 0 {
+		errs = append(errs, "CORS_ALLOWED_ORIGINS must contain at least one origin")
+	}
+
+	if c.Auth.TokenSecret != "" && len(c.Auth.TokenSecret) < authTokenSecretMinBytes {
+		errs = append(errs, fmt.Sprintf("AUTH_TOKEN_SECRET must be at least %d bytes", authTokenSecretMinBytes))
+	}
+	if c.Auth.TokenTTL <= 0 {
+		errs = append(errs, fmt.Sprintf("AUTH_TOKEN_TTL must be positive, got %s", c.Auth.TokenTTL))
+	}
+
+	if len(errs) > 0 {
+		return errors.New("config: " + strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func readFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+
+		values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func mysqlDSNFromEnvFile(values map[string]string) string {
+	user, password := values["MYSQL_USER"], values["MYSQL_PASSWORD"]
+	if user == "" {
+		user, password = "root", values["MYSQL_ROOT_PASSWORD"]
+	}
+
+	host := values["MYSQL_HOST"]
+	if host == "" {
+		host = "127.0.0.1:3306"
+	}
+
+	return fmt.Sprintf("%s:%s@tcp(%s)/%s", user, password, host, values["MYSQL_DATABASE"])
+}
+
+// SERVER_READ_TIMEOUT -> server-read-timeout
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+func parseInt(v string, dst *int) error {
+	i, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return errors.New("not an integer")
+	}
+
+	*dst = i
+	return nil
+}
+
+func parseDuration(v string, dst *time.Duration) error {
+	d, err := time.ParseDuration(strings.TrimSpace(v))
+	if err != nil {
+		return errors.New("not a duration")
+	}
+
+	*dst = d
+	return nil
+}
+
+func splitList(v string) []string {
+	var list []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			list = append(list, s)
+		}
+	}
+
+	return list
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
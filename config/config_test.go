@@ -0,0 +1,243 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfigFile はテスト用の設定ファイルを一時ディレクトリに作成し、パスを返す
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.env")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		env      map[string]string
+		args     []string
+		wantPort int
+	}{
+		{name: "正常系: デフォルト値", wantPort: 8080},
+		{name: "正常系: 設定ファイルがデフォルト値より優先", file: "SERVER_PORT=8081", wantPort: 8081},
+		{name: "正常系: 環境変数が設定ファイルより優先", file: "SERVER_PORT=8081", env: map[string]string{"TEST_SERVER_PORT": "8082"}, wantPort: 8082},
+		{name: "正常系: 引数が環境変数より優先", file: "SERVER_PORT=8081", env: map[string]string{"TEST_SERVER_PORT": "8082"}, args: []string{"-server-port", "8083"}, wantPort: 8083},
+		{name: "正常系: GO_ENVと異なるプレフィックスの環境変数は使わない", env: map[string]string{"LOCAL_SERVER_PORT": "8082", "SERVER_PORT": "8084"}, wantPort: 8080},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GO_ENV", "test")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeConfigFile(t, tt.file)}, args...)
+			}
+
+			cfg, err := Load(args)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Env != "test" {
+				t.Errorf("Env = %s, want test", cfg.Env)
+			}
+			if cfg.Server.Port != tt.wantPort {
+				t.Errorf("Server.Port = %d, want %d", cfg.Server.Port, tt.wantPort)
+			}
+		})
+	}
+}
+
+func TestLoad_ConfigFileFromEnv(t *testing.T) {
+	t.Setenv("GO_ENV", "test")
+	t.Setenv("TEST_CONFIG_FILE", writeConfigFile(t, "DB_DRIVER=memory"))
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.DB.Driver != DriverMemory {
+		t.Errorf("DB.Driver = %s, want %s", cfg.DB.Driver, DriverMemory)
+	}
+}
+
+func TestLoad_File(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		check   func(t *testing.T, cfg *Config)
+		wantErr string
+	}{
+		{
+			name: "正常系: 空行・コメント・引用符・前後の空白",
+			content: `
+# comment
+SERVER_HOST = "127.0.0.1"
+CORS_ALLOWED_ORIGINS='https://a.example.com, ,https://b.example.com'
+DB_QUERY_TIMEOUT=0
+UNKNOWN_KEY=ignored
+`,
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Host != "127.0.0.1" {
+					t.Errorf("Server.Host = %q, want 127.0.0.1", cfg.Server.Host)
+				}
+				if got := strings.Join(cfg.CORS.AllowedOrigins, " "); got != "https://a.example.com https://b.example.com" {
+					t.Errorf("CORS.AllowedOrigins = %q", got)
+				}
+				if cfg.DB.QueryTimeout != 0 {
+					t.Errorf("DB.QueryTimeout = %s, want 0", cfg.DB.QueryTimeout)
+				}
+			},
+		},
+		{
+			name:    "正常系: ルートごとの冪等な削除",
+			content: "SERVER_IDEMPOTENT_DELETE_ROUTES=/todos:batch",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.IdempotentDelete(RouteDeleteTodo) {
+					t.Errorf("IdempotentDelete(%s) = true, want false", RouteDeleteTodo)
+				}
+				if !cfg.Server.IdempotentDelete(RouteBatchDeleteTodos) {
+					t.Errorf("IdempotentDelete(%s) = false, want true", RouteBatchDeleteTodos)
+				}
+			},
+		},
+		{
+			name:    "正常系: MYSQL_DSNがなければdevelopment.envの値から組み立てる",
+			content: "MYSQL_DATABASE=todo\nMYSQL_USER=app\nMYSQL_PASSWORD=secret\nMYSQL_HOST=db:3306",
+			check: func(t *testing.T, cfg *Config) {
+				if want := "app:secret@tcp(db:3306)/todo"; cfg.DB.MySQLDSN != want {
+					t.Errorf("DB.MySQLDSN = %s, want %s", cfg.DB.MySQLDSN, want)
+				}
+			},
+		},
+		{
+			name:    "正常系: ユーザーがなければrootで接続する",
+			content: "MYSQL_DATABASE=todo\nMYSQL_ROOT_PASSWORD=root",
+			check: func(t *testing.T, cfg *Config) {
+				if want := "root:root@tcp(127.0.0.1:3306)/todo"; cfg.DB.MySQLDSN != want {
+					t.Errorf("DB.MySQLDSN = %s, want %s", cfg.DB.MySQLDSN, want)
+				}
+			},
+		},
+		{
+			name:    "正常系: MYSQL_DSNがあれば組み立てない",
+			content: "MYSQL_DSN=user:pass@tcp(host:3306)/db\nMYSQL_DATABASE=todo",
+			check: func(t *testing.T, cfg *Config) {
+				if want := "user:pass@tcp(host:3306)/db"; cfg.DB.MySQLDSN != want {
+					t.Errorf("DB.MySQLDSN = %s, want %s", cfg.DB.MySQLDSN, want)
+				}
+			},
+		},
+		{name: "異常系: KEY=VALUEでない行", content: "SERVER_PORT=8081\nINVALID", wantErr: ":2: expected KEY=VALUE"},
+		{name: "異常系: 不正な値", content: "SERVER_PORT=abc\nSERVER_READ_TIMEOUT=10", wantErr: `invalid value "abc" for SERVER_PORT`},
+		{name: "異常系: 検証エラー", content: "SERVER_PORT=70000", wantErr: "SERVER_PORT must be between 1 and 65535"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GO_ENV", "test")
+
+			cfg, err := Load([]string{"-config", writeConfigFile(t, tt.content)})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		wantErrs []string
+	}{
+		{name: "異常系: 設定ファイルがない", args: []string{"-config", filepath.Join(os.TempDir(), "not-exist.env")}, wantErrs: []string{"config: open"}},
+		{name: "異常系: 未知の引数", args: []string{"-unknown"}, wantErrs: []string{"flag provided but not defined"}},
+		{
+			name:     "異常系: 全ての不正な値を報告する",
+			env:      map[string]string{"TEST_SERVER_PORT": "abc", "TEST_DB_QUERY_TIMEOUT": "5"},
+			args:     []string{"-db-max-open-conns", "many"},
+			wantErrs: []string{"env TEST_SERVER_PORT", "env TEST_DB_QUERY_TIMEOUT", "flag -db-max-open-conns"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GO_ENV", "test")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			_, err := Load(tt.args)
+			if err == nil {
+				t.Fatal("Load() error = nil")
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Load() error = %v, want containing %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr string
+	}{
+		{name: "正常系: デフォルト値", modify: func(c *Config) {}},
+		{name: "正常系: メモリのドライバ", modify: func(c *Config) { c.DB.Driver = DriverMemory; c.DB.MySQLDSN = "" }},
+		{name: "正常系: 接続数の上限なし", modify: func(c *Config) { c.DB.MaxOpenConns = 0; c.DB.MaxIdleConns = 20 }},
+		{name: "異常系: ポートが範囲外", modify: func(c *Config) { c.Server.Port = 0 }, wantErr: "SERVER_PORT"},
+		{name: "異常系: 負のタイムアウト", modify: func(c *Config) { c.Server.ReadTimeout = -time.Second }, wantErr: "SERVER_READ_TIMEOUT must not be negative"},
+		{name: "異常系: シャットダウンの待ち時間が0", modify: func(c *Config) { c.Server.ShutdownTimeout = 0 }, wantErr: "SERVER_SHUTDOWN_TIMEOUT must be positive"},
+		{name: "正常系: 冪等な削除のルート", modify: func(c *Config) { c.Server.IdempotentDeleteRoutes = []string{RouteDeleteTodo, RouteBatchDeleteTodos} }},
+		{name: "異常系: 冪等な削除に未知のルート", modify: func(c *Config) { c.Server.IdempotentDeleteRoutes = []string{"/todos/trash"} }, wantErr: `SERVER_IDEMPOTENT_DELETE_ROUTES must be some of /todos/{id}, /todos:batch, got "/todos/trash"`},
+		{name: "異常系: 未知のドライバ", modify: func(c *Config) { c.DB.Driver = "postgres" }, wantErr: "DB_DRIVER must be one of"},
+		{name: "異常系: MySQLのDSNが空", modify: func(c *Config) { c.DB.MySQLDSN = "" }, wantErr: "MYSQL_DSN is required"},
+		{name: "異常系: SQLiteのパスが空", modify: func(c *Config) { c.DB.Driver = DriverSQLite; c.DB.SQLitePath = "" }, wantErr: "SQLITE_PATH is required"},
+		{name: "異常系: メモリにtodoの引き継ぎ先", modify: func(c *Config) { c.DB.Driver = DriverMemory; c.DB.LegacyTodoOwner = "a@example.com" }, wantErr: "DB_LEGACY_TODO_OWNER cannot be used"},
+		{name: "異常系: アイドル接続数が上限より多い", modify: func(c *Config) { c.DB.MaxOpenConns = 5; c.DB.MaxIdleConns = 10 }, wantErr: "DB_MAX_IDLE_CONNS (10) must not exceed"},
+		{name: "異常系: CORSのオリジンが空", modify: func(c *Config) { c.CORS.AllowedOrigins = nil }, wantErr: "CORS_ALLOWED_ORIGINS"},
+		{name: "異常系: 署名鍵が短い", modify: func(c *Config) { c.Auth.TokenSecret = strings.Repeat("a", 31) }, wantErr: "AUTH_TOKEN_SECRET must be at least 32 bytes"},
+		{name: "異常系: トークンの有効期間が0", modify: func(c *Config) { c.Auth.TokenTTL = 0 }, wantErr: "AUTH_TOKEN_TTL must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
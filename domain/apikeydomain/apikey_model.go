@@ -0,0 +1,120 @@
+package apikeydomain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+// KeyPrefix はAPIキーの先頭の文字列。ログインのトークンと見分けるために使う
+const KeyPrefix = "tsk_"
+
+// 最終利用日時はこの間隔より細かくは記録しない
+const lastUsedInterval = time.Minute
+
+// IsKey は文字列がAPIキーの形式かを返す
+func IsKey(s string) bool {
+	return strings.HasPrefix(s, KeyPrefix)
+}
+
+// Hash はハッシュ化したAPIキー。キーそのものは発行時にのみ返し、保存しない
+type Hash string
+
+func (h Hash) Value() string {
+	return string(h)
+}
+
+// APIKey はCIやスクリプトがログインせずに使うキー。発行したユーザーとしてtodoを操作する
+type APIKey struct {
+	id     ID
+	userID userdomain.ID
+	name   Name
+	scope  Scope
+	// 一覧でキーを見分けるためのキーの先頭部分
+	prefix     string
+	hash       Hash
+	createdAt  time.Time
+	lastUsedAt *time.Time
+	revokedAt  *time.Time
+}
+
+func NewAPIKeyWhenUnCreated(userID userdomain.ID, name Name, scope Scope, prefix string, hash Hash, createdAt time.Time) *APIKey {
+	return &APIKey{
+		userID:    userID,
+		name:      name,
+		scope:     scope,
+		prefix:    prefix,
+		hash:      hash,
+		createdAt: createdAt,
+	}
+}
+
+func NewAPIKey(
+	id ID,
+	userID userdomain.ID,
+	name Name,
+	scope Scope,
+	prefix string,
+	hash Hash,
+	createdAt time.Time,
+	lastUsedAt *time.Time,
+	revokedAt *time.Time,
+) *APIKey {
+	return &APIKey{
+		id:         id,
+		userID:     userID,
+		name:       name,
+		scope:      scope,
+		prefix:     prefix,
+		hash:       hash,
+		createdAt:  createdAt,
+		lastUsedAt: lastUsedAt,
+		revokedAt:  revokedAt,
+	}
+}
+
+func (k *APIKey) ID() ID {
+	return k.id
+}
+
+func (k *APIKey) UserID() userdomain.ID {
+	return k.userID
+}
+
+func (k *APIKey) Name() Name {
+	return k.name
+}
+
+func (k *APIKey) Scope() Scope {
+	return k.scope
+}
+
+func (k *APIKey) Prefix() string {
+	return k.prefix
+}
+
+func (k *APIKey) Hash() Hash {
+	return k.hash
+}
+
+func (k *APIKey) CreatedAt() time.Time {
+	return k.createdAt
+}
+
+func (k *APIKey) LastUsedAt() *time.Time {
+	return k.lastUsedAt
+}
+
+func (k *APIKey) RevokedAt() *time.Time {
+	return k.revokedAt
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.revokedAt != nil
+}
+
+// NeedsTouch は最終利用日時を更新するかを返す。リクエストごとの書き込みを避けるため一定の間隔をあける
+func (k *APIKey) NeedsTouch(now time.Time) bool {
+	return k.lastUsedAt == nil || now.Sub(*k.lastUsedAt) >= lastUsedInterval
+}
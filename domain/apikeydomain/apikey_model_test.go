@@ -0,0 +1,49 @@
+package apikeydomain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsKey(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want bool
+	}{
+		{name: "APIキー", s: "tsk_yy4630dihaW1jcGopqryzmR9fgeld8ATwEVH8lkWFjc", want: true},
+		{name: "ログインのトークン", s: "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.e30.sig", want: false},
+		{name: "空文字", s: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsKey(tt.s); got != tt.want {
+				t.Errorf("IsKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKey_NeedsTouch(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	justNow := now.Add(-30 * time.Second)
+	aMinuteAgo := now.Add(-time.Minute)
+
+	tests := []struct {
+		name       string
+		lastUsedAt *time.Time
+		want       bool
+	}{
+		{name: "未使用", lastUsedAt: nil, want: true},
+		{name: "間隔内", lastUsedAt: &justNow, want: false},
+		{name: "間隔を過ぎた", lastUsedAt: &aMinuteAgo, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := NewAPIKey(1, 1, "ci", ReadWrite, "tsk_abcdefgh", "hash", now.Add(-time.Hour), tt.lastUsedAt, nil)
+			if got := k.NeedsTouch(now); got != tt.want {
+				t.Errorf("NeedsTouch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package apikeydomain
+
+import "context"
+
+// APIキーで認証したリクエストは、ユーザーに加えてキーもコンテキストで引き回し、変更履歴に残す
+
+type apiKeyIDKey struct{}
+
+func ContextWithAPIKeyID(ctx context.Context, id ID) context.Context {
+	return context.WithValue(ctx, apiKeyIDKey{}, id)
+}
+
+// APIKeyIDFromContext はリクエストを認証したAPIキーのIDを返す。APIキーで認証していなければfalse
+func APIKeyIDFromContext(ctx context.Context) (ID, bool) {
+	id, ok := ctx.Value(apiKeyIDKey{}).(ID)
+	return id, ok
+}
@@ -0,0 +1,31 @@
+package apikeydomain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+// api_keys.name VARCHAR(50)
+const nameMaxLength = 50
+
+// Name はキーの用途を見分けるための名前
+type Name string
+
+func NewName(name string) (Name, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", apperrors.NewValidationError("required", name, "APIキー名は必須です")
+	}
+
+	if utf8.RuneCountInString(name) > nameMaxLength {
+		return "", apperrors.NewValidationError("maxLength", name, fmt.Sprintf("APIキー名は%d文字以内で入力してください", nameMaxLength))
+	}
+
+	return Name(name), nil
+}
+
+func (n Name) Value() string {
+	return string(n)
+}
@@ -0,0 +1,28 @@
+package apikeydomain
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+type Repository interface {
+	CreateAPIKey(ctx context.Context, key *APIKey) (ID, error)
+	// FetchAPIKeyByID はユーザーが発行したキーを返す。なければapperrors.APIKeyNotFoundを返す
+	FetchAPIKeyByID(ctx context.Context, id ID, userID userdomain.ID) (*APIKey, error)
+	// FetchAPIKeys はユーザーが発行したキーを失効したものも含めてID順に返す
+	FetchAPIKeys(ctx context.Context, userID userdomain.ID) ([]*APIKey, error)
+	// FetchAPIKeyByHash はハッシュが一致するキーを返す。なければapperrors.APIKeyNotFoundを返す
+	FetchAPIKeyByHash(ctx context.Context, hash Hash) (*APIKey, error)
+	// RevokeAPIKey はキーを失効させる。失効済みなら失効日時を変えない
+	RevokeAPIKey(ctx context.Context, id ID, revokedAt time.Time) error
+	UpdateLastUsedAt(ctx context.Context, id ID, lastUsedAt time.Time) error
+}
+
+// KeyManager はAPIキーを生成し、保存するハッシュを求める
+type KeyManager interface {
+	// Generate は新しいキーと、一覧でキーを見分けるためのキーの先頭部分を返す
+	Generate() (key string, prefix string, err error)
+	Hash(key string) Hash
+}
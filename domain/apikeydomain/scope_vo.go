@@ -0,0 +1,31 @@
+package apikeydomain
+
+import "github.com/kazumakawahara/todo-sample/apperrors"
+
+// Scope はキーで行える操作の範囲
+type Scope string
+
+const (
+	// ReadOnly はtodoの参照のみ行える
+	ReadOnly Scope = "read-only"
+	// ReadWrite はtodoの参照と変更を行える
+	ReadWrite Scope = "read-write"
+)
+
+func NewScope(scope string) (Scope, error) {
+	switch s := Scope(scope); s {
+	case ReadOnly, ReadWrite:
+		return s, nil
+	}
+
+	return "", apperrors.NewValidationError("oneOf", scope, "スコープはread-only, read-writeのいずれかで指定してください")
+}
+
+func (s Scope) Value() string {
+	return string(s)
+}
+
+// CanWrite は変更を伴う操作を行えるかを返す
+func (s Scope) CanWrite() bool {
+	return s == ReadWrite
+}
@@ -0,0 +1,33 @@
+package apikeydomain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   string
+		want    Scope
+		wantErr error
+	}{
+		{name: "正常系: 読み取り専用", scope: "read-only", want: ReadOnly},
+		{name: "正常系: 読み書き", scope: "read-write", want: ReadWrite},
+		{name: "異常系: 空文字", scope: "", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 存在しないスコープ", scope: "admin", wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewScope(tt.scope)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewScope() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
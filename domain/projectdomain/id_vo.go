@@ -0,0 +1,17 @@
+package projectdomain
+
+import "github.com/kazumakawahara/todo-sample/apperrors"
+
+type ID int
+
+func NewID(id int) (ID, error) {
+	if id <= 0 {
+		return 0, apperrors.NewValidationError("positive", id, "プロジェクトIDは1以上の整数で指定してください")
+	}
+
+	return ID(id), nil
+}
+
+func (i ID) Value() int {
+	return int(i)
+}
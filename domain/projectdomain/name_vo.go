@@ -0,0 +1,30 @@
+package projectdomain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+// projects.name VARCHAR(50)
+const nameMaxLength = 50
+
+type Name string
+
+func NewName(name string) (Name, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", apperrors.NewValidationError("required", name, "プロジェクト名は必須です")
+	}
+
+	if utf8.RuneCountInString(name) > nameMaxLength {
+		return "", apperrors.NewValidationError("maxLength", name, fmt.Sprintf("プロジェクト名は%d文字以内で入力してください", nameMaxLength))
+	}
+
+	return Name(name), nil
+}
+
+func (n Name) Value() string {
+	return string(n)
+}
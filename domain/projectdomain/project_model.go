@@ -0,0 +1,74 @@
+package projectdomain
+
+import (
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+// Project はtodoをまとめて複数のユーザーで共有するリスト
+type Project struct {
+	id        ID
+	name      Name
+	createdAt time.Time
+}
+
+func NewProjectWhenUnCreated(name Name, createdAt time.Time) *Project {
+	return &Project{
+		name:      name,
+		createdAt: createdAt,
+	}
+}
+
+func NewProject(id ID, name Name, createdAt time.Time) *Project {
+	return &Project{
+		id:        id,
+		name:      name,
+		createdAt: createdAt,
+	}
+}
+
+func (p *Project) ID() ID {
+	return p.id
+}
+
+func (p *Project) Name() Name {
+	return p.name
+}
+
+func (p *Project) CreatedAt() time.Time {
+	return p.createdAt
+}
+
+// Member はプロジェクトに参加しているユーザーとその権限
+type Member struct {
+	userID userdomain.ID
+	role   Role
+}
+
+func NewMember(userID userdomain.ID, role Role) *Member {
+	return &Member{
+		userID: userID,
+		role:   role,
+	}
+}
+
+func (m *Member) UserID() userdomain.ID {
+	return m.userID
+}
+
+func (m *Member) Role() Role {
+	return m.role
+}
+
+// EnsureOwnerRemains はメンバーの変更後もオーナーが1人以上残るかを検証する
+func EnsureOwnerRemains(members []*Member) error {
+	for _, member := range members {
+		if member.role == Owner {
+			return nil
+		}
+	}
+
+	return apperrors.ProjectOwnerRequired
+}
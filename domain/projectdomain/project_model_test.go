@@ -0,0 +1,28 @@
+package projectdomain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestEnsureOwnerRemains(t *testing.T) {
+	tests := []struct {
+		name    string
+		members []*Member
+		wantErr error
+	}{
+		{name: "正常系: オーナーが1人", members: []*Member{NewMember(1, Owner), NewMember(2, Viewer)}},
+		{name: "正常系: オーナーが複数", members: []*Member{NewMember(1, Owner), NewMember(2, Owner)}},
+		{name: "異常系: オーナーがいない", members: []*Member{NewMember(1, Editor), NewMember(2, Viewer)}, wantErr: apperrors.ProjectOwnerRequired},
+		{name: "異常系: メンバーがいない", members: nil, wantErr: apperrors.ProjectOwnerRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := EnsureOwnerRemains(tt.members); !errors.Is(err, tt.wantErr) {
+				t.Errorf("EnsureOwnerRemains() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
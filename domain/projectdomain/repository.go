@@ -0,0 +1,31 @@
+package projectdomain
+
+import (
+	"context"
+
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+// 権限の確認はユースケースで行い、リポジトリは指定されたユーザーのまま読み書きする。
+// TransactionManager のトランザクションの中で取得した権限とメンバーは、コミットするまで他から変更させない。
+type Repository interface {
+	// CreateProject はプロジェクトを作成し、作成したユーザーをオーナーとして追加する
+	CreateProject(ctx context.Context, project *Project, owner userdomain.ID) (ID, error)
+	FetchProjectByID(ctx context.Context, id ID) (*Project, error)
+	// FetchProjects はユーザーが参加しているプロジェクトをID順に返す
+	FetchProjects(ctx context.Context, userID userdomain.ID) ([]*Membership, error)
+	// FetchRole はユーザーのプロジェクトでの権限を返す。参加していなければapperrors.ProjectNotFoundを返す
+	FetchRole(ctx context.Context, id ID, userID userdomain.ID) (Role, error)
+	// FetchMembers はプロジェクトのメンバーをユーザーID順に返す
+	FetchMembers(ctx context.Context, id ID) ([]*Member, error)
+	// SaveMember はメンバーを追加する。既に参加していれば権限を変更する
+	SaveMember(ctx context.Context, id ID, member *Member) error
+	// DeleteMember はメンバーを削除する。参加していなければapperrors.UserNotFoundを返す
+	DeleteMember(ctx context.Context, id ID, userID userdomain.ID) error
+}
+
+// Membership はユーザーが参加しているプロジェクトとその権限
+type Membership struct {
+	Project *Project
+	Role    Role
+}
@@ -0,0 +1,38 @@
+package projectdomain
+
+import "github.com/kazumakawahara/todo-sample/apperrors"
+
+// Role はプロジェクトのメンバーの権限
+type Role string
+
+const (
+	// Owner はtodoの編集に加えてメンバーを管理できる
+	Owner Role = "owner"
+	// Editor はtodoを作成・編集できる
+	Editor Role = "editor"
+	// Viewer はtodoを参照のみできる
+	Viewer Role = "viewer"
+)
+
+func NewRole(role string) (Role, error) {
+	switch r := Role(role); r {
+	case Owner, Editor, Viewer:
+		return r, nil
+	}
+
+	return "", apperrors.NewValidationError("oneOf", role, "権限はowner, editor, viewerのいずれかで指定してください")
+}
+
+func (r Role) Value() string {
+	return string(r)
+}
+
+// CanEditTodos はプロジェクトのtodoを作成・変更できるかを返す
+func (r Role) CanEditTodos() bool {
+	return r == Owner || r == Editor
+}
+
+// CanManageMembers はメンバーを追加・変更・削除できるかを返す
+func (r Role) CanManageMembers() bool {
+	return r == Owner
+}
@@ -0,0 +1,55 @@
+package projectdomain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		want    Role
+		wantErr error
+	}{
+		{name: "正常系: オーナー", role: "owner", want: Owner},
+		{name: "正常系: 編集者", role: "editor", want: Editor},
+		{name: "正常系: 閲覧者", role: "viewer", want: Viewer},
+		{name: "異常系: 空文字", role: "", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 大文字", role: "Owner", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 存在しない権限", role: "admin", wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRole(tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewRole() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRole_CanEditTodos(t *testing.T) {
+	tests := []struct {
+		name string
+		role Role
+		want bool
+	}{
+		{name: "オーナー", role: Owner, want: true},
+		{name: "編集者", role: Editor, want: true},
+		{name: "閲覧者", role: Viewer, want: false},
+		{name: "メンバーでない", role: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.role.CanEditTodos(); got != tt.want {
+				t.Errorf("CanEditTodos() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
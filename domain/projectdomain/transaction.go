@@ -0,0 +1,10 @@
+package projectdomain
+
+import "context"
+
+// TransactionManager はメンバーの確認と変更を1つのトランザクションで実行する。
+// fnに渡すリポジトリの操作は全てトランザクションの中で行われ、
+// fnがエラーを返した場合(panicした場合も含む)はロールバックし、そうでなければコミットする。
+type TransactionManager interface {
+	Transaction(ctx context.Context, fn func(repo Repository) error) error
+}
@@ -0,0 +1,89 @@
+package tododomain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+const (
+	// checklist_items.title VARCHAR(100)
+	checklistItemTitleMaxLength = 100
+	// 1つのtodoに追加できるチェックリストの項目数
+	MaxChecklistItems = 100
+)
+
+type ChecklistItemID int
+
+func NewChecklistItemID(id int) (ChecklistItemID, error) {
+	if id <= 0 {
+		return 0, apperrors.NewValidationError("positive", id, "チェックリストの項目IDは1以上の整数で指定してください")
+	}
+
+	return ChecklistItemID(id), nil
+}
+
+func (i ChecklistItemID) Value() int {
+	return int(i)
+}
+
+type ChecklistItemTitle string
+
+func NewChecklistItemTitle(title string) (ChecklistItemTitle, error) {
+	if strings.TrimSpace(title) == "" {
+		return "", apperrors.NewValidationError("required", title, "チェックリストの項目名は必須です")
+	}
+
+	if utf8.RuneCountInString(title) > checklistItemTitleMaxLength {
+		return "", apperrors.NewValidationError("maxLength", title, fmt.Sprintf("チェックリストの項目名は%d文字以内で入力してください", checklistItemTitleMaxLength))
+	}
+
+	return ChecklistItemTitle(title), nil
+}
+
+func (t ChecklistItemTitle) Value() string {
+	return string(t)
+}
+
+// ChecklistItem はtodoのチェックリストの1項目。todoを通してのみ変更するため、変更時は新しい値に置き換える
+type ChecklistItem struct {
+	// 追加してから永続化するまでは0
+	id    ChecklistItemID
+	title ChecklistItemTitle
+	done  bool
+}
+
+func NewChecklistItem(id ChecklistItemID, title ChecklistItemTitle, done bool) *ChecklistItem {
+	return &ChecklistItem{
+		id:    id,
+		title: title,
+		done:  done,
+	}
+}
+
+func (i *ChecklistItem) ID() ChecklistItemID {
+	return i.id
+}
+
+func (i *ChecklistItem) Title() ChecklistItemTitle {
+	return i.title
+}
+
+func (i *ChecklistItem) IsDone() bool {
+	return i.done
+}
+
+// checklistValues はチェックリストを変更履歴に記録する値にする。追加直後の項目はIDがないため項目名と完了のみを記録する
+func checklistValues(items []*ChecklistItem) []map[string]interface{} {
+	values := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		values[i] = map[string]interface{}{
+			"title": item.title.Value(),
+			"done":  item.done,
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,174 @@
+package tododomain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewChecklistItemTitle(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		want    ChecklistItemTitle
+		wantErr error
+	}{
+		{name: "正常系", title: "牛乳を買う", want: ChecklistItemTitle("牛乳を買う")},
+		{name: "正常系: 100文字", title: strings.Repeat("あ", 100), want: ChecklistItemTitle(strings.Repeat("あ", 100))},
+		{name: "異常系: 空文字", title: "", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 空白のみ", title: " 　", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 101文字", title: strings.Repeat("あ", 101), wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewChecklistItemTitle(tt.title)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewChecklistItemTitle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewChecklistItemTitle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newChecklistTodo(t *testing.T, items ...*ChecklistItem) *Todo {
+	t.Helper()
+
+	todo := NewTodo(TodoParams{
+		ID:                 1,
+		Title:              "title",
+		ImplementationDate: ImplementationDate(date(2022, 4, 1)),
+		DueDate:            DueDate(date(2022, 4, 2)),
+		Status:             TODO,
+		Priority:           LOW,
+		Version:            InitialVersion,
+		Checklist:          items,
+	})
+
+	return todo
+}
+
+func checklistIDs(todo *Todo) []ChecklistItemID {
+	var ids []ChecklistItemID
+	for _, item := range todo.Checklist() {
+		ids = append(ids, item.ID())
+	}
+
+	return ids
+}
+
+func TestTodo_ReorderChecklist(t *testing.T) {
+	tests := []struct {
+		name        string
+		ids         []ChecklistItemID
+		want        []ChecklistItemID
+		wantChanged bool
+		wantErr     error
+	}{
+		{name: "正常系", ids: []ChecklistItemID{3, 1, 2}, want: []ChecklistItemID{3, 1, 2}, wantChanged: true},
+		{name: "正常系: 同じ順なら変更しない", ids: []ChecklistItemID{1, 2, 3}, want: []ChecklistItemID{1, 2, 3}},
+		{name: "異常系: 項目が足りない", ids: []ChecklistItemID{1, 2}, wantErr: apperrors.InvalidChecklistOrder},
+		{name: "異常系: 同じIDを複数指定", ids: []ChecklistItemID{1, 1, 2}, wantErr: apperrors.InvalidChecklistOrder},
+		{name: "異常系: 存在しないID", ids: []ChecklistItemID{1, 2, 4}, wantErr: apperrors.InvalidChecklistOrder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := newChecklistTodo(t, NewChecklistItem(1, "a", false), NewChecklistItem(2, "b", false), NewChecklistItem(3, "c", false))
+
+			err := todo.ReorderChecklist(tt.ids)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReorderChecklist() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got := checklistIDs(todo)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Checklist() ids = %v, want %v", got, tt.want)
+				}
+			}
+			if todo.IsChecklistChanged() != tt.wantChanged {
+				t.Errorf("IsChecklistChanged() = %v, want %v", todo.IsChecklistChanged(), tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestTodo_CheckChecklistItem(t *testing.T) {
+	todo := newChecklistTodo(t, NewChecklistItem(1, "a", false), NewChecklistItem(2, "b", true))
+
+	// 既に同じ状態なら変更しない
+	if err := todo.CheckChecklistItem(2, true); err != nil {
+		t.Fatal(err)
+	}
+	if todo.IsChecklistChanged() {
+		t.Fatalf("IsChecklistChanged() = true, want false")
+	}
+
+	if err := todo.CheckChecklistItem(1, true); err != nil {
+		t.Fatal(err)
+	}
+	if !todo.Checklist()[0].IsDone() || !todo.IsChecklistChanged() {
+		t.Errorf("CheckChecklistItem() did not mark the item as done")
+	}
+
+	if err := todo.CheckChecklistItem(3, true); !errors.Is(err, apperrors.ChecklistItemNotFound) {
+		t.Errorf("CheckChecklistItem() error = %v, wantErr %v", err, apperrors.ChecklistItemNotFound)
+	}
+}
+
+func TestTodo_AddAndRemoveChecklistItem(t *testing.T) {
+	todo := newChecklistTodo(t, NewChecklistItem(1, "a", true))
+
+	if err := todo.AddChecklistItem("b"); err != nil {
+		t.Fatal(err)
+	}
+	items := todo.Checklist()
+	if len(items) != 2 || items[1].ID() != 0 || items[1].Title() != "b" || items[1].IsDone() {
+		t.Fatalf("AddChecklistItem() checklist = %v", items)
+	}
+
+	if err := todo.RemoveChecklistItem(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(todo.Checklist()); got != 1 {
+		t.Errorf("len(Checklist()) = %d, want 1", got)
+	}
+
+	if err := todo.RemoveChecklistItem(1); !errors.Is(err, apperrors.ChecklistItemNotFound) {
+		t.Errorf("RemoveChecklistItem() error = %v, wantErr %v", err, apperrors.ChecklistItemNotFound)
+	}
+
+	full := make([]*ChecklistItem, MaxChecklistItems)
+	for i := range full {
+		full[i] = NewChecklistItem(ChecklistItemID(i+1), "item", false)
+	}
+	if err := newChecklistTodo(t, full...).AddChecklistItem("over"); !errors.Is(err, apperrors.TooManyChecklistItems) {
+		t.Errorf("AddChecklistItem() error = %v, wantErr %v", err, apperrors.TooManyChecklistItems)
+	}
+}
+
+func TestTodo_Progress(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []*ChecklistItem
+		want  int
+	}{
+		{name: "正常系: 項目なし", want: 0},
+		{name: "正常系: 未完了のみ", items: []*ChecklistItem{NewChecklistItem(1, "a", false)}, want: 0},
+		{name: "正常系: 切り捨て", items: []*ChecklistItem{NewChecklistItem(1, "a", true), NewChecklistItem(2, "b", false), NewChecklistItem(3, "c", false)}, want: 33},
+		{name: "正常系: 全て完了", items: []*ChecklistItem{NewChecklistItem(1, "a", true), NewChecklistItem(2, "b", true)}, want: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newChecklistTodo(t, tt.items...).Progress(); got != tt.want {
+				t.Errorf("Progress() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package tododomain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+)
+
+type SortField string
+
+const (
+	SortByID                 SortField = "id"
+	SortByTitle              SortField = "title"
+	SortByImplementationDate SortField = "implementationDate"
+	SortByDueDate            SortField = "dueDate"
+	SortByStatus             SortField = "status"
+	SortByPriority           SortField = "priority"
+)
+
+func NewSortField(field string) (SortField, error) {
+	if field == "" {
+		return SortByID, nil
+	}
+
+	switch sortField := SortField(field); sortField {
+	case SortByID, SortByTitle, SortByImplementationDate, SortByDueDate, SortByStatus, SortByPriority:
+		return sortField, nil
+	}
+
+	return "", apperrors.NewValidationError("oneOf", field, "ソート項目の値が不正です")
+}
+
+type SortOrder string
+
+const (
+	Asc  SortOrder = "asc"
+	Desc SortOrder = "desc"
+)
+
+func NewSortOrder(order string) (SortOrder, error) {
+	if order == "" {
+		return Asc, nil
+	}
+
+	switch sortOrder := SortOrder(order); sortOrder {
+	case Asc, Desc:
+		return sortOrder, nil
+	}
+
+	return "", apperrors.NewValidationError("oneOf", order, "並び順は asc または desc で指定してください")
+}
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 100
+)
+
+func NewLimit(limit int) (int, error) {
+	if limit == 0 {
+		return DefaultLimit, nil
+	}
+
+	if limit < 0 || limit > MaxLimit {
+		return 0, apperrors.NewValidationError("range", limit, fmt.Sprintf("取得件数は1〜%dで指定してください", MaxLimit))
+	}
+
+	return limit, nil
+}
+
+// Cursor は前のページの最後のtodoを表す。このtodoより後ろから取得する。
+type Cursor struct {
+	ID ID
+	// SortField に対応するソートキーの値 (Todo.SortKey の戻り値と同じ型)
+	Value interface{}
+}
+
+// Criteria はtodo一覧の検索条件。ゼロ値の項目では絞り込まない。
+type Criteria struct {
+	Statuses    []Status
+	Priorities  []Priority
+	DueDateFrom time.Time
+	DueDateTo   time.Time
+	Title       string // 部分一致
+	Memo        string // 部分一致
+	ProjectID   projectdomain.ID
+	TagIDs      []TagID
+	TagMatch    TagMatch
+	SortField   SortField
+	SortOrder   SortOrder
+	After       *Cursor
+	Limit       int
+	// trueならゴミ箱のtodoのみ、falseならゴミ箱にないtodoのみを対象にする
+	Trashed bool
+}
+
+// SortKey はfieldに対応するソートキーの値を返す
+func (t *Todo) SortKey(field SortField) interface{} {
+	switch field {
+	case SortByTitle:
+		return t.title.Value()
+	case SortByImplementationDate:
+		return t.implementationDate.Value()
+	case SortByDueDate:
+		return t.dueDate.Value()
+	case SortByStatus:
+		return t.status.Value()
+	case SortByPriority:
+		return t.priority.Value()
+	default:
+		return t.id.Value()
+	}
+}
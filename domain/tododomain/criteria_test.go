@@ -0,0 +1,81 @@
+package tododomain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewSortField(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		want    SortField
+		wantErr error
+	}{
+		{name: "正常系: 未指定", field: "", want: SortByID},
+		{name: "正常系: dueDate", field: "dueDate", want: SortByDueDate},
+		{name: "異常系: 不明な項目", field: "memo", wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSortField(tt.field)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewSortField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewSortField() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSortOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   string
+		want    SortOrder
+		wantErr error
+	}{
+		{name: "正常系: 未指定", order: "", want: Asc},
+		{name: "正常系: desc", order: "desc", want: Desc},
+		{name: "異常系: 不明な並び順", order: "DESC", wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSortOrder(tt.order)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewSortOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewSortOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		want    int
+		wantErr error
+	}{
+		{name: "正常系: 未指定", limit: 0, want: DefaultLimit},
+		{name: "正常系: 上限", limit: MaxLimit, want: MaxLimit},
+		{name: "異常系: 負の数", limit: -1, wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 上限超過", limit: MaxLimit + 1, wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewLimit(tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewLimit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
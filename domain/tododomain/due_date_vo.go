@@ -1,11 +1,17 @@
 package tododomain
 
-import "time"
+import (
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
 
 type DueDate time.Time
 
 func NewDueDate(date time.Time) (DueDate, error) {
-	// TODO: validation
+	if date.IsZero() {
+		return DueDate{}, apperrors.NewValidationError("required", date, "期限日は必須です")
+	}
 
 	return DueDate(date), nil
 }
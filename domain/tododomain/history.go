@@ -0,0 +1,75 @@
+package tododomain
+
+import "time"
+
+type HistoryAction string
+
+const (
+	HistoryCreate  HistoryAction = "create"
+	HistoryUpdate  HistoryAction = "update"
+	HistoryDelete  HistoryAction = "delete"
+	HistoryRestore HistoryAction = "restore"
+)
+
+func (a HistoryAction) Value() string {
+	return string(a)
+}
+
+// FieldChange は1項目の変更前後の値。作成時の変更前、復元時の変更後はnil
+type FieldChange struct {
+	Field  string
+	Before interface{}
+	After  interface{}
+}
+
+// History はtodoの1回の変更の記録
+type History struct {
+	todoID    ID
+	action    HistoryAction
+	changes   []FieldChange
+	actor     string
+	requestID string
+	createdAt time.Time
+}
+
+func NewHistory(
+	todoID ID,
+	action HistoryAction,
+	changes []FieldChange,
+	actor string,
+	requestID string,
+	createdAt time.Time,
+) *History {
+	return &History{
+		todoID:    todoID,
+		action:    action,
+		changes:   changes,
+		actor:     actor,
+		requestID: requestID,
+		createdAt: createdAt,
+	}
+}
+
+func (h *History) TodoID() ID {
+	return h.todoID
+}
+
+func (h *History) Action() HistoryAction {
+	return h.action
+}
+
+func (h *History) Changes() []FieldChange {
+	return h.changes
+}
+
+func (h *History) Actor() string {
+	return h.actor
+}
+
+func (h *History) RequestID() string {
+	return h.requestID
+}
+
+func (h *History) CreatedAt() time.Time {
+	return h.createdAt
+}
@@ -1,9 +1,13 @@
 package tododomain
 
+import "github.com/kazumakawahara/todo-sample/apperrors"
+
 type ID int
 
 func NewID(id int) (ID, error) {
-	// TODO: validation
+	if id <= 0 {
+		return 0, apperrors.NewValidationError("positive", id, "IDは1以上の整数で指定してください")
+	}
 
 	return ID(id), nil
 }
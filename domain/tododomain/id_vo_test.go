@@ -0,0 +1,32 @@
+package tododomain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		want    ID
+		wantErr error
+	}{
+		{name: "正常系", id: 1, want: ID(1)},
+		{name: "異常系: 0", id: 0, wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 負の数", id: -1, wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewID(tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,17 @@
 package tododomain
 
-import "time"
+import (
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
 
 type ImplementationDate time.Time
 
 func NewImplementationDate(date time.Time) (ImplementationDate, error) {
-	// TODO: validation
+	if date.IsZero() {
+		return ImplementationDate{}, apperrors.NewValidationError("required", date, "実施日は必須です")
+	}
 
 	return ImplementationDate(date), nil
 }
@@ -0,0 +1,34 @@
+package tododomain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewImplementationDate(t *testing.T) {
+	date := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		date    time.Time
+		want    ImplementationDate
+		wantErr error
+	}{
+		{name: "正常系", date: date, want: ImplementationDate(date)},
+		{name: "異常系: ゼロ値", date: time.Time{}, wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewImplementationDate(tt.date)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewImplementationDate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Value().Equal(tt.want.Value()) {
+				t.Errorf("NewImplementationDate() = %v, want %v", got.Value(), tt.want.Value())
+			}
+		})
+	}
+}
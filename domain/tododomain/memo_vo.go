@@ -1,9 +1,20 @@
 package tododomain
 
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+const memoMaxLength = 1000
+
 type Memo string
 
 func NewMemo(memo string) (Memo, error) {
-	// TODO: validation
+	if utf8.RuneCountInString(memo) > memoMaxLength {
+		return "", apperrors.NewValidationError("maxLength", memo, fmt.Sprintf("メモは%d文字以内で入力してください", memoMaxLength))
+	}
 
 	return Memo(memo), nil
 }
@@ -0,0 +1,34 @@
+package tododomain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewMemo(t *testing.T) {
+	tests := []struct {
+		name    string
+		memo    string
+		want    Memo
+		wantErr error
+	}{
+		{name: "正常系", memo: "牛乳を買う", want: Memo("牛乳を買う")},
+		{name: "正常系: 空文字", memo: "", want: Memo("")},
+		{name: "正常系: 1000文字", memo: strings.Repeat("あ", 1000), want: Memo(strings.Repeat("あ", 1000))},
+		{name: "異常系: 1001文字", memo: strings.Repeat("あ", 1001), wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMemo(tt.memo)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewMemo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewMemo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
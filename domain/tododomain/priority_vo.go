@@ -1,5 +1,7 @@
 package tododomain
 
+import "github.com/kazumakawahara/todo-sample/apperrors"
+
 type Priority uint
 
 const (
@@ -10,9 +12,12 @@ const (
 )
 
 func NewPriority(priorityID uint) (Priority, error) {
-	// TODO: validation
+	priority := Priority(priorityID)
+	if priority < UNKNOWN || priority > HIGH {
+		return 0, apperrors.NewValidationError("oneOf", priorityID, "優先度の値が不正です")
+	}
 
-	return Priority(priorityID), nil
+	return priority, nil
 }
 
 func (p Priority) Value() uint {
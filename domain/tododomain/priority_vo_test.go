@@ -0,0 +1,35 @@
+package tododomain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewPriority(t *testing.T) {
+	tests := []struct {
+		name       string
+		priorityID uint
+		want       Priority
+		wantErr    error
+	}{
+		{name: "正常系: UNKNOWN", priorityID: 1, want: UNKNOWN},
+		{name: "正常系: LOW", priorityID: 2, want: LOW},
+		{name: "正常系: MEDIUM", priorityID: 3, want: MEDIUM},
+		{name: "正常系: HIGH", priorityID: 4, want: HIGH},
+		{name: "異常系: 0", priorityID: 0, wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 範囲外", priorityID: 99, wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewPriority(tt.priorityID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewPriority() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewPriority() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,32 @@
 package tododomain
 
+import (
+	"context"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+)
+
+// ゴミ箱のtodoは FetchTrashedTodoByID と Criteria.Trashed でのみ取得できる
 type Repository interface {
-	CreateTodo(todo *Todo) (ID, error)
-	FetchTodoByID(id ID) (*Todo, error)
-	FetchTodos() ([]*Todo, error)
-	UpdateTodo(todo *Todo) (ID, error)
-	DeleteTodo(id ID) error
+	CreateTodo(ctx context.Context, todo *Todo) (ID, error)
+	FetchTodoByID(ctx context.Context, id ID) (*Todo, error)
+	FetchTrashedTodoByID(ctx context.Context, id ID) (*Todo, error)
+	FetchTodos(ctx context.Context, criteria *Criteria) ([]*Todo, error)
+	SearchTodos(ctx context.Context, query SearchQuery, limit int) ([]*SearchResult, error)
+	UpdateTodo(ctx context.Context, todo *Todo) (ID, error)
+	// PurgeTodos は個人のtodoとprojectIDsのプロジェクトのtodoのうち、deletedBeforeより前にゴミ箱に移動したものを完全に削除し、削除した件数を返す
+	PurgeTodos(ctx context.Context, deletedBefore time.Time, projectIDs []projectdomain.ID) (int, error)
+	CreateHistory(ctx context.Context, history *History) error
+	// FetchHistories はtodoの変更履歴を古い順に返す。完全に削除したtodoの履歴も残る
+	FetchHistories(ctx context.Context, todoID ID) ([]*History, error)
+
+	// CreateTodos は複数のtodoをまとめて作成し、引数と同じ順にIDを返す
+	CreateTodos(ctx context.Context, todos []*Todo) ([]ID, error)
+	// FetchTodosByIDs はゴミ箱にないtodoのうちIDが一致するものをID順に返す。存在しないIDは結果に含まれない。
+	// トランザクションの中で取得したtodoは、コミットするまで他から変更させない
+	FetchTodosByIDs(ctx context.Context, ids []ID) ([]*Todo, error)
+	// UpdateTodos は複数のtodoをまとめて更新する。バージョンが取得時から変わっていたtodoは更新せず、そのIDを返す
+	UpdateTodos(ctx context.Context, todos []*Todo) (stale []ID, err error)
+	CreateHistories(ctx context.Context, histories []*History) error
 }
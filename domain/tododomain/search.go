@@ -0,0 +1,43 @@
+package tododomain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+const searchQueryMaxLength = 100
+
+// SearchQuery はタイトル・メモの全文検索のキーワード
+type SearchQuery string
+
+func NewSearchQuery(query string) (SearchQuery, error) {
+	if strings.TrimSpace(query) == "" {
+		return "", apperrors.NewValidationError("required", query, "検索キーワードは必須です")
+	}
+
+	if utf8.RuneCountInString(query) > searchQueryMaxLength {
+		return "", apperrors.NewValidationError("maxLength", query, fmt.Sprintf("検索キーワードは%d文字以内で入力してください", searchQueryMaxLength))
+	}
+
+	return SearchQuery(query), nil
+}
+
+func (q SearchQuery) Value() string {
+	return string(q)
+}
+
+// Terms は空白区切りのキーワードを返す
+func (q SearchQuery) Terms() []string {
+	return strings.Fields(string(q))
+}
+
+type SearchResult struct {
+	Todo *Todo
+	// 関連度 (大きいほど関連が高い)
+	Score float64
+	// キーワード以外の語で一致を判定した場合の語 (MySQLのngramなど)。キーワードが含まれない項目の強調表示に使う
+	MatchedTerms []string
+}
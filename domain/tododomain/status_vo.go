@@ -1,5 +1,7 @@
 package tododomain
 
+import "github.com/kazumakawahara/todo-sample/apperrors"
+
 type Status uint
 
 const (
@@ -9,11 +11,31 @@ const (
 )
 
 func NewStatus(statusID uint) (Status, error) {
-	// TODO: validation
+	status := Status(statusID)
+	if status < TODO || status > DONE {
+		return 0, apperrors.NewValidationError("oneOf", statusID, "ステータスの値が不正です")
+	}
 
-	return Status(statusID), nil
+	return status, nil
 }
 
 func (s Status) Value() uint {
 	return uint(s)
 }
+
+// 許可されたstatusの遷移
+var statusTransitions = map[Status][]Status{
+	TODO:  {DOING, DONE},
+	DOING: {DONE},
+	DONE:  {TODO},
+}
+
+func (s Status) CanTransitionTo(to Status) bool {
+	for _, status := range statusTransitions[s] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,34 @@
+package tododomain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		statusID uint
+		want     Status
+		wantErr  error
+	}{
+		{name: "正常系: TODO", statusID: 1, want: TODO},
+		{name: "正常系: DOING", statusID: 2, want: DOING},
+		{name: "正常系: DONE", statusID: 3, want: DONE},
+		{name: "異常系: 0", statusID: 0, wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 範囲外", statusID: 4, wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewStatus(tt.statusID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
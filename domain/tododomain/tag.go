@@ -0,0 +1,155 @@
+package tododomain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+const (
+	// tags.name VARCHAR(30)
+	tagNameMaxLength = 30
+	// 1つのtodoに付けられるタグの数
+	MaxTagsPerTodo = 20
+)
+
+type TagID int
+
+func NewTagID(id int) (TagID, error) {
+	if id <= 0 {
+		return 0, apperrors.NewValidationError("positive", id, "タグIDは1以上の整数で指定してください")
+	}
+
+	return TagID(id), nil
+}
+
+func (i TagID) Value() int {
+	return int(i)
+}
+
+// NewTagIDs はtodoに付けるタグのIDを検証する。同じIDは1つにまとめる
+func NewTagIDs(ids []int) ([]TagID, error) {
+	tagIDs := make([]TagID, 0, len(ids))
+	seen := make(map[TagID]bool, len(ids))
+	for _, id := range ids {
+		tagID, err := NewTagID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if !seen[tagID] {
+			seen[tagID] = true
+			tagIDs = append(tagIDs, tagID)
+		}
+	}
+
+	if len(tagIDs) > MaxTagsPerTodo {
+		return nil, apperrors.NewValidationError("range", len(tagIDs), fmt.Sprintf("タグは%d個以内で指定してください", MaxTagsPerTodo))
+	}
+
+	return tagIDs, nil
+}
+
+type TagName string
+
+func NewTagName(name string) (TagName, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", apperrors.NewValidationError("required", name, "タグ名は必須です")
+	}
+
+	if utf8.RuneCountInString(name) > tagNameMaxLength {
+		return "", apperrors.NewValidationError("maxLength", name, fmt.Sprintf("タグ名は%d文字以内で入力してください", tagNameMaxLength))
+	}
+
+	return TagName(name), nil
+}
+
+func (n TagName) Value() string {
+	return string(n)
+}
+
+// Tag はtodoを自由に分類するためのラベル。ユーザーごとに作成し、名前はユーザーの中で重複できない
+type Tag struct {
+	id   TagID
+	name TagName
+}
+
+func NewTagWhenUnCreated(name TagName) *Tag {
+	return &Tag{
+		name: name,
+	}
+}
+
+func NewTag(id TagID, name TagName) *Tag {
+	return &Tag{
+		id:   id,
+		name: name,
+	}
+}
+
+func (t *Tag) ID() TagID {
+	return t.id
+}
+
+func (t *Tag) Name() TagName {
+	return t.name
+}
+
+func (t *Tag) Rename(name TagName) {
+	t.name = name
+}
+
+// sortTags はタグを名前順に並べたコピーを返す
+func sortTags(tags []*Tag) []*Tag {
+	sorted := make([]*Tag, len(tags))
+	copy(sorted, tags)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].name != sorted[j].name {
+			return sorted[i].name < sorted[j].name
+		}
+		return sorted[i].id < sorted[j].id
+	})
+
+	return sorted
+}
+
+// TagMatch は複数のタグで絞り込むときの条件
+type TagMatch string
+
+const (
+	// TagMatchAny はいずれかのタグが付いたtodoに絞り込む
+	TagMatchAny TagMatch = "any"
+	// TagMatchAll は全てのタグが付いたtodoに絞り込む
+	TagMatchAll TagMatch = "all"
+)
+
+func NewTagMatch(match string) (TagMatch, error) {
+	if match == "" {
+		return TagMatchAny, nil
+	}
+
+	switch tagMatch := TagMatch(match); tagMatch {
+	case TagMatchAny, TagMatchAll:
+		return tagMatch, nil
+	}
+
+	return "", apperrors.NewValidationError("oneOf", match, "タグの絞り込み条件は any または all で指定してください")
+}
+
+// TagRepository は認証したユーザーのタグを読み書きする
+type TagRepository interface {
+	// CreateTag はタグを作成する。同じ名前のタグがあればapperrors.Conflictを返す
+	CreateTag(ctx context.Context, tag *Tag) (TagID, error)
+	// FetchTags はユーザーのタグを名前順に返す
+	FetchTags(ctx context.Context) ([]*Tag, error)
+	// FetchTagByID はユーザーのタグを返す。なければapperrors.TagNotFoundを返す
+	FetchTagByID(ctx context.Context, id TagID) (*Tag, error)
+	// UpdateTag はタグの名前を変更する。同じ名前のタグがあればapperrors.Conflictを返す
+	UpdateTag(ctx context.Context, tag *Tag) error
+	// DeleteTag はタグを削除し、todoからも外す。なければapperrors.TagNotFoundを返す
+	DeleteTag(ctx context.Context, id TagID) error
+}
@@ -0,0 +1,92 @@
+package tododomain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewTagName(t *testing.T) {
+	tests := []struct {
+		name    string
+		tagName string
+		want    TagName
+		wantErr error
+	}{
+		{name: "正常系", tagName: "仕事", want: TagName("仕事")},
+		{name: "正常系: 30文字", tagName: strings.Repeat("あ", 30), want: TagName(strings.Repeat("あ", 30))},
+		{name: "異常系: 空文字", tagName: "", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 空白のみ", tagName: " 　", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 31文字", tagName: strings.Repeat("あ", 31), wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTagName(tt.tagName)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewTagName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewTagName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTagIDs(t *testing.T) {
+	tooMany := make([]int, MaxTagsPerTodo+1)
+	for i := range tooMany {
+		tooMany[i] = i + 1
+	}
+
+	tests := []struct {
+		name    string
+		ids     []int
+		want    []TagID
+		wantErr error
+	}{
+		{name: "正常系", ids: []int{3, 1}, want: []TagID{3, 1}},
+		{name: "正常系: 空", ids: []int{}, want: []TagID{}},
+		{name: "正常系: 重複は1つにまとめる", ids: []int{2, 2, 1, 2}, want: []TagID{2, 1}},
+		{name: "異常系: 0", ids: []int{1, 0}, wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 上限を超える", ids: tooMany, wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTagIDs(tt.ids)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewTagIDs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewTagIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTagMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		match   string
+		want    TagMatch
+		wantErr error
+	}{
+		{name: "正常系: any", match: "any", want: TagMatchAny},
+		{name: "正常系: all", match: "all", want: TagMatchAll},
+		{name: "正常系: 空ならany", match: "", want: TagMatchAny},
+		{name: "異常系: 未対応の値", match: "none", wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTagMatch(tt.match)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewTagMatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewTagMatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
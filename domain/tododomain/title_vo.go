@@ -1,17 +1,25 @@
 package tododomain
 
 import (
-	"github.com/kazumakawahara/todo-sample/apperrors"
+	"fmt"
+	"strings"
 	"unicode/utf8"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
 )
 
+// todos.title VARCHAR(50)
+const titleMaxLength = 50
+
 type Title string
 
 func NewTitle(title string) (Title, error) {
+	if strings.TrimSpace(title) == "" {
+		return "", apperrors.NewValidationError("required", title, "タイトルは必須です")
+	}
 
-	// TODO: validation
-	if utf8.RuneCountInString(title) > 10 {
-		return "", apperrors.InvalidParameter
+	if utf8.RuneCountInString(title) > titleMaxLength {
+		return "", apperrors.NewValidationError("maxLength", title, fmt.Sprintf("タイトルは%d文字以内で入力してください", titleMaxLength))
 	}
 
 	return Title(title), nil
@@ -0,0 +1,35 @@
+package tododomain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewTitle(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		want    Title
+		wantErr error
+	}{
+		{name: "正常系", title: "買い物", want: Title("買い物")},
+		{name: "正常系: 50文字", title: strings.Repeat("あ", 50), want: Title(strings.Repeat("あ", 50))},
+		{name: "異常系: 空文字", title: "", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 空白のみ", title: " 　", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 51文字", title: strings.Repeat("あ", 51), wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTitle(tt.title)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewTitle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewTitle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,13 @@
 package tododomain
 
+import (
+	"sort"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+)
+
 type Todo struct {
 	id                 ID
 	title              Title
@@ -8,6 +16,18 @@ type Todo struct {
 	status             Status
 	priority           Priority
 	memo               Memo
+	version            Version
+	// ゴミ箱に移動した日時。nilならゴミ箱にない
+	deletedAt *time.Time
+	// 所属するプロジェクト。0ならプロジェクトに属さない個人のtodo。作成後は変更できない
+	projectID projectdomain.ID
+	// 付けられたタグ。名前順に並べて保持する
+	tags []*Tag
+	// チェックリストの項目。並び順に保持する
+	checklist []*ChecklistItem
+
+	// 生成・取得後の変更内容。変更履歴に記録する
+	changes []FieldChange
 }
 
 func NewTodoWhenUnCreated(
@@ -16,33 +36,82 @@ func NewTodoWhenUnCreated(
 	dueDate DueDate,
 	priority Priority,
 	memo Memo,
-) *Todo {
-	return &Todo{
+	projectID projectdomain.ID,
+	tags []*Tag,
+) (*Todo, error) {
+	if err := validatePeriod(implementationDate, dueDate); err != nil {
+		return nil, err
+	}
+
+	t := &Todo{
 		title:              title,
 		implementationDate: implementationDate,
 		dueDate:            dueDate,
 		status:             TODO, // todo作成時はstatusは作業前
 		priority:           priority,
 		memo:               memo,
+		version:            InitialVersion,
+		projectID:          projectID,
+		tags:               sortTags(tags),
 	}
+
+	// 作成時は全ての項目を変更前なしの変更として記録する
+	t.recordChange("title", nil, title.Value())
+	t.recordChange("implementationDate", nil, implementationDate.Value())
+	t.recordChange("dueDate", nil, dueDate.Value())
+	t.recordChange("statusID", nil, t.status.Value())
+	t.recordChange("priorityID", nil, priority.Value())
+	t.recordChange("memo", nil, memo.Value())
+	if t.InProject() {
+		t.recordChange("projectID", nil, projectID.Value())
+	}
+	if len(tags) > 0 {
+		t.recordChange("tagIDs", nil, tagIDValues(t.tags))
+	}
+
+	return t, nil
 }
 
-func NewTodo(
-	id ID,
-	title Title,
-	implementationDate ImplementationDate,
-	dueDate DueDate,
-	status Status,
-	priority Priority,
-	memo Memo) *Todo {
+// TodoParams は永続化済みのtodoを復元するときの値。ゼロ値の項目は未設定として扱う
+type TodoParams struct {
+	ID                 ID
+	Title              Title
+	ImplementationDate ImplementationDate
+	DueDate            DueDate
+	Status             Status
+	Priority           Priority
+	Memo               Memo
+	Version            Version
+	// ゴミ箱に移動した日時。nilならゴミ箱にない
+	DeletedAt *time.Time
+	// 0ならプロジェクトに属さない個人のtodo
+	ProjectID projectdomain.ID
+	Tags      []*Tag
+	Checklist []*ChecklistItem
+}
+
+// NewTodo は永続化済みのtodoを復元する。
+// 期間の検証は作成・日付の変更時に行い、検証を入れる前に保存された値も読み出せるようにここでは検証しない。
+func NewTodo(p TodoParams) *Todo {
+	deletedAt := p.DeletedAt
+	if deletedAt != nil {
+		t := *deletedAt
+		deletedAt = &t
+	}
+
 	return &Todo{
-		id:                 id,
-		title:              title,
-		implementationDate: implementationDate,
-		dueDate:            dueDate,
-		status:             status,
-		priority:           priority,
-		memo:               memo,
+		id:                 p.ID,
+		title:              p.Title,
+		implementationDate: p.ImplementationDate,
+		dueDate:            p.DueDate,
+		status:             p.Status,
+		priority:           p.Priority,
+		memo:               p.Memo,
+		version:            p.Version,
+		deletedAt:          deletedAt,
+		projectID:          p.ProjectID,
+		tags:               sortTags(p.Tags),
+		checklist:          append([]*ChecklistItem{}, p.Checklist...),
 	}
 }
 
@@ -73,3 +142,357 @@ func (t *Todo) Priority() Priority {
 func (t *Todo) Memo() Memo {
 	return t.memo
 }
+
+func (t *Todo) Version() Version {
+	return t.version
+}
+
+// DeletedAt はゴミ箱に移動した日時を返す。ゴミ箱になければnil
+func (t *Todo) DeletedAt() *time.Time {
+	if t.deletedAt == nil {
+		return nil
+	}
+
+	deletedAt := *t.deletedAt
+	return &deletedAt
+}
+
+func (t *Todo) ProjectID() projectdomain.ID {
+	return t.projectID
+}
+
+// InProject はプロジェクトに属するtodoかを返す
+func (t *Todo) InProject() bool {
+	return t.projectID != 0
+}
+
+// Tags は付けられたタグを名前順に返す
+func (t *Todo) Tags() []*Tag {
+	tags := make([]*Tag, len(t.tags))
+	copy(tags, t.tags)
+	return tags
+}
+
+// Checklist はチェックリストの項目を並び順に返す
+func (t *Todo) Checklist() []*ChecklistItem {
+	checklist := make([]*ChecklistItem, len(t.checklist))
+	copy(checklist, t.checklist)
+	return checklist
+}
+
+// Progress はチェックリストの完了した項目の割合を0〜100の整数(切り捨て)で返す。項目がなければ0
+func (t *Todo) Progress() int {
+	if len(t.checklist) == 0 {
+		return 0
+	}
+
+	var done int
+	for _, item := range t.checklist {
+		if item.done {
+			done++
+		}
+	}
+
+	return done * 100 / len(t.checklist)
+}
+
+func (t *Todo) IsTrashed() bool {
+	return t.deletedAt != nil
+}
+
+// Trash はtodoをゴミ箱に移動する。ゴミ箱のtodoは復元するかretentionを過ぎて完全に削除されるまで残る。
+func (t *Todo) Trash(now time.Time) {
+	if t.deletedAt == nil {
+		t.recordChange("deletedAt", nil, now)
+		t.deletedAt = &now
+	}
+}
+
+// Restore はゴミ箱のtodoを元に戻す
+func (t *Todo) Restore() {
+	if t.deletedAt != nil {
+		t.recordChange("deletedAt", *t.deletedAt, nil)
+		t.deletedAt = nil
+	}
+}
+
+func (t *Todo) ChangeTitle(title Title) {
+	if t.title != title {
+		t.recordChange("title", t.title.Value(), title.Value())
+		t.title = title
+	}
+}
+
+// Reschedule は実施日と期限日を変更する。
+// 作業完了のtodoは日付を動かせない。
+func (t *Todo) Reschedule(implementationDate ImplementationDate, dueDate DueDate) error {
+	implementationDateChanged := !sameDate(t.implementationDate.Value(), implementationDate.Value())
+	dueDateChanged := !sameDate(t.dueDate.Value(), dueDate.Value())
+	// 日付を変えなければ、検証を入れる前に保存された期間のままでも他の項目を更新できる
+	if !implementationDateChanged && !dueDateChanged {
+		return nil
+	}
+
+	if t.status == DONE {
+		return apperrors.DoneTodoDatesLocked
+	}
+
+	if err := validatePeriod(implementationDate, dueDate); err != nil {
+		return err
+	}
+
+	if implementationDateChanged {
+		t.recordChange("implementationDate", t.implementationDate.Value(), implementationDate.Value())
+		t.implementationDate = implementationDate
+	}
+	if dueDateChanged {
+		t.recordChange("dueDate", t.dueDate.Value(), dueDate.Value())
+		t.dueDate = dueDate
+	}
+
+	return nil
+}
+
+// ChangeStatus は遷移表に従ってstatusを変更する。同じstatusへの変更は何もしない。
+// 作業完了からの差し戻しは Reopen でのみ行える。
+func (t *Todo) ChangeStatus(status Status) error {
+	if t.status == status {
+		return nil
+	}
+
+	if t.status == DONE {
+		return apperrors.InvalidStatusTransition
+	}
+
+	return t.transitionTo(status)
+}
+
+// Start は作業前のtodoを作業中にする
+func (t *Todo) Start() error {
+	return t.transitionTo(DOING)
+}
+
+// Complete は作業前・作業中のtodoを作業完了にする
+func (t *Todo) Complete() error {
+	return t.transitionTo(DONE)
+}
+
+// Reopen は作業完了のtodoを作業前に戻す
+func (t *Todo) Reopen() error {
+	return t.transitionTo(TODO)
+}
+
+func (t *Todo) transitionTo(status Status) error {
+	if !t.status.CanTransitionTo(status) {
+		return apperrors.InvalidStatusTransition
+	}
+
+	t.recordChange("statusID", t.status.Value(), status.Value())
+	t.status = status
+
+	return nil
+}
+
+func (t *Todo) ChangePriority(priority Priority) {
+	if t.priority != priority {
+		t.recordChange("priorityID", t.priority.Value(), priority.Value())
+		t.priority = priority
+	}
+}
+
+func (t *Todo) ChangeMemo(memo Memo) {
+	if t.memo != memo {
+		t.recordChange("memo", t.memo.Value(), memo.Value())
+		t.memo = memo
+	}
+}
+
+// ChangeTags は付けるタグを置き換える。順序のみの違いは変更としない
+func (t *Todo) ChangeTags(tags []*Tag) {
+	sorted := sortTags(tags)
+
+	before, after := tagIDValues(t.tags), tagIDValues(sorted)
+	if sameIDs(before, after) {
+		return
+	}
+
+	t.recordChange("tagIDs", before, after)
+	t.tags = sorted
+}
+
+// IsTagsChanged は生成・取得後に付けるタグが変わったかを返す
+func (t *Todo) IsTagsChanged() bool {
+	for _, change := range t.changes {
+		if change.Field == "tagIDs" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddChecklistItem はチェックリストの末尾に未完了の項目を追加する。IDは永続化時に採番する
+func (t *Todo) AddChecklistItem(title ChecklistItemTitle) error {
+	if len(t.checklist) >= MaxChecklistItems {
+		return apperrors.TooManyChecklistItems
+	}
+
+	t.changeChecklist(append(t.Checklist(), NewChecklistItem(0, title, false)))
+
+	return nil
+}
+
+// ReorderChecklist はチェックリストを指定したIDの順に並べ替える。全ての項目のIDを1回ずつ指定する
+func (t *Todo) ReorderChecklist(ids []ChecklistItemID) error {
+	if len(ids) != len(t.checklist) {
+		return apperrors.InvalidChecklistOrder
+	}
+
+	items := make(map[ChecklistItemID]*ChecklistItem, len(t.checklist))
+	for _, item := range t.checklist {
+		items[item.id] = item
+	}
+
+	reordered := make([]*ChecklistItem, len(ids))
+	changed := false
+	for i, id := range ids {
+		item, ok := items[id]
+		if !ok {
+			return apperrors.InvalidChecklistOrder
+		}
+		delete(items, id)
+
+		reordered[i] = item
+		changed = changed || item != t.checklist[i]
+	}
+
+	if changed {
+		t.changeChecklist(reordered)
+	}
+
+	return nil
+}
+
+// CheckChecklistItem はチェックリストの項目の完了を切り替える
+func (t *Todo) CheckChecklistItem(id ChecklistItemID, done bool) error {
+	i, err := t.checklistIndex(id)
+	if err != nil {
+		return err
+	}
+
+	item := t.checklist[i]
+	if item.done == done {
+		return nil
+	}
+
+	checklist := t.Checklist()
+	checklist[i] = NewChecklistItem(item.id, item.title, done)
+	t.changeChecklist(checklist)
+
+	return nil
+}
+
+// RemoveChecklistItem はチェックリストから項目を削除する
+func (t *Todo) RemoveChecklistItem(id ChecklistItemID) error {
+	i, err := t.checklistIndex(id)
+	if err != nil {
+		return err
+	}
+
+	checklist := t.Checklist()
+	t.changeChecklist(append(checklist[:i], checklist[i+1:]...))
+
+	return nil
+}
+
+// IsChecklistChanged は生成・取得後にチェックリストが変わったかを返す
+func (t *Todo) IsChecklistChanged() bool {
+	for _, change := range t.changes {
+		if change.Field == "checklist" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *Todo) checklistIndex(id ChecklistItemID) (int, error) {
+	for i, item := range t.checklist {
+		if item.id == id {
+			return i, nil
+		}
+	}
+
+	return 0, apperrors.ChecklistItemNotFound
+}
+
+func (t *Todo) changeChecklist(checklist []*ChecklistItem) {
+	t.recordChange("checklist", checklistValues(t.checklist), checklistValues(checklist))
+	t.checklist = checklist
+}
+
+// IsChanged は生成・取得後に値が変更されたかを返す。変更がなければ更新を省略できる。
+func (t *Todo) IsChanged() bool {
+	return len(t.changes) > 0
+}
+
+// Changes は生成・取得後の変更内容を項目ごとに返す
+func (t *Todo) Changes() []FieldChange {
+	changes := make([]FieldChange, len(t.changes))
+	copy(changes, t.changes)
+	return changes
+}
+
+// recordChange は変更内容を記録する。同じ項目を複数回変更した場合は最初の変更前の値を残す。
+func (t *Todo) recordChange(field string, before, after interface{}) {
+	for i := range t.changes {
+		if t.changes[i].Field == field {
+			t.changes[i].After = after
+			return
+		}
+	}
+
+	t.changes = append(t.changes, FieldChange{Field: field, Before: before, After: after})
+}
+
+// 実施日は期限日より後にできない(日付単位で比較する)
+func validatePeriod(implementationDate ImplementationDate, dueDate DueDate) error {
+	if truncateToDate(implementationDate.Value()).After(truncateToDate(dueDate.Value())) {
+		return apperrors.ImplementationDateAfterDueDate
+	}
+
+	return nil
+}
+
+// tagIDValues はタグのIDを昇順で返す。変更履歴に記録する
+func tagIDValues(tags []*Tag) []int {
+	ids := make([]int, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.id.Value()
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+func sameIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sameDate(a, b time.Time) bool {
+	return truncateToDate(a).Equal(truncateToDate(b))
+}
+
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
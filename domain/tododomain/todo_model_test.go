@@ -0,0 +1,313 @@
+package tododomain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNewTodoWhenUnCreated(t *testing.T) {
+	tests := []struct {
+		name               string
+		implementationDate time.Time
+		dueDate            time.Time
+		wantErr            error
+	}{
+		{name: "正常系", implementationDate: date(2022, 4, 1), dueDate: date(2022, 4, 2)},
+		{name: "正常系: 同日で時刻が後", implementationDate: date(2022, 4, 1).Add(15 * time.Hour), dueDate: date(2022, 4, 1)},
+		{name: "異常系: 実施日が期限日より後", implementationDate: date(2022, 4, 2), dueDate: date(2022, 4, 1), wantErr: apperrors.ImplementationDateAfterDueDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTodoWhenUnCreated("title", ImplementationDate(tt.implementationDate), DueDate(tt.dueDate), LOW, "", 0, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewTodoWhenUnCreated() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Status() != TODO {
+				t.Errorf("NewTodoWhenUnCreated() status = %v, want %v", got.Status(), TODO)
+			}
+		})
+	}
+}
+
+func TestTodo_Reschedule(t *testing.T) {
+	tests := []struct {
+		name               string
+		status             Status
+		implementationDate time.Time
+		dueDate            time.Time
+		wantErr            error
+	}{
+		{name: "正常系", status: TODO, implementationDate: date(2022, 4, 5), dueDate: date(2022, 4, 6)},
+		{name: "正常系: 作業完了で日付が変わらない", status: DONE, implementationDate: date(2022, 4, 1), dueDate: date(2022, 4, 2)},
+		{name: "異常系: 実施日が期限日より後", status: DOING, implementationDate: date(2022, 4, 3), dueDate: date(2022, 4, 2), wantErr: apperrors.ImplementationDateAfterDueDate},
+		{name: "異常系: 作業完了の日付変更", status: DONE, implementationDate: date(2022, 4, 1), dueDate: date(2022, 4, 3), wantErr: apperrors.DoneTodoDatesLocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := NewTodo(TodoParams{
+				ID:                 1,
+				Title:              "title",
+				ImplementationDate: ImplementationDate(date(2022, 4, 1)),
+				DueDate:            DueDate(date(2022, 4, 2)),
+				Status:             tt.status,
+				Priority:           LOW,
+				Version:            InitialVersion,
+			})
+
+			err := todo.Reschedule(ImplementationDate(tt.implementationDate), DueDate(tt.dueDate))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Reschedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !todo.DueDate().Value().Equal(tt.dueDate) {
+				t.Errorf("Reschedule() dueDate = %v, want %v", todo.DueDate().Value(), tt.dueDate)
+			}
+		})
+	}
+}
+
+func TestNewTodo_InvalidStoredPeriod(t *testing.T) {
+	tests := []struct {
+		name               string
+		implementationDate time.Time
+		dueDate            time.Time
+		wantErr            error
+	}{
+		{name: "正常系: 日付を変えない", implementationDate: date(2022, 4, 3), dueDate: date(2022, 4, 2)},
+		{name: "正常系: 正しい期間に直す", implementationDate: date(2022, 4, 1), dueDate: date(2022, 4, 2)},
+		{name: "異常系: 不正な期間のまま期限日を変える", implementationDate: date(2022, 4, 3), dueDate: date(2022, 4, 1), wantErr: apperrors.ImplementationDateAfterDueDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 検証を入れる前に保存された、実施日が期限日より後のtodoも復元できる
+			todo := NewTodo(TodoParams{
+				ID:                 1,
+				Title:              "title",
+				ImplementationDate: ImplementationDate(date(2022, 4, 3)),
+				DueDate:            DueDate(date(2022, 4, 2)),
+				Status:             TODO,
+				Priority:           LOW,
+				Version:            InitialVersion,
+			})
+
+			err := todo.Reschedule(ImplementationDate(tt.implementationDate), DueDate(tt.dueDate))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Reschedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTodo_StatusTransition(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     Status
+		transition func(*Todo) error
+		want       Status
+		wantErr    error
+	}{
+		{name: "Start: 作業前→作業中", status: TODO, transition: (*Todo).Start, want: DOING},
+		{name: "Start: 作業中→作業中", status: DOING, transition: (*Todo).Start, wantErr: apperrors.InvalidStatusTransition},
+		{name: "Start: 作業完了→作業中", status: DONE, transition: (*Todo).Start, wantErr: apperrors.InvalidStatusTransition},
+		{name: "Complete: 作業前→作業完了", status: TODO, transition: (*Todo).Complete, want: DONE},
+		{name: "Complete: 作業中→作業完了", status: DOING, transition: (*Todo).Complete, want: DONE},
+		{name: "Complete: 作業完了→作業完了", status: DONE, transition: (*Todo).Complete, wantErr: apperrors.InvalidStatusTransition},
+		{name: "Reopen: 作業完了→作業前", status: DONE, transition: (*Todo).Reopen, want: TODO},
+		{name: "Reopen: 作業中→作業前", status: DOING, transition: (*Todo).Reopen, wantErr: apperrors.InvalidStatusTransition},
+		{name: "ChangeStatus: 同じstatus", status: DONE, transition: func(t *Todo) error { return t.ChangeStatus(DONE) }, want: DONE},
+		{name: "ChangeStatus: 作業前→作業中", status: TODO, transition: func(t *Todo) error { return t.ChangeStatus(DOING) }, want: DOING},
+		{name: "ChangeStatus: 作業完了→作業前", status: DONE, transition: func(t *Todo) error { return t.ChangeStatus(TODO) }, wantErr: apperrors.InvalidStatusTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := NewTodo(TodoParams{
+				ID:                 1,
+				Title:              "title",
+				ImplementationDate: ImplementationDate(date(2022, 4, 1)),
+				DueDate:            DueDate(date(2022, 4, 2)),
+				Status:             tt.status,
+				Priority:           LOW,
+				Version:            InitialVersion,
+			})
+
+			err := tt.transition(todo)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("transition error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && todo.Status() != tt.want {
+				t.Errorf("status = %v, want %v", todo.Status(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTodo_IsChanged(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*Todo) error
+		want   bool
+	}{
+		{name: "変更なし", change: func(*Todo) error { return nil }, want: false},
+		{name: "同じ値で更新", change: func(t *Todo) error {
+			t.ChangeTitle("title")
+			t.ChangePriority(LOW)
+			t.ChangeMemo("")
+			if err := t.ChangeStatus(TODO); err != nil {
+				return err
+			}
+			return t.Reschedule(ImplementationDate(date(2022, 4, 1).Add(time.Hour)), DueDate(date(2022, 4, 2)))
+		}, want: false},
+		{name: "タイトルを変更", change: func(t *Todo) error { t.ChangeTitle("changed"); return nil }, want: true},
+		{name: "期限日を変更", change: func(t *Todo) error {
+			return t.Reschedule(ImplementationDate(date(2022, 4, 1)), DueDate(date(2022, 4, 3)))
+		}, want: true},
+		{name: "statusを変更", change: (*Todo).Start, want: true},
+		{name: "ゴミ箱に移動", change: func(t *Todo) error { t.Trash(date(2022, 4, 3)); return nil }, want: true},
+		{name: "ゴミ箱にないtodoを復元", change: func(t *Todo) error { t.Restore(); return nil }, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := NewTodo(TodoParams{
+				ID:                 1,
+				Title:              "title",
+				ImplementationDate: ImplementationDate(date(2022, 4, 1)),
+				DueDate:            DueDate(date(2022, 4, 2)),
+				Status:             TODO,
+				Priority:           LOW,
+				Version:            InitialVersion,
+			})
+
+			if err := tt.change(todo); err != nil {
+				t.Fatal(err)
+			}
+			if got := todo.IsChanged(); got != tt.want {
+				t.Errorf("IsChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrashAndRestore(t *testing.T) {
+	todo := NewTodo(TodoParams{
+		ID:                 1,
+		Title:              "title",
+		ImplementationDate: ImplementationDate(date(2022, 4, 1)),
+		DueDate:            DueDate(date(2022, 4, 2)),
+		Status:             TODO,
+		Priority:           LOW,
+		Version:            InitialVersion,
+	})
+
+	now := date(2022, 4, 3)
+	todo.Trash(now)
+	if !todo.IsTrashed() || !todo.DeletedAt().Equal(now) {
+		t.Fatalf("DeletedAt() = %v, want %v", todo.DeletedAt(), now)
+	}
+
+	// 既にゴミ箱にあれば日時は変わらない
+	todo.Trash(now.Add(time.Hour))
+	if !todo.DeletedAt().Equal(now) {
+		t.Errorf("DeletedAt() = %v, want %v", todo.DeletedAt(), now)
+	}
+
+	todo.Restore()
+	if todo.IsTrashed() || todo.DeletedAt() != nil {
+		t.Errorf("DeletedAt() = %v, want nil", todo.DeletedAt())
+	}
+}
+
+func TestTodo_Changes(t *testing.T) {
+	todo := NewTodo(TodoParams{
+		ID:                 1,
+		Title:              "title",
+		ImplementationDate: ImplementationDate(date(2022, 4, 1)),
+		DueDate:            DueDate(date(2022, 4, 2)),
+		Status:             TODO,
+		Priority:           LOW,
+		Version:            InitialVersion,
+	})
+
+	todo.ChangeTitle("changed")
+	todo.ChangeTitle("changed again")
+	if err := todo.Start(); err != nil {
+		t.Fatal(err)
+	}
+	todo.ChangePriority(LOW)
+
+	want := []FieldChange{
+		// 同じ項目の変更は最初の変更前の値と最後の変更後の値にまとめる
+		{Field: "title", Before: "title", After: "changed again"},
+		{Field: "statusID", Before: TODO.Value(), After: DOING.Value()},
+	}
+	got := todo.Changes()
+	if len(got) != len(want) {
+		t.Fatalf("Changes() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Changes()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNewTodoWhenUnCreated_Changes(t *testing.T) {
+	todo, err := NewTodoWhenUnCreated("title", ImplementationDate(date(2022, 4, 1)), DueDate(date(2022, 4, 2)), LOW, "memo", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 作成時は全ての項目を変更前なしで記録する
+	for _, change := range todo.Changes() {
+		if change.Before != nil {
+			t.Errorf("Changes() %s before = %v, want nil", change.Field, change.Before)
+		}
+	}
+	if got := len(todo.Changes()); got != 6 {
+		t.Errorf("len(Changes()) = %d, want 6", got)
+	}
+}
+
+func TestTodo_ChangeTags(t *testing.T) {
+	work, home := NewTag(1, "work"), NewTag(2, "home")
+
+	todo := NewTodo(TodoParams{
+		ID:                 1,
+		Title:              "title",
+		ImplementationDate: ImplementationDate(date(2022, 4, 1)),
+		DueDate:            DueDate(date(2022, 4, 2)),
+		Status:             TODO,
+		Priority:           LOW,
+		Version:            InitialVersion,
+		Tags:               []*Tag{work},
+	})
+
+	// 同じタグなら変更しない
+	todo.ChangeTags([]*Tag{work})
+	if todo.IsTagsChanged() {
+		t.Fatalf("IsTagsChanged() = true, want false")
+	}
+
+	todo.ChangeTags([]*Tag{work, home})
+	if !todo.IsTagsChanged() {
+		t.Fatalf("IsTagsChanged() = false, want true")
+	}
+
+	// タグは名前順に並べる
+	tags := todo.Tags()
+	if len(tags) != 2 || tags[0] != home || tags[1] != work {
+		t.Errorf("Tags() = %v, want [home work]", tags)
+	}
+
+	want := FieldChange{Field: "tagIDs", Before: []int{1}, After: []int{1, 2}}
+	got := todo.Changes()
+	if len(got) != 1 || got[0].Field != want.Field || !reflect.DeepEqual(got[0].Before, want.Before) || !reflect.DeepEqual(got[0].After, want.After) {
+		t.Errorf("Changes() = %v, want [%v]", got, want)
+	}
+}
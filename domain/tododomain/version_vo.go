@@ -0,0 +1,21 @@
+package tododomain
+
+import "github.com/kazumakawahara/todo-sample/apperrors"
+
+// Version は楽観的排他制御のためのバージョン。更新のたびに1ずつ増える。
+type Version uint
+
+// 作成時のバージョン
+const InitialVersion Version = 1
+
+func NewVersion(version uint) (Version, error) {
+	if version < uint(InitialVersion) {
+		return 0, apperrors.NewValidationError("positive", version, "バージョンは1以上の整数で指定してください")
+	}
+
+	return Version(version), nil
+}
+
+func (v Version) Value() uint {
+	return uint(v)
+}
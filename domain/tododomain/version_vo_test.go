@@ -0,0 +1,31 @@
+package tododomain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version uint
+		want    Version
+		wantErr error
+	}{
+		{name: "正常系", version: 1, want: InitialVersion},
+		{name: "異常系: 0", version: 0, wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewVersion(tt.version)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
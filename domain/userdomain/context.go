@@ -0,0 +1,17 @@
+package userdomain
+
+import "context"
+
+// 認証したユーザーはリクエストのコンテキストで引き回し、リポジトリはこのユーザーのデータのみを扱う
+
+type userIDKey struct{}
+
+func ContextWithUserID(ctx context.Context, id ID) context.Context {
+	return context.WithValue(ctx, userIDKey{}, id)
+}
+
+// UserIDFromContext は認証したユーザーのIDを返す。認証していなければfalse
+func UserIDFromContext(ctx context.Context) (ID, bool) {
+	id, ok := ctx.Value(userIDKey{}).(ID)
+	return id, ok
+}
@@ -0,0 +1,38 @@
+package userdomain
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+// users.email VARCHAR(255)
+const emailMaxLength = 255
+
+// Email はログインに使うメールアドレス。大文字・小文字を区別せず小文字で保持する
+type Email string
+
+func NewEmail(email string) (Email, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return "", apperrors.NewValidationError("required", email, "メールアドレスは必須です")
+	}
+
+	if utf8.RuneCountInString(email) > emailMaxLength {
+		return "", apperrors.NewValidationError("maxLength", email, fmt.Sprintf("メールアドレスは%d文字以内で入力してください", emailMaxLength))
+	}
+
+	// 表示名付きの形式 (Name <a@example.com>) は受け付けない
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return "", apperrors.NewValidationError("email", email, "メールアドレスの形式が不正です")
+	}
+
+	return Email(email), nil
+}
+
+func (e Email) Value() string {
+	return string(e)
+}
@@ -0,0 +1,36 @@
+package userdomain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		want    Email
+		wantErr error
+	}{
+		{name: "正常系", email: "taro@example.com", want: Email("taro@example.com")},
+		{name: "正常系: 大文字と前後の空白", email: " Taro@Example.COM ", want: Email("taro@example.com")},
+		{name: "異常系: 空文字", email: "", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: @がない", email: "taro.example.com", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 表示名付き", email: "Taro <taro@example.com>", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 256文字", email: strings.Repeat("a", 244) + "@example.com", wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEmail(tt.email)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewEmail() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewEmail() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package userdomain
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+const (
+	passwordMinLength = 8
+	// bcryptは72バイトを超える部分を無視するため、それ以上は受け付けない
+	passwordMaxBytes = 72
+)
+
+// Password は平文のパスワード。検証とハッシュ化にのみ使い、保存しない
+type Password string
+
+func NewPassword(password string) (Password, error) {
+	if utf8.RuneCountInString(password) < passwordMinLength {
+		return "", apperrors.NewValidationError("minLength", nil, fmt.Sprintf("パスワードは%d文字以上で入力してください", passwordMinLength))
+	}
+
+	if len(password) > passwordMaxBytes {
+		return "", apperrors.NewValidationError("maxLength", nil, fmt.Sprintf("パスワードは%dバイト以内で入力してください", passwordMaxBytes))
+	}
+
+	return Password(password), nil
+}
+
+func (p Password) Value() string {
+	return string(p)
+}
+
+// PasswordHash はハッシュ化したパスワード
+type PasswordHash string
+
+func (h PasswordHash) Value() string {
+	return string(h)
+}
@@ -0,0 +1,35 @@
+package userdomain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+func TestNewPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		want     Password
+		wantErr  error
+	}{
+		{name: "正常系: 8文字", password: "password", want: Password("password")},
+		{name: "正常系: 72バイト", password: strings.Repeat("a", 72), want: Password(strings.Repeat("a", 72))},
+		{name: "異常系: 7文字", password: "passwor", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 73バイト", password: strings.Repeat("a", 73), wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 8文字だが72バイトを超える", password: strings.Repeat("あ", 25), wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewPassword(tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewPassword() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
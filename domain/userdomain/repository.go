@@ -0,0 +1,27 @@
+package userdomain
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// CreateUser はユーザーを作成する。メールアドレスが登録済みならapperrors.Conflictを返す
+	CreateUser(ctx context.Context, user *User) (ID, error)
+	FetchUserByID(ctx context.Context, id ID) (*User, error)
+	// FetchUserByEmail はメールアドレスが一致するユーザーを返す。いなければapperrors.UserNotFoundを返す
+	FetchUserByEmail(ctx context.Context, email Email) (*User, error)
+}
+
+type PasswordHasher interface {
+	Hash(password Password) (PasswordHash, error)
+	// Matches はパスワードがハッシュと一致するかを返す
+	Matches(hash PasswordHash, password Password) bool
+}
+
+// TokenManager はログインしたユーザーに署名付きのトークンを発行し、検証する
+type TokenManager interface {
+	Issue(userID ID, now time.Time) (token string, expiresAt time.Time, err error)
+	// Verify はトークンの署名と有効期限を検証し、ユーザーIDを返す
+	Verify(token string, now time.Time) (ID, error)
+}
@@ -0,0 +1,43 @@
+package userdomain
+
+import "time"
+
+type User struct {
+	id           ID
+	email        Email
+	passwordHash PasswordHash
+	createdAt    time.Time
+}
+
+func NewUserWhenUnCreated(email Email, passwordHash PasswordHash, createdAt time.Time) *User {
+	return &User{
+		email:        email,
+		passwordHash: passwordHash,
+		createdAt:    createdAt,
+	}
+}
+
+func NewUser(id ID, email Email, passwordHash PasswordHash, createdAt time.Time) *User {
+	return &User{
+		id:           id,
+		email:        email,
+		passwordHash: passwordHash,
+		createdAt:    createdAt,
+	}
+}
+
+func (u *User) ID() ID {
+	return u.id
+}
+
+func (u *User) Email() Email {
+	return u.email
+}
+
+func (u *User) PasswordHash() PasswordHash {
+	return u.passwordHash
+}
+
+func (u *User) CreatedAt() time.Time {
+	return u.createdAt
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/apikeydomain"
+)
+
+const (
+	apiKeyRandomBytes = 32
+	// 一覧で見分けるために残すキーの先頭の文字数 (KeyPrefixを含む)
+	apiKeyPrefixLength = 12
+)
+
+// sha256KeyManager はAPIキーをSHA-256でハッシュ化する。
+// キーは十分な長さの乱数のため、パスワードと違い低速なハッシュにはせず、ハッシュでキーを引けるようにする。
+type sha256KeyManager struct{}
+
+func NewSHA256KeyManager() *sha256KeyManager {
+	return &sha256KeyManager{}
+}
+
+func (m *sha256KeyManager) Generate() (string, string, error) {
+	b := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", apperrors.InternalServerError.Wrap(err)
+	}
+
+	key := apikeydomain.KeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	return key, key[:apiKeyPrefixLength], nil
+}
+
+func (m *sha256KeyManager) Hash(key string) apikeydomain.Hash {
+	sum := sha256.Sum256([]byte(key))
+	return apikeydomain.Hash(hex.EncodeToString(sum[:]))
+}
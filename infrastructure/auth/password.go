@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+type bcryptPasswordHasher struct {
+	cost int
+}
+
+func NewBcryptPasswordHasher() *bcryptPasswordHasher {
+	return &bcryptPasswordHasher{cost: bcrypt.DefaultCost}
+}
+
+func (h *bcryptPasswordHasher) Hash(password userdomain.Password) (userdomain.PasswordHash, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password.Value()), h.cost)
+	if err != nil {
+		return "", apperrors.InternalServerError.Wrap(err)
+	}
+
+	return userdomain.PasswordHash(hash), nil
+}
+
+func (h *bcryptPasswordHasher) Matches(hash userdomain.PasswordHash, password userdomain.Password) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash.Value()), []byte(password.Value())) == nil
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+func TestBcryptPasswordHasher(t *testing.T) {
+	// テストの時間を抑えるため最小のコストでハッシュ化する
+	h := &bcryptPasswordHasher{cost: bcrypt.MinCost}
+
+	hash, err := h.Hash("password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(hash.Value(), "password123") {
+		t.Errorf("Hash() = %s, contains the password", hash)
+	}
+
+	// 同じパスワードでもソルトによりハッシュは異なる
+	other, err := h.Hash("password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == hash {
+		t.Errorf("Hash() returned the same hash twice: %s", hash)
+	}
+
+	tests := []struct {
+		name     string
+		hash     userdomain.PasswordHash
+		password userdomain.Password
+		want     bool
+	}{
+		{name: "正常系", hash: hash, password: "password123", want: true},
+		{name: "正常系: 別のソルトのハッシュ", hash: other, password: "password123", want: true},
+		{name: "異常系: パスワードが異なる", hash: hash, password: "password124", want: false},
+		{name: "異常系: 大文字小文字が異なる", hash: hash, password: "PASSWORD123", want: false},
+		{name: "異常系: 空のパスワード", hash: hash, password: "", want: false},
+		{name: "異常系: ハッシュが空", hash: "", password: "password123", want: false},
+		{name: "異常系: ハッシュでない", hash: "password123", password: "password123", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.Matches(tt.hash, tt.password); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBcryptPasswordHasher(t *testing.T) {
+	hash, err := NewBcryptPasswordHasher().Hash("password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cost, err := bcrypt.Cost([]byte(hash.Value()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != bcrypt.DefaultCost {
+		t.Errorf("cost = %d, want %d", cost, bcrypt.DefaultCost)
+	}
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+// トークンはHS256で署名したJWT (RFC 7519) とする。アルゴリズムは固定し、ヘッダーで切り替えさせない
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+type tokenClaims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type hmacTokenManager struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewHMACTokenManager(secret []byte, ttl time.Duration) *hmacTokenManager {
+	return &hmacTokenManager{
+		secret: secret,
+		ttl:    ttl,
+	}
+}
+
+func (m *hmacTokenManager) Issue(userID userdomain.ID, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(m.ttl)
+
+	claims, err := json.Marshal(&tokenClaims{
+		Subject:   strconv.Itoa(userID.Value()),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, apperrors.InternalServerError.Wrap(err)
+	}
+
+	signingInput := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	return signingInput + "." + m.sign(signingInput), time.Unix(expiresAt.Unix(), 0).UTC(), nil
+}
+
+func (m *hmacTokenManager) Verify(token string, now time.Time) (userdomain.ID, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return 0, apperrors.Unauthorized
+	}
+
+	signingInput := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(m.sign(signingInput))) {
+		return 0, apperrors.Unauthorized
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return 0, apperrors.Unauthorized
+	}
+
+	var claims tokenClaims
+	if err = json.Unmarshal(b, &claims); err != nil {
+		return 0, apperrors.Unauthorized
+	}
+
+	if now.Unix() >= claims.ExpiresAt {
+		return 0, apperrors.Unauthorized
+	}
+
+	id, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return 0, apperrors.Unauthorized
+	}
+
+	userID, err := userdomain.NewID(id)
+	if err != nil {
+		return 0, apperrors.Unauthorized
+	}
+
+	return userID, nil
+}
+
+func (m *hmacTokenManager) sign(signingInput string) string {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(signingInput))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
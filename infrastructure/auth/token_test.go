@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// signToken はヘッダーとクレームを指定してテスト用の鍵で署名したトークンを作る
+func signToken(header, claims string) string {
+	signingInput := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(claims))
+
+	mac := hmac.New(sha256.New, testSecret)
+	mac.Write([]byte(signingInput))
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestHMACTokenManager_IssueAndVerify(t *testing.T) {
+	m := NewHMACTokenManager(testSecret, time.Hour)
+	now := time.Date(2022, 4, 1, 9, 0, 0, 500, time.UTC)
+
+	token, expiresAt, err := m.Issue(1, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2022, 4, 1, 10, 0, 0, 0, time.UTC); !expiresAt.Equal(want) {
+		t.Errorf("Issue() expiresAt = %v, want %v", expiresAt, want)
+	}
+
+	header, claims, _ := strings.Cut(token, ".")
+	claims, signature, _ := strings.Cut(claims, ".")
+	otherSigned, _, err := NewHMACTokenManager([]byte("another secret"), time.Hour).Issue(1, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		now     time.Time
+		wantID  userdomain.ID
+		wantErr error
+	}{
+		{name: "正常系", token: token, now: now, wantID: 1},
+		{name: "正常系: 有効期限の直前", token: token, now: expiresAt.Add(-time.Second), wantID: 1},
+		{name: "異常系: 有効期限ちょうど", token: token, now: expiresAt, wantErr: apperrors.Unauthorized},
+		{name: "異常系: 有効期限切れ", token: token, now: expiresAt.Add(time.Hour), wantErr: apperrors.Unauthorized},
+		{name: "異常系: 署名の改ざん", token: header + "." + claims + "." + strings.Repeat("A", len(signature)), now: now, wantErr: apperrors.Unauthorized},
+		{name: "異常系: 署名なし", token: header + "." + claims + ".", now: now, wantErr: apperrors.Unauthorized},
+		{name: "異常系: クレームの改ざん", token: header + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"2","iat":0,"exp":9999999999}`)) + "." + signature, now: now, wantErr: apperrors.Unauthorized},
+		{name: "異常系: 異なる鍵で署名", token: otherSigned, now: now, wantErr: apperrors.Unauthorized},
+		{name: "異常系: alg=noneのヘッダー", token: signToken(`{"alg":"none","typ":"JWT"}`, `{"sub":"1","iat":0,"exp":9999999999}`), now: now, wantErr: apperrors.Unauthorized},
+		{name: "異常系: 別のアルゴリズムのヘッダー", token: signToken(`{"alg":"HS512","typ":"JWT"}`, `{"sub":"1","iat":0,"exp":9999999999}`), now: now, wantErr: apperrors.Unauthorized},
+		{name: "異常系: 区切りが足りない", token: header + "." + claims, now: now, wantErr: apperrors.Unauthorized},
+		{name: "異常系: 区切りが多い", token: token + ".extra", now: now, wantErr: apperrors.Unauthorized},
+		{name: "異常系: 空文字", token: "", now: now, wantErr: apperrors.Unauthorized},
+		{name: "異常系: クレームがJSONでない", token: signToken(`{"alg":"HS256","typ":"JWT"}`, `not json`), now: now, wantErr: apperrors.Unauthorized},
+		{name: "異常系: 有効期限なし", token: signToken(`{"alg":"HS256","typ":"JWT"}`, `{"sub":"1"}`), now: now, wantErr: apperrors.Unauthorized},
+		{name: "異常系: ユーザーIDが数値でない", token: signToken(`{"alg":"HS256","typ":"JWT"}`, `{"sub":"admin","exp":9999999999}`), now: now, wantErr: apperrors.Unauthorized},
+		{name: "異常系: ユーザーIDが0", token: signToken(`{"alg":"HS256","typ":"JWT"}`, `{"sub":"0","exp":9999999999}`), now: now, wantErr: apperrors.Unauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Verify(tt.token, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.wantID {
+				t.Errorf("Verify() = %d, want %d", got, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestHMACTokenManager_HeaderFormat(t *testing.T) {
+	// 自前で組み立てた同じヘッダー・クレームのトークンは検証を通る
+	token := signToken(`{"alg":"HS256","typ":"JWT"}`, `{"sub":"3","iat":0,"exp":9999999999}`)
+
+	got, err := NewHMACTokenManager(testSecret, time.Hour).Verify(token, time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 3 {
+		t.Errorf("Verify() = %d, want 3", got)
+	}
+}
@@ -1,13 +1,92 @@
 package datasource
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type Todo struct {
-	ID                 int       `db:"id"`
-	Title              string    `db:"title"`
-	ImplementationDate time.Time `db:"implementation_date"`
-	DueDate            time.Time `db:"due_date"`
-	StatusID           uint      `db:"status_id"`
-	PriorityID         uint      `db:"priority_id"`
-	Memo               string    `db:"memo"`
+	ID                 int           `db:"id"`
+	Title              string        `db:"title"`
+	ImplementationDate time.Time     `db:"implementation_date"`
+	DueDate            time.Time     `db:"due_date"`
+	StatusID           uint          `db:"status_id"`
+	PriorityID         uint          `db:"priority_id"`
+	Memo               string        `db:"memo"`
+	Version            uint          `db:"version"`
+	DeletedAt          *time.Time    `db:"deleted_at"`
+	ProjectID          sql.NullInt64 `db:"project_id"`
+}
+
+type TodoSearchResult struct {
+	Todo
+	Score float64 `db:"score"`
+}
+
+type TodoHistory struct {
+	ID        int       `db:"id"`
+	TodoID    int       `db:"todo_id"`
+	Action    string    `db:"action"`
+	Changes   string    `db:"changes"` // HistoryChange の配列のJSON
+	Actor     string    `db:"actor"`
+	RequestID string    `db:"request_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type HistoryChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type User struct {
+	ID           int       `db:"id"`
+	Email        string    `db:"email"`
+	PasswordHash string    `db:"password_hash"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+type Project struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type ProjectMembership struct {
+	Project
+	Role string `db:"role"`
+}
+
+type ProjectMember struct {
+	UserID int    `db:"user_id"`
+	Role   string `db:"role"`
+}
+
+type APIKey struct {
+	ID         int        `db:"id"`
+	UserID     int        `db:"user_id"`
+	Name       string     `db:"name"`
+	Scope      string     `db:"scope"`
+	Prefix     string     `db:"prefix"`
+	KeyHash    string     `db:"key_hash"`
+	CreatedAt  time.Time  `db:"created_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+}
+
+type Tag struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+type TodoTag struct {
+	TodoID int `db:"todo_id"`
+	Tag
+}
+
+type ChecklistItem struct {
+	ID     int    `db:"id"`
+	TodoID int    `db:"todo_id"`
+	Title  string `db:"title"`
+	Done   bool   `db:"done"`
 }
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/apikeydomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/interfaces/presenter"
+	"github.com/kazumakawahara/todo-sample/usecase"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+)
+
+// NewAuthMiddlewareFunc は Authorization: Bearer のトークンを検証し、認証したユーザーをリクエストのコンテキストに設定する
+func NewAuthMiddlewareFunc(tokenManager userdomain.TokenManager) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="todo-sample"`)
+				presenter.ErrorJSON(w, r, apperrors.Unauthorized)
+				return
+			}
+
+			userID, err := tokenManager.Verify(token, time.Now())
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="todo-sample", error="invalid_token"`)
+				presenter.ErrorJSON(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(userdomain.ContextWithUserID(r.Context(), userID)))
+		})
+	}
+}
+
+// NewAPIKeyAuthMiddlewareFunc は Authorization: Bearer にログインのトークンに加えてAPIキーも受け付ける。
+// APIキーは先頭の apikeydomain.KeyPrefix で見分け、読み取り専用のキーでは参照以外のメソッドを拒否する。
+func NewAPIKeyAuthMiddlewareFunc(tokenManager userdomain.TokenManager, apiKeyUsecase usecase.APIKeyUsecase) func(http.Handler) http.Handler {
+	tokenAuth := NewAuthMiddlewareFunc(tokenManager)
+
+	return func(next http.Handler) http.Handler {
+		tokenNext := tokenAuth(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := bearerToken(r)
+			if !ok || !apikeydomain.IsKey(key) {
+				tokenNext.ServeHTTP(w, r)
+				return
+			}
+
+			out, err := apiKeyUsecase.Authenticate(r.Context(), &input.APIKeyAuthentication{
+				Key:   key,
+				Write: !isSafeMethod(r.Method),
+			})
+			if err != nil {
+				switch {
+				case errors.Is(err, apperrors.Unauthorized):
+					w.Header().Set("WWW-Authenticate", `Bearer realm="todo-sample", error="invalid_token"`)
+				case errors.Is(err, apperrors.Forbidden):
+					w.Header().Set("WWW-Authenticate", `Bearer realm="todo-sample", error="insufficient_scope"`)
+				}
+				presenter.ErrorJSON(w, r, err)
+				return
+			}
+
+			ctx := userdomain.ContextWithUserID(r.Context(), userdomain.ID(out.UserID))
+			ctx = apikeydomain.ContextWithAPIKeyID(ctx, apikeydomain.ID(out.ID))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// isSafeMethod は参照のみのメソッドかを返す
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	return false
+}
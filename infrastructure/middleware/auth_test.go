@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/domain/apikeydomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/auth"
+	"github.com/kazumakawahara/todo-sample/infrastructure/persistence"
+	"github.com/kazumakawahara/todo-sample/usecase"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+)
+
+// echoUserHandler はコンテキストに設定されたユーザーIDを返す
+var echoUserHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userdomain.UserIDFromContext(r.Context())
+	apiKeyID, _ := apikeydomain.APIKeyIDFromContext(r.Context())
+	fmt.Fprintf(w, "%d %d", userID, apiKeyID)
+})
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		want          string
+		wantOK        bool
+	}{
+		{name: "正常系", authorization: "Bearer token", want: "token", wantOK: true},
+		{name: "正常系: スキームの大文字小文字を区別しない", authorization: "bearer token", want: "token", wantOK: true},
+		{name: "正常系: 前後の空白", authorization: "Bearer   token ", want: "token", wantOK: true},
+		{name: "異常系: ヘッダーなし", authorization: "", wantOK: false},
+		{name: "異常系: トークンなし", authorization: "Bearer", wantOK: false},
+		{name: "異常系: 空のトークン", authorization: "Bearer  ", wantOK: false},
+		{name: "異常系: 別のスキーム", authorization: "Basic dXNlcjpwYXNz", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+
+			got, ok := bearerToken(r)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("bearerToken() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewAuthMiddlewareFunc(t *testing.T) {
+	tokenManager := auth.NewHMACTokenManager([]byte("0123456789abcdef0123456789abcdef"), time.Hour)
+	token, _, err := tokenManager.Issue(1, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, _, err := tokenManager.Issue(1, time.Now().Add(-2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := NewAuthMiddlewareFunc(tokenManager)(echoUserHandler)
+
+	tests := []struct {
+		name                string
+		authorization       string
+		wantStatus          int
+		wantBody            string
+		wantWWWAuthenticate string
+	}{
+		{name: "正常系", authorization: "Bearer " + token, wantStatus: http.StatusOK, wantBody: "1 0"},
+		{name: "異常系: ヘッダーなし", wantStatus: http.StatusUnauthorized, wantWWWAuthenticate: `Bearer realm="todo-sample"`},
+		{name: "異常系: 別のスキーム", authorization: "Token " + token, wantStatus: http.StatusUnauthorized, wantWWWAuthenticate: `Bearer realm="todo-sample"`},
+		{name: "異常系: 不正なトークン", authorization: "Bearer " + token + "x", wantStatus: http.StatusUnauthorized, wantWWWAuthenticate: `Bearer realm="todo-sample", error="invalid_token"`},
+		{name: "異常系: 有効期限切れ", authorization: "Bearer " + expired, wantStatus: http.StatusUnauthorized, wantWWWAuthenticate: `Bearer realm="todo-sample", error="invalid_token"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/todos", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, r)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tt.wantWWWAuthenticate {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, tt.wantWWWAuthenticate)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestNewAPIKeyAuthMiddlewareFunc(t *testing.T) {
+	tokenManager := auth.NewHMACTokenManager([]byte("0123456789abcdef0123456789abcdef"), time.Hour)
+	token, _, err := tokenManager.Issue(1, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(persistence.NewAPIKeyMemoryRepository(), auth.NewSHA256KeyManager())
+	ctx := userdomain.ContextWithUserID(context.Background(), 2)
+	readOnly, err := apiKeyUsecase.CreateAPIKey(ctx, &input.APIKey{Name: "ci", Scope: string(apikeydomain.ReadOnly)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	readWrite, err := apiKeyUsecase.CreateAPIKey(ctx, &input.APIKey{Name: "script", Scope: string(apikeydomain.ReadWrite)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := NewAPIKeyAuthMiddlewareFunc(tokenManager, apiKeyUsecase)(echoUserHandler)
+
+	tests := []struct {
+		name                string
+		method              string
+		authorization       string
+		wantStatus          int
+		wantBody            string
+		wantWWWAuthenticate string
+	}{
+		{name: "正常系: ログインのトークン", method: http.MethodPost, authorization: "Bearer " + token, wantStatus: http.StatusOK, wantBody: "1 0"},
+		{name: "正常系: 読み取り専用のキーで参照", method: http.MethodGet, authorization: "Bearer " + readOnly.Key, wantStatus: http.StatusOK, wantBody: fmt.Sprintf("2 %d", readOnly.ID)},
+		{name: "正常系: 読み書きできるキーで変更", method: http.MethodDelete, authorization: "Bearer " + readWrite.Key, wantStatus: http.StatusOK, wantBody: fmt.Sprintf("2 %d", readWrite.ID)},
+		{name: "異常系: 読み取り専用のキーで変更", method: http.MethodPost, authorization: "Bearer " + readOnly.Key, wantStatus: http.StatusForbidden, wantWWWAuthenticate: `Bearer realm="todo-sample", error="insufficient_scope"`},
+		{name: "異常系: 存在しないキー", method: http.MethodGet, authorization: "Bearer " + apikeydomain.KeyPrefix + "unknown", wantStatus: http.StatusUnauthorized, wantWWWAuthenticate: `Bearer realm="todo-sample", error="invalid_token"`},
+		{name: "異常系: ヘッダーなし", method: http.MethodGet, wantStatus: http.StatusUnauthorized, wantWWWAuthenticate: `Bearer realm="todo-sample"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/todos", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, r)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tt.wantWWWAuthenticate {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, tt.wantWWWAuthenticate)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
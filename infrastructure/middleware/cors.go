@@ -6,11 +6,12 @@ import (
 	"github.com/rs/cors"
 )
 
-func NewCorsMiddlewareFunc() func(http.Handler) http.Handler {
+func NewCorsMiddlewareFunc(allowedOrigins []string) func(http.Handler) http.Handler {
 	corsWrapper := cors.New(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
-		AllowedHeaders:   []string{"Origin", "Content-Type", "Accept", "Accept-Language"},
+		AllowedOrigins:   allowedOrigins,
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions},
+		AllowedHeaders:   []string{"Origin", "Content-Type", "Accept", "Accept-Language", "Authorization", "If-Match", "X-Request-ID"},
+		ExposedHeaders:   []string{"ETag", "X-Next-Cursor", "X-Request-ID"},
 		AllowCredentials: true,
 	})
 
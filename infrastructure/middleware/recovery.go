@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/interfaces/presenter"
+)
+
+// NewRecoveryMiddlewareFunc はハンドラのpanicをスタックトレースとともにログに出力し、500のJSONを返す
+func NewRecoveryMiddlewareFunc() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				// レスポンスの中断はnet/httpに任せる
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				log.Printf("panic: %v (request_id=%s %s %s)\n%s", rec, r.Header.Get(requestIDHeader), r.Method, r.URL.Path, debug.Stack())
+				presenter.ErrorJSON(w, r, apperrors.InternalServerError)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// NewRequestIDMiddlewareFunc はリクエストIDがなければ採番してリクエストに設定し、レスポンスにも返す
+func NewRequestIDMiddlewareFunc() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(requestIDHeader)
+			if requestID == "" || len(requestID) > 64 {
+				requestID = newRequestID()
+				r.Header.Set(requestIDHeader, requestID)
+			}
+
+			w.Header().Set(requestIDHeader, requestID)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,200 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/xerrors"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/apikeydomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/datasource"
+	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
+)
+
+type apiKeyRepository struct {
+	*rdb.MySQLHandler
+}
+
+func NewAPIKeyRepository(mysqlHandler *rdb.MySQLHandler) *apiKeyRepository {
+	return &apiKeyRepository{mysqlHandler}
+}
+
+func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, key *apikeydomain.APIKey) (apikeydomain.ID, error) {
+	return createAPIKey(ctx, r.Conn, key, key.CreatedAt())
+}
+
+func (r *apiKeyRepository) FetchAPIKeyByID(ctx context.Context, id apikeydomain.ID, userID userdomain.ID) (*apikeydomain.APIKey, error) {
+	return fetchAPIKey(ctx, r.Conn, "id = ? AND user_id = ?", id.Value(), userID.Value())
+}
+
+func (r *apiKeyRepository) FetchAPIKeys(ctx context.Context, userID userdomain.ID) ([]*apikeydomain.APIKey, error) {
+	return fetchAPIKeys(ctx, r.Conn, userID)
+}
+
+func (r *apiKeyRepository) FetchAPIKeyByHash(ctx context.Context, hash apikeydomain.Hash) (*apikeydomain.APIKey, error) {
+	return fetchAPIKey(ctx, r.Conn, "key_hash = ?", hash.Value())
+}
+
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, id apikeydomain.ID, revokedAt time.Time) error {
+	return revokeAPIKey(ctx, r.Conn, id, revokedAt)
+}
+
+func (r *apiKeyRepository) UpdateLastUsedAt(ctx context.Context, id apikeydomain.ID, lastUsedAt time.Time) error {
+	return updateAPIKeyLastUsedAt(ctx, r.Conn, id, lastUsedAt)
+}
+
+// 以下はMySQL・SQLiteで共通のAPIキーの読み書き。日時は呼び出し側でドライバの形式にして渡す
+
+func createAPIKey(ctx context.Context, conn sqlx.ExecerContext, key *apikeydomain.APIKey, createdAt interface{}) (apikeydomain.ID, error) {
+	query := `
+        INSERT INTO api_keys
+        (
+          user_id,
+          name,
+          scope,
+          prefix,
+          key_hash,
+          created_at
+        )
+        VALUES
+          (?, ?, ?, ?, ?, ?)`
+
+	result, err := conn.ExecContext(
+		ctx,
+		query,
+		key.UserID().Value(),
+		key.Name().Value(),
+		key.Scope().Value(),
+		key.Prefix(),
+		key.Hash().Value(),
+		createdAt,
+	)
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	idVo, err := apikeydomain.NewID(int(id))
+	if err != nil {
+		return 0, apperrors.InternalServerError.Wrap(err)
+	}
+
+	return idVo, nil
+}
+
+const apiKeyColumns = `
+            id,
+            user_id,
+            name,
+            scope,
+            prefix,
+            key_hash,
+            created_at,
+            last_used_at,
+            revoked_at`
+
+func fetchAPIKey(ctx context.Context, conn sqlx.QueryerContext, cond string, args ...interface{}) (*apikeydomain.APIKey, error) {
+	fetchQuery := `
+        SELECT` + apiKeyColumns + `
+        FROM
+            api_keys
+        WHERE
+            ` + cond
+
+	var apiKeyDto datasource.APIKey
+	if err := conn.QueryRowxContext(ctx, fetchQuery, args...).StructScan(&apiKeyDto); err != nil {
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.APIKeyNotFound
+		}
+
+		return nil, dbError(err)
+	}
+
+	return toAPIKeyDomain(apiKeyDto)
+}
+
+func fetchAPIKeys(ctx context.Context, conn sqlx.QueryerContext, userID userdomain.ID) ([]*apikeydomain.APIKey, error) {
+	fetchQuery := `
+        SELECT` + apiKeyColumns + `
+        FROM
+            api_keys
+        WHERE
+            user_id = ?
+        ORDER BY
+            id`
+
+	var apiKeysDto []datasource.APIKey
+	if err := sqlx.SelectContext(ctx, conn, &apiKeysDto, fetchQuery, userID.Value()); err != nil {
+		return nil, dbError(err)
+	}
+
+	apiKeys := make([]*apikeydomain.APIKey, len(apiKeysDto))
+	for i, apiKeyDto := range apiKeysDto {
+		apiKeyDm, err := toAPIKeyDomain(apiKeyDto)
+		if err != nil {
+			return nil, err
+		}
+
+		apiKeys[i] = apiKeyDm
+	}
+
+	return apiKeys, nil
+}
+
+func revokeAPIKey(ctx context.Context, conn sqlx.ExecerContext, id apikeydomain.ID, revokedAt interface{}) error {
+	if _, err := conn.ExecContext(ctx, "UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL", revokedAt, id.Value()); err != nil {
+		return dbError(err)
+	}
+
+	return nil
+}
+
+func updateAPIKeyLastUsedAt(ctx context.Context, conn sqlx.ExecerContext, id apikeydomain.ID, lastUsedAt interface{}) error {
+	if _, err := conn.ExecContext(ctx, "UPDATE api_keys SET last_used_at = ? WHERE id = ?", lastUsedAt, id.Value()); err != nil {
+		return dbError(err)
+	}
+
+	return nil
+}
+
+func toAPIKeyDomain(apiKeyDto datasource.APIKey) (*apikeydomain.APIKey, error) {
+	idVo, err := apikeydomain.NewID(apiKeyDto.ID)
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	userIDVo, err := userdomain.NewID(apiKeyDto.UserID)
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	nameVo, err := apikeydomain.NewName(apiKeyDto.Name)
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	scopeVo, err := apikeydomain.NewScope(apiKeyDto.Scope)
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	return apikeydomain.NewAPIKey(
+		idVo,
+		userIDVo,
+		nameVo,
+		scopeVo,
+		apiKeyDto.Prefix,
+		apikeydomain.Hash(apiKeyDto.KeyHash),
+		apiKeyDto.CreatedAt,
+		apiKeyDto.LastUsedAt,
+		apiKeyDto.RevokedAt,
+	), nil
+}
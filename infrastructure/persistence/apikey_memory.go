@@ -0,0 +1,142 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/apikeydomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+type apiKeyMemoryRepository struct {
+	mu      sync.RWMutex
+	lastID  int
+	apiKeys map[int]*apikeydomain.APIKey
+}
+
+func NewAPIKeyMemoryRepository() *apiKeyMemoryRepository {
+	return &apiKeyMemoryRepository{
+		apiKeys: make(map[int]*apikeydomain.APIKey),
+	}
+}
+
+func (r *apiKeyMemoryRepository) CreateAPIKey(ctx context.Context, key *apikeydomain.APIKey) (apikeydomain.ID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, k := range r.apiKeys {
+		if k.Hash() == key.Hash() {
+			return 0, apperrors.Conflict
+		}
+	}
+
+	r.lastID++
+
+	idVo, err := apikeydomain.NewID(r.lastID)
+	if err != nil {
+		return 0, apperrors.InternalServerError.Wrap(err)
+	}
+
+	r.apiKeys[idVo.Value()] = apikeydomain.NewAPIKey(
+		idVo,
+		key.UserID(),
+		key.Name(),
+		key.Scope(),
+		key.Prefix(),
+		key.Hash(),
+		key.CreatedAt(),
+		nil,
+		nil,
+	)
+
+	return idVo, nil
+}
+
+func (r *apiKeyMemoryRepository) FetchAPIKeyByID(ctx context.Context, id apikeydomain.ID, userID userdomain.ID) (*apikeydomain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.apiKeys[id.Value()]
+	if !ok || key.UserID() != userID {
+		return nil, apperrors.APIKeyNotFound
+	}
+
+	return key, nil
+}
+
+func (r *apiKeyMemoryRepository) FetchAPIKeys(ctx context.Context, userID userdomain.ID) ([]*apikeydomain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var keys []*apikeydomain.APIKey
+	for _, key := range r.apiKeys {
+		if key.UserID() == userID {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID() < keys[j].ID()
+	})
+
+	return keys, nil
+}
+
+func (r *apiKeyMemoryRepository) FetchAPIKeyByHash(ctx context.Context, hash apikeydomain.Hash) (*apikeydomain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.apiKeys {
+		if key.Hash() == hash {
+			return key, nil
+		}
+	}
+
+	return nil, apperrors.APIKeyNotFound
+}
+
+func (r *apiKeyMemoryRepository) RevokeAPIKey(ctx context.Context, id apikeydomain.ID, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.apiKeys[id.Value()]
+	if !ok || key.IsRevoked() {
+		return nil
+	}
+
+	r.apiKeys[id.Value()] = copyAPIKey(key, key.LastUsedAt(), &revokedAt)
+
+	return nil
+}
+
+func (r *apiKeyMemoryRepository) UpdateLastUsedAt(ctx context.Context, id apikeydomain.ID, lastUsedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.apiKeys[id.Value()]
+	if !ok {
+		return nil
+	}
+
+	r.apiKeys[id.Value()] = copyAPIKey(key, &lastUsedAt, key.RevokedAt())
+
+	return nil
+}
+
+// copyAPIKey は日時を変えたキーを返す。取得済みのキーを書き換えないよう置き換える
+func copyAPIKey(key *apikeydomain.APIKey, lastUsedAt, revokedAt *time.Time) *apikeydomain.APIKey {
+	return apikeydomain.NewAPIKey(
+		key.ID(),
+		key.UserID(),
+		key.Name(),
+		key.Scope(),
+		key.Prefix(),
+		key.Hash(),
+		key.CreatedAt(),
+		lastUsedAt,
+		revokedAt,
+	)
+}
@@ -0,0 +1,43 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/domain/apikeydomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
+)
+
+type apiKeySQLiteRepository struct {
+	*rdb.SQLiteHandler
+}
+
+func NewAPIKeySQLiteRepository(sqliteHandler *rdb.SQLiteHandler) *apiKeySQLiteRepository {
+	return &apiKeySQLiteRepository{sqliteHandler}
+}
+
+func (r *apiKeySQLiteRepository) CreateAPIKey(ctx context.Context, key *apikeydomain.APIKey) (apikeydomain.ID, error) {
+	createdAt := key.CreatedAt()
+	return createAPIKey(ctx, r.Conn, key, sqliteDateTime(&createdAt))
+}
+
+func (r *apiKeySQLiteRepository) FetchAPIKeyByID(ctx context.Context, id apikeydomain.ID, userID userdomain.ID) (*apikeydomain.APIKey, error) {
+	return fetchAPIKey(ctx, r.Conn, "id = ? AND user_id = ?", id.Value(), userID.Value())
+}
+
+func (r *apiKeySQLiteRepository) FetchAPIKeys(ctx context.Context, userID userdomain.ID) ([]*apikeydomain.APIKey, error) {
+	return fetchAPIKeys(ctx, r.Conn, userID)
+}
+
+func (r *apiKeySQLiteRepository) FetchAPIKeyByHash(ctx context.Context, hash apikeydomain.Hash) (*apikeydomain.APIKey, error) {
+	return fetchAPIKey(ctx, r.Conn, "key_hash = ?", hash.Value())
+}
+
+func (r *apiKeySQLiteRepository) RevokeAPIKey(ctx context.Context, id apikeydomain.ID, revokedAt time.Time) error {
+	return revokeAPIKey(ctx, r.Conn, id, sqliteDateTime(&revokedAt))
+}
+
+func (r *apiKeySQLiteRepository) UpdateLastUsedAt(ctx context.Context, id apikeydomain.ID, lastUsedAt time.Time) error {
+	return updateAPIKeyLastUsedAt(ctx, r.Conn, id, sqliteDateTime(&lastUsedAt))
+}
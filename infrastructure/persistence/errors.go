@@ -0,0 +1,66 @@
+package persistence
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"github.com/go-sql-driver/mysql"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+// MySQLのエラー番号
+const (
+	mysqlErrConnCountExceeded = 1040
+	mysqlErrDupEntry          = 1062
+	mysqlErrNoReferencedRow   = 1216
+	mysqlErrRowIsReferenced   = 1217
+	mysqlErrRowIsReferenced2  = 1451
+	mysqlErrNoReferencedRow2  = 1452
+	mysqlErrServerGone        = 2006
+	mysqlErrServerLost        = 2013
+)
+
+// dbError はDBドライバのエラーを制約違反・接続エラーごとのapperrorsに変換する
+func dbError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlErrNoReferencedRow, mysqlErrNoReferencedRow2, mysqlErrRowIsReferenced, mysqlErrRowIsReferenced2:
+			return apperrors.InvalidReference.Wrap(err)
+		case mysqlErrDupEntry:
+			return apperrors.Conflict.Wrap(err)
+		case mysqlErrServerGone, mysqlErrServerLost, mysqlErrConnCountExceeded:
+			return apperrors.ServiceUnavailable.Wrap(err)
+		}
+
+		return apperrors.InternalServerError.Wrap(err)
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return apperrors.InvalidReference.Wrap(err)
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return apperrors.Conflict.Wrap(err)
+		case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+			return apperrors.ServiceUnavailable.Wrap(err)
+		}
+
+		return apperrors.InternalServerError.Wrap(err)
+	}
+
+	// 接続が切れた・制限時間内に応答がない場合は一時的に利用できないとみなす
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return apperrors.ServiceUnavailable.Wrap(err)
+	}
+
+	return apperrors.InternalServerError.Wrap(err)
+}
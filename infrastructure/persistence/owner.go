@@ -0,0 +1,37 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+// ownerID は認証したユーザーのIDを返す。todoと変更履歴の読み書きは全てこのユーザーが参加しているものに限る
+func ownerID(ctx context.Context) (userdomain.ID, error) {
+	id, ok := userdomain.UserIDFromContext(ctx)
+	if !ok {
+		return 0, apperrors.Unauthorized
+	}
+
+	return id, nil
+}
+
+// 個人のtodoは所有者のみ、プロジェクトのtodoはプロジェクトのメンバーが読み書きできる。
+// 権限による書き込みの制限はユースケースで行う。いずれも引数に認証したユーザーのIDを2回渡す。
+const (
+	todoScopeCond = `(todos.project_id IS NULL AND todos.owner_id = ?
+            OR todos.project_id IN (SELECT project_id FROM project_members WHERE user_id = ?))`
+	historyScopeCond = `(todo_histories.project_id IS NULL AND todo_histories.owner_id = ?
+            OR todo_histories.project_id IN (SELECT project_id FROM project_members WHERE user_id = ?))`
+)
+
+// projectIDValue はプロジェクトに属さないtodoならNULLを返す
+func projectIDValue(projectID projectdomain.ID) interface{} {
+	if projectID == 0 {
+		return nil
+	}
+
+	return projectID.Value()
+}
@@ -0,0 +1,253 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/xerrors"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/datasource"
+	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
+)
+
+type projectRepository struct {
+	*rdb.MySQLHandler
+	tx *sqlx.Tx
+}
+
+func NewProjectRepository(mysqlHandler *rdb.MySQLHandler) *projectRepository {
+	return &projectRepository{MySQLHandler: mysqlHandler}
+}
+
+// ext はトランザクション中ならトランザクションを、そうでなければ接続を返す
+func (r *projectRepository) ext() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+
+	return r.Conn
+}
+
+func (r *projectRepository) CreateProject(ctx context.Context, project *projectdomain.Project, owner userdomain.ID) (projectdomain.ID, error) {
+	return createProject(ctx, r.Conn, project, owner, project.CreatedAt())
+}
+
+func (r *projectRepository) FetchProjectByID(ctx context.Context, id projectdomain.ID) (*projectdomain.Project, error) {
+	return fetchProjectByID(ctx, r.ext(), id)
+}
+
+func (r *projectRepository) FetchProjects(ctx context.Context, userID userdomain.ID) ([]*projectdomain.Membership, error) {
+	return fetchProjects(ctx, r.ext(), userID)
+}
+
+func (r *projectRepository) FetchRole(ctx context.Context, id projectdomain.ID, userID userdomain.ID) (projectdomain.Role, error) {
+	// トランザクション中は確認した権限をコミットまで変更させない
+	return fetchRole(ctx, r.ext(), id, userID, r.tx != nil)
+}
+
+func (r *projectRepository) FetchMembers(ctx context.Context, id projectdomain.ID) ([]*projectdomain.Member, error) {
+	return fetchMembers(ctx, r.ext(), id, r.tx != nil)
+}
+
+func (r *projectRepository) SaveMember(ctx context.Context, id projectdomain.ID, member *projectdomain.Member) error {
+	query := `
+        INSERT INTO project_members
+        (
+          project_id,
+          user_id,
+          role
+        )
+        VALUES
+          (?, ?, ?)
+        ON DUPLICATE KEY UPDATE
+          role = VALUES(role)`
+
+	if _, err := r.ext().ExecContext(ctx, query, id.Value(), member.UserID().Value(), member.Role().Value()); err != nil {
+		return dbError(err)
+	}
+
+	return nil
+}
+
+func (r *projectRepository) DeleteMember(ctx context.Context, id projectdomain.ID, userID userdomain.ID) error {
+	return deleteMember(ctx, r.ext(), id, userID)
+}
+
+// 以下はMySQL・SQLiteで共通のプロジェクトの読み書き
+
+// createProject はプロジェクトと作成したユーザーのメンバーを1つのトランザクションで登録する
+func createProject(ctx context.Context, conn *sqlx.DB, project *projectdomain.Project, owner userdomain.ID, createdAt interface{}) (projectdomain.ID, error) {
+	var idVo projectdomain.ID
+	err := runInTx(ctx, conn, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, "INSERT INTO projects (name, created_at) VALUES (?, ?)", project.Name().Value(), createdAt)
+		if err != nil {
+			return dbError(err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return dbError(err)
+		}
+
+		idVo, err = projectdomain.NewID(int(id))
+		if err != nil {
+			return apperrors.InternalServerError.Wrap(err)
+		}
+
+		if _, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO project_members (project_id, user_id, role) VALUES (?, ?, ?)",
+			idVo.Value(),
+			owner.Value(),
+			projectdomain.Owner.Value(),
+		); err != nil {
+			return dbError(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return idVo, nil
+}
+
+func fetchProjectByID(ctx context.Context, conn sqlx.QueryerContext, id projectdomain.ID) (*projectdomain.Project, error) {
+	var projectDto datasource.Project
+	if err := conn.QueryRowxContext(ctx, "SELECT id, name, created_at FROM projects WHERE id = ?", id.Value()).StructScan(&projectDto); err != nil {
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ProjectNotFound
+		}
+
+		return nil, dbError(err)
+	}
+
+	return toProjectDomain(projectDto)
+}
+
+func fetchProjects(ctx context.Context, conn sqlx.QueryerContext, userID userdomain.ID) ([]*projectdomain.Membership, error) {
+	fetchQuery := `
+        SELECT
+            projects.id           id,
+            projects.name         name,
+            projects.created_at   created_at,
+            project_members.role  role
+        FROM
+            projects
+        INNER JOIN
+            project_members
+        ON
+            project_members.project_id = projects.id
+        WHERE
+            project_members.user_id = ?
+        ORDER BY
+            projects.id`
+
+	rows, err := conn.QueryxContext(ctx, fetchQuery, userID.Value())
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	var memberships []*projectdomain.Membership
+	for rows.Next() {
+		var membershipDto datasource.ProjectMembership
+		if err := rows.StructScan(&membershipDto); err != nil {
+			return nil, dbError(err)
+		}
+
+		projectDm, err := toProjectDomain(membershipDto.Project)
+		if err != nil {
+			return nil, err
+		}
+
+		roleVo, err := projectdomain.NewRole(membershipDto.Role)
+		if err != nil {
+			return nil, apperrors.InternalServerError.Wrap(err)
+		}
+
+		memberships = append(memberships, &projectdomain.Membership{Project: projectDm, Role: roleVo})
+	}
+
+	return memberships, nil
+}
+
+// fetchRole・fetchMembers はforUpdateなら取得した行をロックする (MySQLのみ)
+func fetchRole(ctx context.Context, conn sqlx.QueryerContext, id projectdomain.ID, userID userdomain.ID, forUpdate bool) (projectdomain.Role, error) {
+	var role string
+	if err := sqlx.GetContext(ctx, conn, &role, "SELECT role FROM project_members WHERE project_id = ? AND user_id = ?"+lockClause(forUpdate), id.Value(), userID.Value()); err != nil {
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return "", apperrors.ProjectNotFound
+		}
+
+		return "", dbError(err)
+	}
+
+	roleVo, err := projectdomain.NewRole(role)
+	if err != nil {
+		return "", apperrors.InternalServerError.Wrap(err)
+	}
+
+	return roleVo, nil
+}
+
+func fetchMembers(ctx context.Context, conn sqlx.QueryerContext, id projectdomain.ID, forUpdate bool) ([]*projectdomain.Member, error) {
+	var membersDto []datasource.ProjectMember
+	if err := sqlx.SelectContext(ctx, conn, &membersDto, "SELECT user_id, role FROM project_members WHERE project_id = ? ORDER BY user_id"+lockClause(forUpdate), id.Value()); err != nil {
+		return nil, dbError(err)
+	}
+
+	members := make([]*projectdomain.Member, len(membersDto))
+	for i, memberDto := range membersDto {
+		userIDVo, err := userdomain.NewID(memberDto.UserID)
+		if err != nil {
+			return nil, apperrors.InternalServerError.Wrap(err)
+		}
+
+		roleVo, err := projectdomain.NewRole(memberDto.Role)
+		if err != nil {
+			return nil, apperrors.InternalServerError.Wrap(err)
+		}
+
+		members[i] = projectdomain.NewMember(userIDVo, roleVo)
+	}
+
+	return members, nil
+}
+
+func deleteMember(ctx context.Context, conn sqlx.ExecerContext, id projectdomain.ID, userID userdomain.ID) error {
+	result, err := conn.ExecContext(ctx, "DELETE FROM project_members WHERE project_id = ? AND user_id = ?", id.Value(), userID.Value())
+	if err != nil {
+		return dbError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError(err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.UserNotFound
+	}
+
+	return nil
+}
+
+func toProjectDomain(projectDto datasource.Project) (*projectdomain.Project, error) {
+	idVo, err := projectdomain.NewID(projectDto.ID)
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	nameVo, err := projectdomain.NewName(projectDto.Name)
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	return projectdomain.NewProject(idVo, nameVo, projectDto.CreatedAt), nil
+}
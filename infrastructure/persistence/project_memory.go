@@ -0,0 +1,135 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+// projectMemoryRepository はtodoの参照範囲の判定にメンバーを使うため、todoと同じ memoryStore に保持する
+type projectMemoryRepository struct {
+	*memoryStore
+	// トランザクション中のリポジトリは既にロックを取っているためロックしない
+	inTx bool
+}
+
+func NewProjectMemoryRepository(todoMemoryRepository *todoMemoryRepository) *projectMemoryRepository {
+	return &projectMemoryRepository{memoryStore: todoMemoryRepository.memoryStore}
+}
+
+// lock は書き込みロックを取り、解放する関数を返す
+func (r *projectMemoryRepository) lock() func() {
+	if r.inTx {
+		return func() {}
+	}
+
+	r.mu.Lock()
+	return r.mu.Unlock
+}
+
+// rlock は読み込みロックを取り、解放する関数を返す
+func (r *projectMemoryRepository) rlock() func() {
+	if r.inTx {
+		return func() {}
+	}
+
+	r.mu.RLock()
+	return r.mu.RUnlock
+}
+
+func (r *projectMemoryRepository) CreateProject(ctx context.Context, project *projectdomain.Project, owner userdomain.ID) (projectdomain.ID, error) {
+	defer r.lock()()
+
+	idVo, err := projectdomain.NewID(r.lastProjectID + 1)
+	if err != nil {
+		return 0, apperrors.InternalServerError.Wrap(err)
+	}
+	r.lastProjectID++
+
+	r.projects[idVo.Value()] = projectdomain.NewProject(idVo, project.Name(), project.CreatedAt())
+	r.members[idVo.Value()] = map[userdomain.ID]projectdomain.Role{owner: projectdomain.Owner}
+
+	return idVo, nil
+}
+
+func (r *projectMemoryRepository) FetchProjectByID(ctx context.Context, id projectdomain.ID) (*projectdomain.Project, error) {
+	defer r.rlock()()
+
+	project, ok := r.projects[id.Value()]
+	if !ok {
+		return nil, apperrors.ProjectNotFound
+	}
+
+	return project, nil
+}
+
+func (r *projectMemoryRepository) FetchProjects(ctx context.Context, userID userdomain.ID) ([]*projectdomain.Membership, error) {
+	defer r.rlock()()
+
+	var memberships []*projectdomain.Membership
+	for id, members := range r.members {
+		if role, ok := members[userID]; ok {
+			memberships = append(memberships, &projectdomain.Membership{Project: r.projects[id], Role: role})
+		}
+	}
+
+	sort.Slice(memberships, func(i, j int) bool {
+		return memberships[i].Project.ID() < memberships[j].Project.ID()
+	})
+
+	return memberships, nil
+}
+
+func (r *projectMemoryRepository) FetchRole(ctx context.Context, id projectdomain.ID, userID userdomain.ID) (projectdomain.Role, error) {
+	defer r.rlock()()
+
+	role, ok := r.members[id.Value()][userID]
+	if !ok {
+		return "", apperrors.ProjectNotFound
+	}
+
+	return role, nil
+}
+
+func (r *projectMemoryRepository) FetchMembers(ctx context.Context, id projectdomain.ID) ([]*projectdomain.Member, error) {
+	defer r.rlock()()
+
+	members := make([]*projectdomain.Member, 0, len(r.members[id.Value()]))
+	for userID, role := range r.members[id.Value()] {
+		members = append(members, projectdomain.NewMember(userID, role))
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].UserID() < members[j].UserID()
+	})
+
+	return members, nil
+}
+
+func (r *projectMemoryRepository) SaveMember(ctx context.Context, id projectdomain.ID, member *projectdomain.Member) error {
+	defer r.lock()()
+
+	members, ok := r.members[id.Value()]
+	if !ok {
+		return apperrors.InvalidReference
+	}
+
+	members[member.UserID()] = member.Role()
+
+	return nil
+}
+
+func (r *projectMemoryRepository) DeleteMember(ctx context.Context, id projectdomain.ID, userID userdomain.ID) error {
+	defer r.lock()()
+
+	if _, ok := r.members[id.Value()][userID]; !ok {
+		return apperrors.UserNotFound
+	}
+
+	delete(r.members[id.Value()], userID)
+
+	return nil
+}
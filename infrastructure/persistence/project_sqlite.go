@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
+)
+
+type projectSQLiteRepository struct {
+	*rdb.SQLiteHandler
+	tx *sqlx.Tx
+}
+
+func NewProjectSQLiteRepository(sqliteHandler *rdb.SQLiteHandler) *projectSQLiteRepository {
+	return &projectSQLiteRepository{SQLiteHandler: sqliteHandler}
+}
+
+// ext はトランザクション中ならトランザクションを、そうでなければ接続を返す
+func (r *projectSQLiteRepository) ext() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+
+	return r.Conn
+}
+
+func (r *projectSQLiteRepository) CreateProject(ctx context.Context, project *projectdomain.Project, owner userdomain.ID) (projectdomain.ID, error) {
+	createdAt := project.CreatedAt()
+	return createProject(ctx, r.Conn, project, owner, sqliteDateTime(&createdAt))
+}
+
+func (r *projectSQLiteRepository) FetchProjectByID(ctx context.Context, id projectdomain.ID) (*projectdomain.Project, error) {
+	return fetchProjectByID(ctx, r.ext(), id)
+}
+
+func (r *projectSQLiteRepository) FetchProjects(ctx context.Context, userID userdomain.ID) ([]*projectdomain.Membership, error) {
+	return fetchProjects(ctx, r.ext(), userID)
+}
+
+func (r *projectSQLiteRepository) FetchRole(ctx context.Context, id projectdomain.ID, userID userdomain.ID) (projectdomain.Role, error) {
+	// 接続が1本のためトランザクションは直列に実行され、行のロックは不要
+	return fetchRole(ctx, r.ext(), id, userID, false)
+}
+
+func (r *projectSQLiteRepository) FetchMembers(ctx context.Context, id projectdomain.ID) ([]*projectdomain.Member, error) {
+	return fetchMembers(ctx, r.ext(), id, false)
+}
+
+func (r *projectSQLiteRepository) SaveMember(ctx context.Context, id projectdomain.ID, member *projectdomain.Member) error {
+	query := `
+        INSERT INTO project_members
+        (
+          project_id,
+          user_id,
+          role
+        )
+        VALUES
+          (?, ?, ?)
+        ON CONFLICT (project_id, user_id) DO UPDATE SET
+          role = excluded.role`
+
+	if _, err := r.ext().ExecContext(ctx, query, id.Value(), member.UserID().Value(), member.Role().Value()); err != nil {
+		return dbError(err)
+	}
+
+	return nil
+}
+
+func (r *projectSQLiteRepository) DeleteMember(ctx context.Context, id projectdomain.ID, userID userdomain.ID) error {
+	return deleteMember(ctx, r.ext(), id, userID)
+}
@@ -0,0 +1,158 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/xerrors"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/datasource"
+	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
+)
+
+type tagRepository struct {
+	*rdb.MySQLHandler
+}
+
+func NewTagRepository(mysqlHandler *rdb.MySQLHandler) *tagRepository {
+	return &tagRepository{mysqlHandler}
+}
+
+func (r *tagRepository) CreateTag(ctx context.Context, tag *tododomain.Tag) (tododomain.TagID, error) {
+	return createTag(ctx, r.Conn, tag)
+}
+
+func (r *tagRepository) FetchTags(ctx context.Context) ([]*tododomain.Tag, error) {
+	return fetchTags(ctx, r.Conn)
+}
+
+func (r *tagRepository) FetchTagByID(ctx context.Context, id tododomain.TagID) (*tododomain.Tag, error) {
+	return fetchTagByID(ctx, r.Conn, id)
+}
+
+func (r *tagRepository) UpdateTag(ctx context.Context, tag *tododomain.Tag) error {
+	return updateTag(ctx, r.Conn, tag)
+}
+
+func (r *tagRepository) DeleteTag(ctx context.Context, id tododomain.TagID) error {
+	return deleteTag(ctx, r.Conn, id)
+}
+
+// 以下はMySQL・SQLiteで共通のタグの読み書き。todoからはtodo_tagsの外部キーで外れる
+
+func createTag(ctx context.Context, conn sqlx.ExecerContext, tag *tododomain.Tag) (tododomain.TagID, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := conn.ExecContext(ctx, "INSERT INTO tags (owner_id, name) VALUES (?, ?)", ownerID.Value(), tag.Name().Value())
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	idVo, err := tododomain.NewTagID(int(id))
+	if err != nil {
+		return 0, apperrors.InternalServerError.Wrap(err)
+	}
+
+	return idVo, nil
+}
+
+func fetchTags(ctx context.Context, conn sqlx.QueryerContext) ([]*tododomain.Tag, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tagsDto []datasource.Tag
+	if err = sqlx.SelectContext(ctx, conn, &tagsDto, "SELECT id, name FROM tags WHERE owner_id = ? ORDER BY name, id", ownerID.Value()); err != nil {
+		return nil, dbError(err)
+	}
+
+	tagDms := make([]*tododomain.Tag, len(tagsDto))
+	for i, tagDto := range tagsDto {
+		tagDm, err := toTagDomain(tagDto)
+		if err != nil {
+			return nil, err
+		}
+
+		tagDms[i] = tagDm
+	}
+
+	return tagDms, nil
+}
+
+func fetchTagByID(ctx context.Context, conn sqlx.QueryerContext, id tododomain.TagID) (*tododomain.Tag, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tagDto datasource.Tag
+	if err = conn.QueryRowxContext(ctx, "SELECT id, name FROM tags WHERE id = ? AND owner_id = ?", id.Value(), ownerID.Value()).StructScan(&tagDto); err != nil {
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.TagNotFound
+		}
+
+		return nil, dbError(err)
+	}
+
+	return toTagDomain(tagDto)
+}
+
+func updateTag(ctx context.Context, conn sqlx.ExtContext, tag *tododomain.Tag) error {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn.ExecContext(ctx, "UPDATE tags SET name = ? WHERE id = ? AND owner_id = ?", tag.Name().Value(), tag.ID().Value(), ownerID.Value())
+	if err != nil {
+		return dbError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError(err)
+	}
+
+	// MySQLは値が変わらない更新を0件と数えるため、存在を確認し直す
+	if rowsAffected == 0 {
+		_, err = fetchTagByID(ctx, conn, tag.ID())
+		return err
+	}
+
+	return nil
+}
+
+func deleteTag(ctx context.Context, conn sqlx.ExecerContext, id tododomain.TagID) error {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn.ExecContext(ctx, "DELETE FROM tags WHERE id = ? AND owner_id = ?", id.Value(), ownerID.Value())
+	if err != nil {
+		return dbError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError(err)
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.TagNotFound
+	}
+
+	return nil
+}
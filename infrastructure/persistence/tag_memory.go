@@ -0,0 +1,168 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+// tagMemoryRepository はタグの変更をtodoに反映するため、todoと同じ memoryStore に保持する
+type tagMemoryRepository struct {
+	*memoryStore
+}
+
+func NewTagMemoryRepository(todoMemoryRepository *todoMemoryRepository) *tagMemoryRepository {
+	return &tagMemoryRepository{todoMemoryRepository.memoryStore}
+}
+
+func (r *tagMemoryRepository) CreateTag(ctx context.Context, tag *tododomain.Tag) (tododomain.TagID, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.duplicated(tag, ownerID) {
+		return 0, apperrors.Conflict
+	}
+
+	idVo, err := tododomain.NewTagID(r.lastTagID + 1)
+	if err != nil {
+		return 0, apperrors.InternalServerError.Wrap(err)
+	}
+	r.lastTagID++
+
+	r.tags[idVo.Value()] = memoryTag{ownerID: ownerID, tag: tododomain.NewTag(idVo, tag.Name())}
+
+	return idVo, nil
+}
+
+func (r *tagMemoryRepository) FetchTags(ctx context.Context) ([]*tododomain.Tag, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tags := make([]*tododomain.Tag, 0)
+	for _, t := range r.tags {
+		if t.ownerID == ownerID {
+			tags = append(tags, tododomain.NewTag(t.tag.ID(), t.tag.Name()))
+		}
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Name() != tags[j].Name() {
+			return tags[i].Name() < tags[j].Name()
+		}
+		return tags[i].ID() < tags[j].ID()
+	})
+
+	return tags, nil
+}
+
+func (r *tagMemoryRepository) FetchTagByID(ctx context.Context, id tododomain.TagID) (*tododomain.Tag, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.tags[id.Value()]
+	if !ok || t.ownerID != ownerID {
+		return nil, apperrors.TagNotFound
+	}
+
+	return tododomain.NewTag(t.tag.ID(), t.tag.Name()), nil
+}
+
+func (r *tagMemoryRepository) UpdateTag(ctx context.Context, tag *tododomain.Tag) error {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.tags[tag.ID().Value()]
+	if !ok || t.ownerID != ownerID {
+		return apperrors.TagNotFound
+	}
+
+	if r.duplicated(tag, ownerID) {
+		return apperrors.Conflict
+	}
+
+	renamed := tododomain.NewTag(tag.ID(), tag.Name())
+	r.tags[tag.ID().Value()] = memoryTag{ownerID: ownerID, tag: renamed}
+
+	return r.replaceTodoTags(tag.ID(), renamed)
+}
+
+func (r *tagMemoryRepository) DeleteTag(ctx context.Context, id tododomain.TagID) error {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.tags[id.Value()]
+	if !ok || t.ownerID != ownerID {
+		return apperrors.TagNotFound
+	}
+
+	delete(r.tags, id.Value())
+
+	return r.replaceTodoTags(id, nil)
+}
+
+// duplicated はユーザーの他のタグに同じ名前があるかを返す
+func (r *tagMemoryRepository) duplicated(tag *tododomain.Tag, ownerID userdomain.ID) bool {
+	for id, t := range r.tags {
+		if id != tag.ID().Value() && t.ownerID == ownerID && t.tag.Name() == tag.Name() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// replaceTodoTags はタグを付けたtodoのタグを差し替える。newTagがnilならtodoから外す
+func (r *tagMemoryRepository) replaceTodoTags(id tododomain.TagID, newTag *tododomain.Tag) error {
+	for todoID, todo := range r.todos {
+		var (
+			tags    []*tododomain.Tag
+			matched bool
+		)
+		for _, tag := range todo.Tags() {
+			if tag.ID() != id {
+				tags = append(tags, tag)
+				continue
+			}
+
+			matched = true
+			if newTag != nil {
+				tags = append(tags, newTag)
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		r.todos[todoID] = copyTodoWith(todo.ID(), todo, todo.Version(), tags, todo.Checklist())
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
+)
+
+type tagSQLiteRepository struct {
+	*rdb.SQLiteHandler
+}
+
+func NewTagSQLiteRepository(sqliteHandler *rdb.SQLiteHandler) *tagSQLiteRepository {
+	return &tagSQLiteRepository{sqliteHandler}
+}
+
+func (r *tagSQLiteRepository) CreateTag(ctx context.Context, tag *tododomain.Tag) (tododomain.TagID, error) {
+	return createTag(ctx, r.Conn, tag)
+}
+
+func (r *tagSQLiteRepository) FetchTags(ctx context.Context) ([]*tododomain.Tag, error) {
+	return fetchTags(ctx, r.Conn)
+}
+
+func (r *tagSQLiteRepository) FetchTagByID(ctx context.Context, id tododomain.TagID) (*tododomain.Tag, error) {
+	return fetchTagByID(ctx, r.Conn, id)
+}
+
+func (r *tagSQLiteRepository) UpdateTag(ctx context.Context, tag *tododomain.Tag) error {
+	return updateTag(ctx, r.Conn, tag)
+}
+
+func (r *tagSQLiteRepository) DeleteTag(ctx context.Context, id tododomain.TagID) error {
+	return deleteTag(ctx, r.Conn, id)
+}
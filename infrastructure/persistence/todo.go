@@ -1,119 +1,206 @@
 package persistence
 
 import (
+	"context"
 	"database/sql"
+	"strings"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"golang.org/x/xerrors"
 
 	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
 	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
 	"github.com/kazumakawahara/todo-sample/infrastructure/datasource"
 	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
 )
 
 type todoRepository struct {
 	*rdb.MySQLHandler
+	// トランザクション中のリポジトリではnil以外
+	tx *sqlx.Tx
 }
 
 func NewTodoRepository(mysqlHandler *rdb.MySQLHandler) *todoRepository {
-	return &todoRepository{mysqlHandler}
-}
-
-func (r *todoRepository) CreateTodo(todo *tododomain.Todo) (tododomain.ID, error) {
-	query := `
-        INSERT INTO todos
-        (
-          title,
-          implementation_date,
-          due_date,
-          status_id,
-          priority_id,
-          memo
-        )
-        VALUES
-          (?, ?, ?, ?, ?, ?)`
-
-	result, err := r.Conn.Exec(
-		query,
-		todo.Title().Value(),
-		todo.ImplementationDate().Value(),
-		todo.DueDate().Value(),
-		todo.Status().Value(),
-		todo.Priority().Value(),
-		todo.Memo().Value(),
-	)
-	if err != nil {
-		panic(err)
-	}
+	return &todoRepository{MySQLHandler: mysqlHandler}
+}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return 0, apperrors.InternalServerError
+// ext はトランザクション中ならトランザクションを、そうでなければ接続を返す
+func (r *todoRepository) ext() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
 	}
 
-	idVo, err := tododomain.NewID(int(id))
+	return r.Conn
+}
+
+func (r *todoRepository) CreateTodo(ctx context.Context, todo *tododomain.Todo) (tododomain.ID, error) {
+	ids, err := createTodos(ctx, r.ext(), []*tododomain.Todo{todo}, mysqlTodoValues)
 	if err != nil {
-		return 0, apperrors.InternalServerError
+		return 0, err
 	}
 
-	return idVo, nil
+	return ids[0], nil
 }
 
-func (r *todoRepository) FetchTodoByID(id tododomain.ID) (*tododomain.Todo, error) {
-	fetchQuery := `
+func (r *todoRepository) FetchTodoByID(ctx context.Context, id tododomain.ID) (*tododomain.Todo, error) {
+	return fetchTodoByID(ctx, r.ext(), id, false)
+}
+
+func (r *todoRepository) FetchTrashedTodoByID(ctx context.Context, id tododomain.ID) (*tododomain.Todo, error) {
+	return fetchTodoByID(ctx, r.ext(), id, true)
+}
+
+func (r *todoRepository) FetchTodos(ctx context.Context, criteria *tododomain.Criteria) ([]*tododomain.Todo, error) {
+	return fetchTodos(ctx, r.ext(), criteria)
+}
+
+func (r *todoRepository) SearchTodos(ctx context.Context, query tododomain.SearchQuery, limit int) ([]*tododomain.SearchResult, error) {
+	searchQuery := `
         SELECT
-          todos.id                  id,
-          todos.title               title,
-          todos.implementation_date implementation_date,
-          todos.due_date            due_date,
-          todos.status_id           status_id,
-          todos.priority_id         priority_id,
-          todos.memo                memo
+            ` + todoSelectColumns + `,
+            MATCH (todos.title, todos.memo) AGAINST (? IN NATURAL LANGUAGE MODE) score
         FROM
-          todos
+            todos
         INNER JOIN
-          statuses
+            statuses
         ON
-          statuses.id = todos.status_id
+            statuses.id = todos.status_id
         INNER JOIN
-          priorities
+            priorities
         ON
-          priorities.id = todos.priority_id
+            priorities.id = todos.priority_id
         WHERE
-          todos.id = ?`
+            MATCH (todos.title, todos.memo) AGAINST (? IN NATURAL LANGUAGE MODE)
+        AND
+            ` + todoScopeCond + `
+        AND
+            ` + trashedCond(false) + `
+        ORDER BY
+            score DESC,
+            todos.id
+        LIMIT ?`
 
-	var todoDto datasource.Todo
-	if err := r.Conn.QueryRowx(fetchQuery, id.Value()).StructScan(&todoDto); err != nil {
-		if xerrors.Is(err, sql.ErrNoRows) {
-			return nil, apperrors.TodoNotFound
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resultsDto []datasource.TodoSearchResult
+	if err = sqlx.SelectContext(ctx, r.ext(), &resultsDto, searchQuery, query.Value(), query.Value(), ownerID.Value(), ownerID.Value(), limit); err != nil {
+		return nil, dbError(err)
+	}
+
+	todosDto := make([]datasource.Todo, len(resultsDto))
+	for i, resultDto := range resultsDto {
+		todosDto[i] = resultDto.Todo
+	}
+
+	todoDms, err := toTodoDomains(ctx, r.ext(), todosDto)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*tododomain.SearchResult, len(resultsDto))
+	for i, resultDto := range resultsDto {
+		results[i] = &tododomain.SearchResult{
+			Todo:         todoDms[i],
+			Score:        resultDto.Score,
+			MatchedTerms: ngramTokens(query.Terms(), mysqlNgramTokenSize),
+		}
+	}
+
+	return results, nil
+}
+
+// FULLTEXTインデックスのngramパーサーの分割単位 (ngram_token_sizeのデフォルト値)
+const mysqlNgramTokenSize = 2
+
+// ngramTokens はキーワードをFULLTEXTインデックスと同じn文字ずつに分割する。
+// キーワードそのものを含まなくても、いずれかの分割した語を含めば検索に一致する。
+func ngramTokens(terms []string, n int) []string {
+	var tokens []string
+	for _, term := range terms {
+		runes := []rune(term)
+		if len(runes) <= n {
+			tokens = append(tokens, term)
+			continue
 		}
 
-		return nil, apperrors.InternalServerError
+		for i := 0; i+n <= len(runes); i++ {
+			tokens = append(tokens, string(runes[i:i+n]))
+		}
 	}
 
-	todoDm := tododomain.NewTodo(
-		tododomain.ID(todoDto.ID),
-		tododomain.Title(todoDto.Title),
-		tododomain.ImplementationDate(todoDto.ImplementationDate),
-		tododomain.DueDate(todoDto.DueDate),
-		tododomain.Status(todoDto.StatusID),
-		tododomain.Priority(todoDto.PriorityID),
-		tododomain.Memo(todoDto.Memo),
-	)
+	return tokens
+}
 
-	return todoDm, nil
+func (r *todoRepository) UpdateTodo(ctx context.Context, todo *tododomain.Todo) (tododomain.ID, error) {
+	return updateTodo(ctx, r.ext(), todo, mysqlTodoValues)
 }
 
-func (r *todoRepository) FetchTodos() ([]*tododomain.Todo, error) {
-	fetchQuery := `
-        SELECT
-            todos.id                  id,
+func (r *todoRepository) PurgeTodos(ctx context.Context, deletedBefore time.Time, projectIDs []projectdomain.ID) (int, error) {
+	return purgeTodos(ctx, r.ext(), deletedBefore, projectIDs)
+}
+
+func (r *todoRepository) FetchHistories(ctx context.Context, todoID tododomain.ID) ([]*tododomain.History, error) {
+	return fetchHistories(ctx, r.ext(), todoID)
+}
+
+func (r *todoRepository) CreateHistory(ctx context.Context, history *tododomain.History) error {
+	return createHistories(ctx, r.ext(), []*tododomain.History{history}, mysqlHistoryCreatedAt)
+}
+
+func (r *todoRepository) CreateTodos(ctx context.Context, todos []*tododomain.Todo) ([]tododomain.ID, error) {
+	return createTodos(ctx, r.ext(), todos, mysqlTodoValues)
+}
+
+func (r *todoRepository) FetchTodosByIDs(ctx context.Context, ids []tododomain.ID) ([]*tododomain.Todo, error) {
+	return fetchTodosByIDs(ctx, r.ext(), ids, r.tx != nil)
+}
+
+func (r *todoRepository) UpdateTodos(ctx context.Context, todos []*tododomain.Todo) ([]tododomain.ID, error) {
+	return updateTodos(ctx, r.ext(), todos, mysqlTodoValues)
+}
+
+func (r *todoRepository) CreateHistories(ctx context.Context, histories []*tododomain.History) error {
+	return createHistories(ctx, r.ext(), histories, mysqlHistoryCreatedAt)
+}
+
+func mysqlTodoValues(todo *tododomain.Todo) []interface{} {
+	return []interface{}{
+		todo.Title().Value(),
+		todo.ImplementationDate().Value(),
+		todo.DueDate().Value(),
+		todo.Status().Value(),
+		todo.Priority().Value(),
+		todo.Memo().Value(),
+		todo.DeletedAt(),
+	}
+}
+
+func mysqlHistoryCreatedAt(history *tododomain.History) interface{} {
+	return history.CreatedAt()
+}
+
+// 以下はMySQL・SQLiteで共通のtodoの読み書き。日付の形式はドライバごとに異なるため、呼び出し側の関数で吸収する
+
+const todoSelectColumns = `todos.id                  id,
             todos.title               title,
             todos.implementation_date implementation_date,
             todos.due_date            due_date,
             todos.status_id           status_id,
             todos.priority_id         priority_id,
-            todos.memo                memo
+            todos.memo                memo,
+            todos.version             version,
+            todos.deleted_at          deleted_at,
+            todos.project_id          project_id`
+
+const todoSelectFrom = `
+        SELECT
+            ` + todoSelectColumns + `
         FROM
             todos
         INNER JOIN
@@ -125,79 +212,383 @@ func (r *todoRepository) FetchTodos() ([]*tododomain.Todo, error) {
         ON
             priorities.id = todos.priority_id`
 
-	rows, err := r.Conn.Queryx(fetchQuery)
+// createTodos はtodoを1件ずつ登録し、引数の順にIDを返す。
+// 複数行のINSERTで振られるIDはauto_increment_incrementやinnodb_autoinc_lock_modeによっては連番にならないため、行ごとに採番したIDを使う。
+// 途中で失敗しても登録済みの行が残らないよう、複数件の登録はトランザクション内で呼び出す。
+func createTodos(ctx context.Context, conn sqlx.ExtContext, todos []*tododomain.Todo, values todoValues) ([]tododomain.ID, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]tododomain.ID, len(todos))
+	for i, todo := range todos {
+		query, args := buildInsertTodoQuery(todo, values, ownerID)
+
+		result, err := conn.ExecContext(ctx, query, args...)
+		if err != nil {
+			return nil, dbError(err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return nil, dbError(err)
+		}
+
+		if ids[i], err = tododomain.NewID(int(id)); err != nil {
+			return nil, apperrors.InternalServerError.Wrap(err)
+		}
+	}
+
+	if err = saveTodoChildren(ctx, conn, ids, todos); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func fetchTodoByID(ctx context.Context, conn sqlx.QueryerContext, id tododomain.ID, trashed bool) (*tododomain.Todo, error) {
+	fetchQuery := todoSelectFrom + `
+        WHERE
+            todos.id = ?
+        AND
+            ` + todoScopeCond + `
+        AND
+            ` + trashedCond(trashed)
+
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var todoDto datasource.Todo
+	if err := sqlx.GetContext(ctx, conn, &todoDto, fetchQuery, id.Value(), ownerID.Value(), ownerID.Value()); err != nil {
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.TodoNotFound
+		}
+
+		return nil, dbError(err)
+	}
+
+	todoDms, err := toTodoDomains(ctx, conn, []datasource.Todo{todoDto})
+	if err != nil {
+		return nil, err
+	}
+
+	return todoDms[0], nil
+}
+
+func fetchTodos(ctx context.Context, conn sqlx.QueryerContext, criteria *tododomain.Criteria) ([]*tododomain.Todo, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	criteriaQuery, args := buildTodoCriteria(criteria, ownerID)
+
+	return selectTodos(ctx, conn, todoSelectFrom+criteriaQuery, args...)
+}
+
+// fetchTodosByIDs はゴミ箱にないtodoのうちIDが一致するものをID順に返す
+// fetchTodosByIDs はforUpdateなら取得したtodoの行をロックし、一括更新までに他から変更させない (MySQLのみ)
+func fetchTodosByIDs(ctx context.Context, conn sqlx.QueryerContext, ids []tododomain.ID, forUpdate bool) ([]*tododomain.Todo, error) {
+	fetchQuery := todoSelectFrom + `
+        WHERE
+            todos.id IN (` + placeholders(len(ids)) + `)
+        AND
+            ` + todoScopeCond + `
+        AND
+            ` + trashedCond(false) + `
+        ORDER BY
+            todos.id` + lockClause(forUpdate, "todos")
+
+	ownerID, err := ownerID(ctx)
 	if err != nil {
-		return nil, apperrors.InternalServerError
+		return nil, err
+	}
+
+	args := make([]interface{}, 0, len(ids)+2)
+	for _, id := range ids {
+		args = append(args, id.Value())
 	}
+	args = append(args, ownerID.Value(), ownerID.Value())
+
+	return selectTodos(ctx, conn, fetchQuery, args...)
+}
 
+// selectTodos はtodoSelectFromで始まるクエリを実行し、タグとチェックリストを含むtodoを返す
+func selectTodos(ctx context.Context, conn sqlx.QueryerContext, query string, args ...interface{}) ([]*tododomain.Todo, error) {
 	var todosDto []datasource.Todo
-	for rows.Next() {
-		var todoDto datasource.Todo
-		if err := rows.StructScan(&todoDto); err != nil {
-			return nil, apperrors.InternalServerError
+	if err := sqlx.SelectContext(ctx, conn, &todosDto, query, args...); err != nil {
+		return nil, dbError(err)
+	}
+
+	return toTodoDomains(ctx, conn, todosDto)
+}
+
+func updateTodo(ctx context.Context, conn sqlx.ExtContext, todo *tododomain.Todo, values todoValues) (tododomain.ID, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	updated, err := execUpdateTodo(ctx, conn, todo, values, ownerID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !updated {
+		return 0, notAffectedError(ctx, conn, todo.ID())
+	}
+
+	if err = saveTodoChildren(ctx, conn, []tododomain.ID{todo.ID()}, []*tododomain.Todo{todo}); err != nil {
+		return 0, err
+	}
+
+	return todo.ID(), nil
+}
+
+// updateTodos は1件ずつ更新し、取得後に他の更新が入り更新しなかったtodoのIDを返す
+func updateTodos(ctx context.Context, conn sqlx.ExtContext, todos []*tododomain.Todo, values todoValues) ([]tododomain.ID, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		stale        []tododomain.ID
+		updatedIDs   []tododomain.ID
+		updatedTodos []*tododomain.Todo
+	)
+	for _, todo := range todos {
+		updated, err := execUpdateTodo(ctx, conn, todo, values, ownerID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !updated {
+			stale = append(stale, todo.ID())
+			continue
 		}
 
-		todosDto = append(todosDto, todoDto)
+		updatedIDs = append(updatedIDs, todo.ID())
+		updatedTodos = append(updatedTodos, todo)
 	}
 
-	todoDms := make([]*tododomain.Todo, len(todosDto))
-	for i, todoDto := range todosDto {
-		todoDms[i] = tododomain.NewTodo(
-			tododomain.ID(todoDto.ID),
-			tododomain.Title(todoDto.Title),
-			tododomain.ImplementationDate(todoDto.ImplementationDate),
-			tododomain.DueDate(todoDto.DueDate),
-			tododomain.Status(todoDto.StatusID),
-			tododomain.Priority(todoDto.PriorityID),
-			tododomain.Memo(todoDto.Memo),
-		)
+	if err = saveTodoChildren(ctx, conn, updatedIDs, updatedTodos); err != nil {
+		return nil, err
 	}
 
-	return todoDms, nil
+	return stale, nil
 }
 
-func (r *todoRepository) UpdateTodo(todo *tododomain.Todo) (tododomain.ID, error) {
+// execUpdateTodo は取得時からバージョンが変わっていない利用者が参照できるtodoを更新し、更新したかを返す
+func execUpdateTodo(ctx context.Context, conn sqlx.ExecerContext, todo *tododomain.Todo, values todoValues, ownerID userdomain.ID) (bool, error) {
+	sets := make([]string, len(todoColumns))
+	for i, column := range todoColumns {
+		sets[i] = column + " = ?"
+	}
+
 	updateQuery := `
         UPDATE
             todos
-        SET 
-            title = ?,
-            implementation_date = ?,
-            due_date = ?,
-            status_id = ?,
-            priority_id = ?,
-            memo = ?
+        SET
+            ` + strings.Join(sets, ",\n            ") + `,
+            version = version + 1
         WHERE
-            id = ?`
+            id = ?
+        AND
+            version = ?
+        AND
+            ` + todoScopeCond
 
-	if _, err := r.Conn.Exec(
-		updateQuery,
-		todo.Title().Value(),
-		todo.ImplementationDate().Value(),
-		todo.DueDate().Value(),
-		todo.Status().Value(),
-		todo.Priority().Value(),
-		todo.Memo().Value(),
-		todo.ID().Value(),
-	); err != nil {
-		return 0, apperrors.InternalServerError
+	args := append(values(todo), todo.ID().Value(), todo.Version().Value(), ownerID.Value(), ownerID.Value())
+
+	result, err := conn.ExecContext(ctx, updateQuery, args...)
+	if err != nil {
+		return false, dbError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, dbError(err)
 	}
-	return 0, nil
+
+	return rowsAffected > 0, nil
 }
 
-func (r *todoRepository) DeleteTodo(id tododomain.ID) error {
-	deleteQuery := `
+// purgeTodos は個人のtodoとprojectIDsのプロジェクトのtodoのうち、deletedBeforeより前にゴミ箱に移動したものを完全に削除する。
+// deletedBeforeはドライバの日時の形式で渡す
+func purgeTodos(ctx context.Context, conn sqlx.ExecerContext, deletedBefore interface{}, projectIDs []projectdomain.ID) (int, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	projectCond := "todos.project_id IS NULL"
+	args := []interface{}{deletedBefore, ownerID.Value(), ownerID.Value()}
+	if len(projectIDs) > 0 {
+		projectCond = "(todos.project_id IS NULL OR todos.project_id IN (" + placeholders(len(projectIDs)) + "))"
+		for _, projectID := range projectIDs {
+			args = append(args, projectID.Value())
+		}
+	}
+
+	purgeQuery := `
         DELETE FROM
             todos
         WHERE
-            id = ?`
+            deleted_at < ?
+        AND
+            ` + todoScopeCond + `
+        AND
+            ` + projectCond
 
-	if _, err := r.Conn.Exec(
-		deleteQuery,
-		id.Value(),
-	); err != nil {
-		return apperrors.InternalServerError
+	result, err := conn.ExecContext(ctx, purgeQuery, args...)
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// notAffectedError は更新の対象行がなかった理由を返す。
+// 行がなければ完全に削除済み、あれば取得後に他の更新が入りバージョンが変わっている。
+func notAffectedError(ctx context.Context, conn sqlx.QueryerContext, id tododomain.ID) error {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	if err := sqlx.GetContext(ctx, conn, &count, "SELECT COUNT(*) FROM todos WHERE id = ? AND "+todoScopeCond, id.Value(), ownerID.Value(), ownerID.Value()); err != nil {
+		return dbError(err)
+	}
+
+	if count == 0 {
+		return apperrors.TodoNotFound
+	}
+
+	return apperrors.PreconditionFailed
+}
+
+func fetchHistories(ctx context.Context, conn sqlx.QueryerContext, todoID tododomain.ID) ([]*tododomain.History, error) {
+	fetchQuery := `
+        SELECT
+            todo_histories.id         id,
+            todo_histories.todo_id    todo_id,
+            todo_histories.action     action,
+            todo_histories.changes    changes,
+            todo_histories.actor      actor,
+            todo_histories.request_id request_id,
+            todo_histories.created_at created_at
+        FROM
+            todo_histories
+        WHERE
+            todo_histories.todo_id = ?
+        AND
+            ` + historyScopeCond + `
+        ORDER BY
+            todo_histories.id`
+
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var historiesDto []datasource.TodoHistory
+	if err = sqlx.SelectContext(ctx, conn, &historiesDto, fetchQuery, todoID.Value(), ownerID.Value(), ownerID.Value()); err != nil {
+		return nil, dbError(err)
+	}
+
+	histories := make([]*tododomain.History, len(historiesDto))
+	for i, historyDto := range historiesDto {
+		history, err := toHistoryDomain(historyDto)
+		if err != nil {
+			return nil, err
+		}
+
+		histories[i] = history
+	}
+
+	return histories, nil
+}
+
+func createHistories(
+	ctx context.Context,
+	conn sqlx.ExecerContext,
+	histories []*tododomain.History,
+	createdAt func(history *tododomain.History) interface{},
+) error {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := buildInsertHistoriesQuery(histories, ownerID, createdAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err = conn.ExecContext(ctx, query, args...); err != nil {
+		return dbError(err)
 	}
 
 	return nil
 }
+
+// saveTodoChildren はtodoと別のテーブルに保持するタグとチェックリストのうち、変わったものを書き込む。todosとidsは同じ並びで渡す
+func saveTodoChildren(ctx context.Context, conn sqlx.ExecerContext, ids []tododomain.ID, todos []*tododomain.Todo) error {
+	if err := saveTodoTags(ctx, conn, ids, todos); err != nil {
+		return err
+	}
+
+	return saveChecklists(ctx, conn, ids, todos)
+}
+
+// toTodoDomains はtodoに付けたタグとチェックリストをまとめて取得し、todoのドメインモデルに変換する
+func toTodoDomains(ctx context.Context, conn sqlx.QueryerContext, todosDto []datasource.Todo) ([]*tododomain.Todo, error) {
+	ids := make([]int, len(todosDto))
+	for i, todoDto := range todosDto {
+		ids[i] = todoDto.ID
+	}
+
+	tags, err := fetchTodoTags(ctx, conn, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	checklists, err := fetchChecklistItems(ctx, conn, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	todoDms := make([]*tododomain.Todo, len(todosDto))
+	for i, todoDto := range todosDto {
+		todoDms[i] = toTodoDomain(todoDto, tags[todoDto.ID], checklists[todoDto.ID])
+	}
+
+	return todoDms, nil
+}
+
+func toTodoDomain(todoDto datasource.Todo, tags []*tododomain.Tag, checklist []*tododomain.ChecklistItem) *tododomain.Todo {
+	return tododomain.NewTodo(tododomain.TodoParams{
+		ID:                 tododomain.ID(todoDto.ID),
+		Title:              tododomain.Title(todoDto.Title),
+		ImplementationDate: tododomain.ImplementationDate(todoDto.ImplementationDate),
+		DueDate:            tododomain.DueDate(todoDto.DueDate),
+		Status:             tododomain.Status(todoDto.StatusID),
+		Priority:           tododomain.Priority(todoDto.PriorityID),
+		Memo:               tododomain.Memo(todoDto.Memo),
+		Version:            tododomain.Version(todoDto.Version),
+		DeletedAt:          todoDto.DeletedAt,
+		ProjectID:          projectdomain.ID(todoDto.ProjectID.Int64),
+		Tags:               tags,
+		Checklist:          checklist,
+	})
+}
@@ -0,0 +1,88 @@
+package persistence
+
+import (
+	"strings"
+
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+// MySQL・SQLiteで共通の登録の組み立て。
+// 日付の形式はドライバごとに異なるため、1行分の値は呼び出し側の関数で作る。
+
+// todoColumns は todoValues が返す値の並び
+var todoColumns = []string{
+	"title",
+	"implementation_date",
+	"due_date",
+	"status_id",
+	"priority_id",
+	"memo",
+	"deleted_at",
+}
+
+// todoValues はtodoColumnsの順にtodoの値を返す
+type todoValues func(todo *tododomain.Todo) []interface{}
+
+// buildInsertTodoQuery はtodoを登録するINSERT文とその引数を返す
+func buildInsertTodoQuery(todo *tododomain.Todo, values todoValues, ownerID userdomain.ID) (string, []interface{}) {
+	args := append(values(todo), todo.Version().Value(), ownerID.Value(), projectIDValue(todo.ProjectID()))
+
+	query := `
+        INSERT INTO todos
+        (
+          ` + strings.Join(todoColumns, ",\n          ") + `,
+          version,
+          owner_id,
+          project_id
+        )
+        VALUES
+          (` + placeholders(len(args)) + `)`
+
+	return query, args
+}
+
+// buildInsertHistoriesQuery は複数の変更履歴を1文で登録するINSERT文とその引数を返す
+func buildInsertHistoriesQuery(
+	histories []*tododomain.History,
+	ownerID userdomain.ID,
+	createdAt func(history *tododomain.History) interface{},
+) (string, []interface{}, error) {
+	rows := make([]string, len(histories))
+	args := make([]interface{}, 0, len(histories)*8)
+	for i, history := range histories {
+		changes, err := toHistoryChangesJSON(history.Changes())
+		if err != nil {
+			return "", nil, err
+		}
+
+		rows[i] = "(?, ?, ?, ?, ?, ?, ?, (SELECT project_id FROM todos WHERE id = ?))"
+		args = append(args,
+			history.TodoID().Value(),
+			history.Action().Value(),
+			changes,
+			history.Actor(),
+			history.RequestID(),
+			createdAt(history),
+			ownerID.Value(),
+			history.TodoID().Value(),
+		)
+	}
+
+	query := `
+        INSERT INTO todo_histories
+        (
+          todo_id,
+          action,
+          changes,
+          actor,
+          request_id,
+          created_at,
+          owner_id,
+          project_id
+        )
+        VALUES
+          ` + strings.Join(rows, ",\n          ")
+
+	return query, args, nil
+}
@@ -0,0 +1,128 @@
+package persistence
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/datasource"
+)
+
+// MySQL・SQLiteで共通のtodoのチェックリストの読み書き。
+// チェックリストはtodo集約の一部のため、todoの更新と同じトランザクションで項目をまとめて置き換える。
+
+// fetchChecklistItems はtodoのIDごとにチェックリストの項目を並び順で返す
+func fetchChecklistItems(ctx context.Context, conn sqlx.QueryerContext, todoIDs []int) (map[int][]*tododomain.ChecklistItem, error) {
+	checklists := make(map[int][]*tododomain.ChecklistItem, len(todoIDs))
+	if len(todoIDs) == 0 {
+		return checklists, nil
+	}
+
+	fetchQuery := `
+        SELECT
+            id,
+            todo_id,
+            title,
+            done
+        FROM
+            checklist_items
+        WHERE
+            todo_id IN (` + placeholders(len(todoIDs)) + `)
+        ORDER BY
+            todo_id,
+            position`
+
+	args := make([]interface{}, len(todoIDs))
+	for i, id := range todoIDs {
+		args[i] = id
+	}
+
+	var itemsDto []datasource.ChecklistItem
+	if err := sqlx.SelectContext(ctx, conn, &itemsDto, fetchQuery, args...); err != nil {
+		return nil, dbError(err)
+	}
+
+	for _, itemDto := range itemsDto {
+		itemDm, err := toChecklistItemDomain(itemDto)
+		if err != nil {
+			return nil, err
+		}
+
+		checklists[itemDto.TodoID] = append(checklists[itemDto.TodoID], itemDm)
+	}
+
+	return checklists, nil
+}
+
+// saveChecklists はチェックリストが変わったtodoについて、項目を置き換える。todosとidsは同じ並びで渡す。
+// 既存の項目はIDを引き継ぎ、追加した項目はIDを採番する。
+func saveChecklists(ctx context.Context, conn sqlx.ExecerContext, ids []tododomain.ID, todos []*tododomain.Todo) error {
+	var (
+		deleteArgs []interface{}
+		rows       []string
+		args       []interface{}
+	)
+	for i, todo := range todos {
+		if !todo.IsChecklistChanged() {
+			continue
+		}
+
+		deleteArgs = append(deleteArgs, ids[i].Value())
+		for position, item := range todo.Checklist() {
+			var itemID interface{}
+			if item.ID() != 0 {
+				itemID = item.ID().Value()
+			}
+
+			rows = append(rows, "(?, ?, ?, ?, ?)")
+			args = append(args, itemID, ids[i].Value(), position, item.Title().Value(), item.IsDone())
+		}
+	}
+	if len(deleteArgs) == 0 {
+		return nil
+	}
+
+	deleteQuery := "DELETE FROM checklist_items WHERE todo_id IN (" + placeholders(len(deleteArgs)) + ")"
+	if _, err := conn.ExecContext(ctx, deleteQuery, deleteArgs...); err != nil {
+		return dbError(err)
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	insertQuery := `
+        INSERT INTO checklist_items
+        (
+          id,
+          todo_id,
+          position,
+          title,
+          done
+        )
+        VALUES
+          ` + strings.Join(rows, ",\n          ")
+
+	if _, err := conn.ExecContext(ctx, insertQuery, args...); err != nil {
+		return dbError(err)
+	}
+
+	return nil
+}
+
+func toChecklistItemDomain(itemDto datasource.ChecklistItem) (*tododomain.ChecklistItem, error) {
+	idVo, err := tododomain.NewChecklistItemID(itemDto.ID)
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	titleVo, err := tododomain.NewChecklistItemTitle(itemDto.Title)
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	return tododomain.NewChecklistItem(idVo, titleVo, itemDto.Done), nil
+}
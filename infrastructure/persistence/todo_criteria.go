@@ -0,0 +1,146 @@
+package persistence
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+// MySQL・SQLiteで共通の一覧取得条件の組み立て。
+// カラム名は固定の対応表からのみ選び、値は全てプレースホルダで渡す。
+
+var todoSortColumns = map[tododomain.SortField]string{
+	tododomain.SortByID:                 "todos.id",
+	tododomain.SortByTitle:              "todos.title",
+	tododomain.SortByImplementationDate: "todos.implementation_date",
+	tododomain.SortByDueDate:            "todos.due_date",
+	tododomain.SortByStatus:             "todos.status_id",
+	tododomain.SortByPriority:           "todos.priority_id",
+}
+
+// DATE型のカラムとは日付の文字列で比較する
+const criteriaDateLayout = "2006-01-02"
+
+// LIKEのエスケープ文字 (MySQLとSQLiteでバックスラッシュの扱いが異なるため)
+const likeEscape = "!"
+
+// buildTodoCriteria は利用者が参照できるtodoに絞るWHERE句・ORDER BY句・LIMIT句とその引数を返す
+func buildTodoCriteria(criteria *tododomain.Criteria, ownerID userdomain.ID) (string, []interface{}) {
+	var (
+		conds = []string{todoScopeCond, trashedCond(criteria.Trashed)}
+		args  = []interface{}{ownerID.Value(), ownerID.Value()}
+	)
+
+	if criteria.ProjectID != 0 {
+		conds = append(conds, "todos.project_id = ?")
+		args = append(args, criteria.ProjectID.Value())
+	}
+
+	if len(criteria.Statuses) > 0 {
+		conds = append(conds, "todos.status_id IN ("+placeholders(len(criteria.Statuses))+")")
+		for _, status := range criteria.Statuses {
+			args = append(args, status.Value())
+		}
+	}
+
+	if len(criteria.Priorities) > 0 {
+		conds = append(conds, "todos.priority_id IN ("+placeholders(len(criteria.Priorities))+")")
+		for _, priority := range criteria.Priorities {
+			args = append(args, priority.Value())
+		}
+	}
+
+	if len(criteria.TagIDs) > 0 {
+		cond := "todos.id IN (SELECT todo_id FROM todo_tags WHERE tag_id IN (" + placeholders(len(criteria.TagIDs)) + ")"
+		for _, tagID := range criteria.TagIDs {
+			args = append(args, tagID.Value())
+		}
+
+		// 全てのタグが付いたtodoは、指定したタグの付いた行数で判定する
+		if criteria.TagMatch == tododomain.TagMatchAll {
+			cond += " GROUP BY todo_id HAVING COUNT(*) = ?"
+			args = append(args, len(criteria.TagIDs))
+		}
+
+		conds = append(conds, cond+")")
+	}
+
+	if !criteria.DueDateFrom.IsZero() {
+		conds = append(conds, "todos.due_date >= ?")
+		args = append(args, criteria.DueDateFrom.Format(criteriaDateLayout))
+	}
+
+	if !criteria.DueDateTo.IsZero() {
+		conds = append(conds, "todos.due_date <= ?")
+		args = append(args, criteria.DueDateTo.Format(criteriaDateLayout))
+	}
+
+	if criteria.Title != "" {
+		conds = append(conds, "todos.title LIKE ? ESCAPE '"+likeEscape+"'")
+		args = append(args, containsPattern(criteria.Title))
+	}
+
+	if criteria.Memo != "" {
+		conds = append(conds, "todos.memo LIKE ? ESCAPE '"+likeEscape+"'")
+		args = append(args, containsPattern(criteria.Memo))
+	}
+
+	column, ok := todoSortColumns[criteria.SortField]
+	if !ok {
+		column = todoSortColumns[tododomain.SortByID]
+	}
+
+	op, direction := ">", "ASC"
+	if criteria.SortOrder == tododomain.Desc {
+		op, direction = "<", "DESC"
+	}
+
+	if criteria.After != nil {
+		if column == todoSortColumns[tododomain.SortByID] {
+			conds = append(conds, "todos.id "+op+" ?")
+			args = append(args, criteria.After.ID.Value())
+		} else {
+			value := criteria.After.Value
+			if t, ok := value.(time.Time); ok {
+				value = t.Format(criteriaDateLayout)
+			}
+
+			conds = append(conds, "("+column+" "+op+" ? OR ("+column+" = ? AND todos.id "+op+" ?))")
+			args = append(args, value, value, criteria.After.ID.Value())
+		}
+	}
+
+	query := "\n        WHERE\n            " + strings.Join(conds, "\n            AND ")
+
+	query += "\n        ORDER BY\n            " + column + " " + direction
+	if column != todoSortColumns[tododomain.SortByID] {
+		query += ",\n            todos.id " + direction
+	}
+
+	if criteria.Limit > 0 {
+		query += "\n        LIMIT ?"
+		args = append(args, criteria.Limit)
+	}
+
+	return query, args
+}
+
+// trashedCond はゴミ箱のtodoのみ、またはゴミ箱にないtodoのみに絞り込む条件を返す
+func trashedCond(trashed bool) string {
+	if trashed {
+		return "todos.deleted_at IS NOT NULL"
+	}
+
+	return "todos.deleted_at IS NULL"
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func containsPattern(s string) string {
+	r := strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+	return "%" + r.Replace(s) + "%"
+}
@@ -0,0 +1,146 @@
+package persistence
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+)
+
+func TestBuildTodoCriteria(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria *tododomain.Criteria
+		// クエリに含まれる条件・並び順 (空白を詰めて比較する)
+		wantParts []string
+		// クエリに含まれない条件
+		notParts []string
+		wantArgs []interface{}
+	}{
+		{
+			name:      "正常系: 条件なし",
+			criteria:  &tododomain.Criteria{},
+			wantParts: []string{"todos.deleted_at IS NULL", "ORDER BY todos.id ASC"},
+			notParts:  []string{"LIMIT", "todos.id >"},
+			wantArgs:  []interface{}{1, 1},
+		},
+		{
+			name: "正常系: 絞り込み条件",
+			criteria: &tododomain.Criteria{
+				Statuses:    []tododomain.Status{tododomain.TODO, tododomain.DONE},
+				Priorities:  []tododomain.Priority{tododomain.HIGH},
+				DueDateFrom: date(2022, 4, 1),
+				DueDateTo:   date(2022, 4, 30),
+				ProjectID:   3,
+				Limit:       11,
+				Trashed:     true,
+			},
+			wantParts: []string{
+				"todos.deleted_at IS NOT NULL",
+				"todos.project_id = ?",
+				"todos.status_id IN (?, ?)",
+				"todos.priority_id IN (?)",
+				"todos.due_date >= ?",
+				"todos.due_date <= ?",
+				"LIMIT ?",
+			},
+			wantArgs: []interface{}{1, 1, 3, uint(1), uint(3), uint(4), "2022-04-01", "2022-04-30", 11},
+		},
+		{
+			name:      "正常系: LIKEのワイルドカードとエスケープ文字をエスケープする",
+			criteria:  &tododomain.Criteria{Title: "100%_!", Memo: "a"},
+			wantParts: []string{"todos.title LIKE ? ESCAPE '!'", "todos.memo LIKE ? ESCAPE '!'"},
+			wantArgs:  []interface{}{1, 1, "%100!%!_!!%", "%a%"},
+		},
+		{
+			name:      "正常系: いずれかのタグ",
+			criteria:  &tododomain.Criteria{TagIDs: []tododomain.TagID{1, 2}, TagMatch: tododomain.TagMatchAny},
+			wantParts: []string{"todos.id IN (SELECT todo_id FROM todo_tags WHERE tag_id IN (?, ?))"},
+			notParts:  []string{"HAVING"},
+			wantArgs:  []interface{}{1, 1, 1, 2},
+		},
+		{
+			name:      "正常系: 全てのタグ",
+			criteria:  &tododomain.Criteria{TagIDs: []tododomain.TagID{1, 2}, TagMatch: tododomain.TagMatchAll},
+			wantParts: []string{"tag_id IN (?, ?) GROUP BY todo_id HAVING COUNT(*) = ?)"},
+			wantArgs:  []interface{}{1, 1, 1, 2, 2},
+		},
+		{
+			name:      "正常系: IDの昇順のカーソル",
+			criteria:  &tododomain.Criteria{SortField: tododomain.SortByID, After: &tododomain.Cursor{ID: 5, Value: 5}, Limit: 3},
+			wantParts: []string{"todos.id > ?", "ORDER BY todos.id ASC LIMIT ?"},
+			wantArgs:  []interface{}{1, 1, 5, 3},
+		},
+		{
+			name:      "正常系: IDの降順のカーソル",
+			criteria:  &tododomain.Criteria{SortOrder: tododomain.Desc, After: &tododomain.Cursor{ID: 5, Value: 5}},
+			wantParts: []string{"todos.id < ?", "ORDER BY todos.id DESC"},
+			wantArgs:  []interface{}{1, 1, 5},
+		},
+		{
+			name:      "正常系: 同じ値はIDで順序を決める",
+			criteria:  &tododomain.Criteria{SortField: tododomain.SortByTitle, After: &tododomain.Cursor{ID: 5, Value: "b"}},
+			wantParts: []string{"(todos.title > ? OR (todos.title = ? AND todos.id > ?))", "ORDER BY todos.title ASC, todos.id ASC"},
+			wantArgs:  []interface{}{1, 1, "b", "b", 5},
+		},
+		{
+			name:      "正常系: 日付の降順のカーソルは日付の文字列で比較する",
+			criteria:  &tododomain.Criteria{SortField: tododomain.SortByDueDate, SortOrder: tododomain.Desc, After: &tododomain.Cursor{ID: 5, Value: date(2022, 4, 2)}},
+			wantParts: []string{"(todos.due_date < ? OR (todos.due_date = ? AND todos.id < ?))", "ORDER BY todos.due_date DESC, todos.id DESC"},
+			wantArgs:  []interface{}{1, 1, "2022-04-02", "2022-04-02", 5},
+		},
+		{
+			name:      "正常系: 未知のソート項目はIDで並べる",
+			criteria:  &tododomain.Criteria{SortField: "unknown"},
+			wantParts: []string{"ORDER BY todos.id ASC"},
+			notParts:  []string{"unknown"},
+			wantArgs:  []interface{}{1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := buildTodoCriteria(tt.criteria, 1)
+			query = strings.Join(strings.Fields(query), " ")
+
+			if !strings.HasPrefix(query, "WHERE "+strings.Join(strings.Fields(todoScopeCond), " ")) {
+				t.Errorf("query does not start with the scope condition: %s", query)
+			}
+			for _, part := range tt.wantParts {
+				if !strings.Contains(query, part) {
+					t.Errorf("query = %s, want containing %q", query, part)
+				}
+			}
+			for _, part := range tt.notParts {
+				if strings.Contains(query, part) {
+					t.Errorf("query = %s, want not containing %q", query, part)
+				}
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, tt.wantArgs)
+			}
+			if n := strings.Count(query, "?"); n != len(args) {
+				t.Errorf("placeholders = %d, args = %d", n, len(args))
+			}
+		})
+	}
+}
+
+func TestNgramTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms []string
+		want  []string
+	}{
+		{name: "正常系: 2文字ずつに分割", terms: []string{"東京都"}, want: []string{"東京", "京都"}},
+		{name: "正常系: 分割単位以下はそのまま", terms: []string{"東", "東京"}, want: []string{"東", "東京"}},
+		{name: "正常系: 複数のキーワード", terms: []string{"abc", "東京都"}, want: []string{"ab", "bc", "東京", "京都"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ngramTokens(tt.terms, 2); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ngramTokens() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
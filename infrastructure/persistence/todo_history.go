@@ -0,0 +1,54 @@
+package persistence
+
+import (
+	"encoding/json"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/datasource"
+)
+
+// 変更内容は項目ごとに型が異なるためJSONで保存する
+
+func toHistoryChangesJSON(changes []tododomain.FieldChange) (string, error) {
+	changesDto := make([]datasource.HistoryChange, len(changes))
+	for i, change := range changes {
+		changesDto[i] = datasource.HistoryChange{
+			Field:  change.Field,
+			Before: change.Before,
+			After:  change.After,
+		}
+	}
+
+	b, err := json.Marshal(changesDto)
+	if err != nil {
+		return "", apperrors.InternalServerError.Wrap(err)
+	}
+
+	return string(b), nil
+}
+
+func toHistoryDomain(historyDto datasource.TodoHistory) (*tododomain.History, error) {
+	var changesDto []datasource.HistoryChange
+	if err := json.Unmarshal([]byte(historyDto.Changes), &changesDto); err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	changes := make([]tododomain.FieldChange, len(changesDto))
+	for i, changeDto := range changesDto {
+		changes[i] = tododomain.FieldChange{
+			Field:  changeDto.Field,
+			Before: changeDto.Before,
+			After:  changeDto.After,
+		}
+	}
+
+	return tododomain.NewHistory(
+		tododomain.ID(historyDto.TodoID),
+		tododomain.HistoryAction(historyDto.Action),
+		changes,
+		historyDto.Actor,
+		historyDto.RequestID,
+		historyDto.CreatedAt,
+	), nil
+}
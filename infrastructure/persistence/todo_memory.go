@@ -0,0 +1,577 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+// memoryStore はtodoと変更履歴を保持する。トランザクション中は書き込みロックを取り続ける。
+type memoryStore struct {
+	mu        sync.RWMutex
+	lastID    int
+	todos     map[int]*tododomain.Todo
+	histories map[int][]*tododomain.History
+	// todoのIDごとの所有者と所属するプロジェクト。完全に削除したtodoの変更履歴も絞り込むため削除しない。
+	owners map[int]todoOwner
+
+	lastProjectID int
+	projects      map[int]*projectdomain.Project
+	// プロジェクトのIDごとのメンバーの権限
+	members map[int]map[userdomain.ID]projectdomain.Role
+
+	lastTagID int
+	tags      map[int]memoryTag
+
+	lastChecklistItemID int
+}
+
+type memoryTag struct {
+	ownerID userdomain.ID
+	tag     *tododomain.Tag
+}
+
+type todoOwner struct {
+	userID    userdomain.ID
+	projectID projectdomain.ID
+}
+
+type todoMemoryRepository struct {
+	*memoryStore
+	// トランザクション中のリポジトリは既にロックを取っているためロックしない
+	inTx bool
+}
+
+func NewTodoMemoryRepository() *todoMemoryRepository {
+	return &todoMemoryRepository{
+		memoryStore: &memoryStore{
+			todos:     make(map[int]*tododomain.Todo),
+			histories: make(map[int][]*tododomain.History),
+			owners:    make(map[int]todoOwner),
+			projects:  make(map[int]*projectdomain.Project),
+			members:   make(map[int]map[userdomain.ID]projectdomain.Role),
+			tags:      make(map[int]memoryTag),
+		},
+	}
+}
+
+// lock は書き込みロックを取り、解放する関数を返す
+func (r *todoMemoryRepository) lock() func() {
+	if r.inTx {
+		return func() {}
+	}
+
+	r.mu.Lock()
+	return r.mu.Unlock
+}
+
+// rlock は読み込みロックを取り、解放する関数を返す
+func (r *todoMemoryRepository) rlock() func() {
+	if r.inTx {
+		return func() {}
+	}
+
+	r.mu.RLock()
+	return r.mu.RUnlock
+}
+
+// accessible はtodoを利用者が読み書きできるかを返す。
+// 個人のtodoは所有者のみ、プロジェクトのtodoはプロジェクトのメンバーが読み書きできる。
+func (r *todoMemoryRepository) accessible(id int, userID userdomain.ID) bool {
+	owner, ok := r.owners[id]
+	if !ok {
+		return false
+	}
+
+	if owner.projectID == 0 {
+		return owner.userID == userID
+	}
+
+	_, ok = r.members[owner.projectID.Value()][userID]
+	return ok
+}
+
+func (r *todoMemoryRepository) CreateTodo(ctx context.Context, todo *tododomain.Todo) (tododomain.ID, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer r.lock()()
+
+	r.lastID++
+
+	idVo, err := tododomain.NewID(r.lastID)
+	if err != nil {
+		return 0, apperrors.InternalServerError.Wrap(err)
+	}
+
+	todoDm, err := r.storedTodo(idVo, todo, todo.Version())
+	if err != nil {
+		return 0, err
+	}
+
+	r.todos[idVo.Value()] = todoDm
+	r.owners[idVo.Value()] = todoOwner{userID: ownerID, projectID: todo.ProjectID()}
+
+	return idVo, nil
+}
+
+func (r *todoMemoryRepository) FetchTodoByID(ctx context.Context, id tododomain.ID) (*tododomain.Todo, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.rlock()()
+
+	return r.fetchTodoByID(id, ownerID, false)
+}
+
+func (r *todoMemoryRepository) FetchTrashedTodoByID(ctx context.Context, id tododomain.ID) (*tododomain.Todo, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.rlock()()
+
+	return r.fetchTodoByID(id, ownerID, true)
+}
+
+func (r *todoMemoryRepository) fetchTodoByID(id tododomain.ID, ownerID userdomain.ID, trashed bool) (*tododomain.Todo, error) {
+	todo, ok := r.todos[id.Value()]
+	if !ok || !r.accessible(id.Value(), ownerID) || todo.IsTrashed() != trashed {
+		return nil, apperrors.TodoNotFound
+	}
+
+	return copyTodo(todo.ID(), todo, todo.Version()), nil
+}
+
+func (r *todoMemoryRepository) FetchTodos(ctx context.Context, criteria *tododomain.Criteria) ([]*tododomain.Todo, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.rlock()()
+
+	todoDms := make([]*tododomain.Todo, 0, len(r.todos))
+	for id, todo := range r.todos {
+		if !r.accessible(id, ownerID) || !matchTodoCriteria(todo, criteria) {
+			continue
+		}
+
+		todoDms = append(todoDms, copyTodo(todo.ID(), todo, todo.Version()))
+	}
+
+	sort.Slice(todoDms, func(i, j int) bool {
+		return compareTodo(todoDms[i], todoDms[j].SortKey(criteria.SortField), todoDms[j].ID(), criteria) < 0
+	})
+
+	if criteria.Limit > 0 && len(todoDms) > criteria.Limit {
+		todoDms = todoDms[:criteria.Limit]
+	}
+
+	return todoDms, nil
+}
+
+func (r *todoMemoryRepository) SearchTodos(ctx context.Context, query tododomain.SearchQuery, limit int) ([]*tododomain.SearchResult, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.rlock()()
+
+	todoDms := make([]*tododomain.Todo, 0, len(r.todos))
+	for id, todo := range r.todos {
+		if !r.accessible(id, ownerID) || todo.IsTrashed() {
+			continue
+		}
+
+		todoDms = append(todoDms, copyTodo(todo.ID(), todo, todo.Version()))
+	}
+
+	return rankTodos(todoDms, query, limit), nil
+}
+
+func (r *todoMemoryRepository) UpdateTodo(ctx context.Context, todo *tododomain.Todo) (tododomain.ID, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer r.lock()()
+
+	current, ok := r.todos[todo.ID().Value()]
+	if !ok || !r.accessible(todo.ID().Value(), ownerID) {
+		return 0, apperrors.TodoNotFound
+	}
+
+	// 取得後に他の更新が入りバージョンが変わっている
+	if current.Version() != todo.Version() {
+		return 0, apperrors.PreconditionFailed
+	}
+
+	todoDm, err := r.storedTodo(todo.ID(), todo, todo.Version()+1)
+	if err != nil {
+		return 0, err
+	}
+
+	r.todos[todo.ID().Value()] = todoDm
+
+	return todo.ID(), nil
+}
+
+func (r *todoMemoryRepository) PurgeTodos(ctx context.Context, deletedBefore time.Time, projectIDs []projectdomain.ID) (int, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	purgeable := map[projectdomain.ID]bool{0: true}
+	for _, projectID := range projectIDs {
+		purgeable[projectID] = true
+	}
+
+	defer r.lock()()
+
+	var count int
+	for id, todo := range r.todos {
+		if r.accessible(id, ownerID) && purgeable[r.owners[id].projectID] && todo.IsTrashed() && todo.DeletedAt().Before(deletedBefore) {
+			delete(r.todos, id)
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *todoMemoryRepository) FetchHistories(ctx context.Context, todoID tododomain.ID) ([]*tododomain.History, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.rlock()()
+
+	if !r.accessible(todoID.Value(), ownerID) {
+		return []*tododomain.History{}, nil
+	}
+
+	histories := make([]*tododomain.History, len(r.histories[todoID.Value()]))
+	copy(histories, r.histories[todoID.Value()])
+
+	return histories, nil
+}
+
+func (r *todoMemoryRepository) CreateHistory(ctx context.Context, history *tododomain.History) error {
+	if _, err := ownerID(ctx); err != nil {
+		return err
+	}
+
+	defer r.lock()()
+
+	todoID := history.TodoID().Value()
+	r.histories[todoID] = append(r.histories[todoID], history)
+
+	return nil
+}
+
+func (r *todoMemoryRepository) CreateTodos(ctx context.Context, todos []*tododomain.Todo) ([]tododomain.ID, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.lock()()
+
+	ids := make([]tododomain.ID, len(todos))
+	todoDms := make([]*tododomain.Todo, len(todos))
+	for i, todo := range todos {
+		idVo, err := tododomain.NewID(r.lastID + i + 1)
+		if err != nil {
+			return nil, apperrors.InternalServerError.Wrap(err)
+		}
+
+		todoDm, err := r.storedTodo(idVo, todo, todo.Version())
+		if err != nil {
+			return nil, err
+		}
+
+		ids[i], todoDms[i] = idVo, todoDm
+	}
+
+	// 全件のコピーに成功してから保持する
+	for _, todoDm := range todoDms {
+		r.todos[todoDm.ID().Value()] = todoDm
+		r.owners[todoDm.ID().Value()] = todoOwner{userID: ownerID, projectID: todoDm.ProjectID()}
+	}
+	r.lastID += len(todos)
+
+	return ids, nil
+}
+
+func (r *todoMemoryRepository) FetchTodosByIDs(ctx context.Context, ids []tododomain.ID) ([]*tododomain.Todo, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.rlock()()
+
+	var todoDms []*tododomain.Todo
+	for _, id := range ids {
+		todoDm, err := r.fetchTodoByID(id, ownerID, false)
+		if err == apperrors.TodoNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		todoDms = append(todoDms, todoDm)
+	}
+
+	sort.Slice(todoDms, func(i, j int) bool {
+		return todoDms[i].ID() < todoDms[j].ID()
+	})
+
+	return todoDms, nil
+}
+
+func (r *todoMemoryRepository) UpdateTodos(ctx context.Context, todos []*tododomain.Todo) ([]tododomain.ID, error) {
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.lock()()
+
+	// 全件を確認してから更新する
+	var (
+		stale   []tododomain.ID
+		todoDms []*tododomain.Todo
+	)
+	for _, todo := range todos {
+		current, ok := r.todos[todo.ID().Value()]
+		if !ok || !r.accessible(todo.ID().Value(), ownerID) || current.Version() != todo.Version() {
+			stale = append(stale, todo.ID())
+			continue
+		}
+
+		todoDm, err := r.storedTodo(todo.ID(), todo, todo.Version()+1)
+		if err != nil {
+			return nil, err
+		}
+
+		todoDms = append(todoDms, todoDm)
+	}
+
+	for _, todoDm := range todoDms {
+		r.todos[todoDm.ID().Value()] = todoDm
+	}
+
+	return stale, nil
+}
+
+func (r *todoMemoryRepository) CreateHistories(ctx context.Context, histories []*tododomain.History) error {
+	if _, err := ownerID(ctx); err != nil {
+		return err
+	}
+
+	defer r.lock()()
+
+	for _, history := range histories {
+		todoID := history.TodoID().Value()
+		r.histories[todoID] = append(r.histories[todoID], history)
+	}
+
+	return nil
+}
+
+// storedTodo は保持するtodoのコピーを返す。追加したチェックリストの項目にはIDを採番する
+func (r *todoMemoryRepository) storedTodo(id tododomain.ID, todo *tododomain.Todo, version tododomain.Version) (*tododomain.Todo, error) {
+	checklist := todo.Checklist()
+	for i, item := range checklist {
+		if item.ID() != 0 {
+			continue
+		}
+
+		itemID, err := tododomain.NewChecklistItemID(r.lastChecklistItemID + 1)
+		if err != nil {
+			return nil, apperrors.InternalServerError.Wrap(err)
+		}
+		r.lastChecklistItemID++
+
+		checklist[i] = tododomain.NewChecklistItem(itemID, item.Title(), item.IsDone())
+	}
+
+	return copyTodoWith(id, todo, version, todo.Tags(), checklist), nil
+}
+
+// 呼び出し元との間でポインタを共有しないようにコピーを保持・返却する
+func copyTodo(id tododomain.ID, todo *tododomain.Todo, version tododomain.Version) *tododomain.Todo {
+	return copyTodoWith(id, todo, version, todo.Tags(), todo.Checklist())
+}
+
+// copyTodoWith はタグとチェックリストを差し替えてtodoをコピーする
+func copyTodoWith(
+	id tododomain.ID,
+	todo *tododomain.Todo,
+	version tododomain.Version,
+	tags []*tododomain.Tag,
+	checklist []*tododomain.ChecklistItem,
+) *tododomain.Todo {
+	return tododomain.NewTodo(tododomain.TodoParams{
+		ID:                 id,
+		Title:              todo.Title(),
+		ImplementationDate: todo.ImplementationDate(),
+		DueDate:            todo.DueDate(),
+		Status:             todo.Status(),
+		Priority:           todo.Priority(),
+		Memo:               todo.Memo(),
+		Version:            version,
+		DeletedAt:          todo.DeletedAt(),
+		ProjectID:          todo.ProjectID(),
+		Tags:               tags,
+		Checklist:          checklist,
+	})
+}
+
+func matchTodoCriteria(todo *tododomain.Todo, criteria *tododomain.Criteria) bool {
+	if todo.IsTrashed() != criteria.Trashed {
+		return false
+	}
+
+	if len(criteria.Statuses) > 0 && !containsStatus(criteria.Statuses, todo.Status()) {
+		return false
+	}
+
+	if len(criteria.Priorities) > 0 && !containsPriority(criteria.Priorities, todo.Priority()) {
+		return false
+	}
+
+	dueDate := todo.DueDate().Value().Format(criteriaDateLayout)
+	if !criteria.DueDateFrom.IsZero() && dueDate < criteria.DueDateFrom.Format(criteriaDateLayout) {
+		return false
+	}
+
+	if !criteria.DueDateTo.IsZero() && dueDate > criteria.DueDateTo.Format(criteriaDateLayout) {
+		return false
+	}
+
+	if criteria.Title != "" && !containsFold(todo.Title().Value(), criteria.Title) {
+		return false
+	}
+
+	if criteria.Memo != "" && !containsFold(todo.Memo().Value(), criteria.Memo) {
+		return false
+	}
+
+	if criteria.ProjectID != 0 && todo.ProjectID() != criteria.ProjectID {
+		return false
+	}
+
+	if len(criteria.TagIDs) > 0 && !matchTags(todo.Tags(), criteria.TagIDs, criteria.TagMatch) {
+		return false
+	}
+
+	if criteria.After != nil && compareTodo(todo, criteria.After.Value, criteria.After.ID, criteria) <= 0 {
+		return false
+	}
+
+	return true
+}
+
+// compareTodo はソート順でtodoが(value, id)より前なら負、後ろなら正を返す
+func compareTodo(todo *tododomain.Todo, value interface{}, id tododomain.ID, criteria *tododomain.Criteria) int {
+	c := compareSortKey(todo.SortKey(criteria.SortField), value)
+	if c == 0 {
+		c = compareSortKey(todo.ID().Value(), id.Value())
+	}
+
+	if criteria.SortOrder == tododomain.Desc {
+		return -c
+	}
+
+	return c
+}
+
+func compareSortKey(a, b interface{}) int {
+	switch a := a.(type) {
+	case int:
+		return compareOrdered(a, b.(int))
+	case uint:
+		return compareOrdered(a, b.(uint))
+	case string:
+		return compareOrdered(a, b.(string))
+	case time.Time:
+		// DATE型と同様に日付単位で比較する
+		return compareOrdered(a.Format(criteriaDateLayout), b.(time.Time).Format(criteriaDateLayout))
+	}
+
+	return 0
+}
+
+func compareOrdered[T int | uint | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func containsStatus(statuses []tododomain.Status, status tododomain.Status) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsPriority(priorities []tododomain.Priority, priority tododomain.Priority) bool {
+	for _, p := range priorities {
+		if p == priority {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchTags はtodoのタグが指定したタグのいずれか、または全てを含むかを返す
+func matchTags(tags []*tododomain.Tag, tagIDs []tododomain.TagID, match tododomain.TagMatch) bool {
+	var count int
+	for _, tagID := range tagIDs {
+		for _, tag := range tags {
+			if tag.ID() == tagID {
+				count++
+				break
+			}
+		}
+	}
+
+	if match == tododomain.TagMatchAll {
+		return count == len(tagIDs)
+	}
+
+	return count > 0
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,188 @@
+package persistence
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+)
+
+func TestTodoMemoryRepository(t *testing.T) {
+	testTodoRepository(t, func(t *testing.T) (tododomain.Repository, tododomain.TransactionManager) {
+		repo := NewTodoMemoryRepository()
+		return repo, NewMemoryTransactionManager(repo)
+	})
+}
+
+func TestTodoMemoryRepository_ReturnsCopies(t *testing.T) {
+	repo := NewTodoMemoryRepository()
+	ctx := userContext(1)
+
+	todo := newUnCreatedTodo(t, "title")
+	id, err := repo.CreateTodo(ctx, todo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 登録に渡したtodoや取得したtodoを変更しても保持している値は変わらない
+	todo.ChangeTitle("changed by caller")
+	fetched, err := repo.FetchTodoByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetched.ChangeTitle("changed after fetch")
+
+	got, err := repo.FetchTodoByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title() != "title" {
+		t.Errorf("FetchTodoByID() title = %s, want title", got.Title())
+	}
+}
+
+// go test -race で実行し、ロックの漏れがないことも確認する
+func TestTodoMemoryRepository_Concurrent(t *testing.T) {
+	repo := NewTodoMemoryRepository()
+	ctx := userContext(1)
+	txManager := NewMemoryTransactionManager(repo)
+
+	id, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, "counter"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		workers = 10
+		creates = 20
+	)
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		ids = map[tododomain.ID]bool{id: true}
+		// 登録時にコピーするため、同じtodoを並行して登録に渡せる
+		todo = newUnCreatedTodo(t, "title")
+	)
+	errCh := make(chan error, workers*2)
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < creates; i++ {
+				created, err := repo.CreateTodo(ctx, todo)
+				if err != nil {
+					errCh <- err
+					return
+				}
+
+				mu.Lock()
+				ids[created] = true
+				mu.Unlock()
+
+				if _, err = repo.FetchTodos(ctx, &tododomain.Criteria{SortField: tododomain.SortByTitle, Limit: 10}); err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}()
+
+		// 取得から更新までをトランザクションで行えば、同時に更新しても更新が失われない
+		go func() {
+			defer wg.Done()
+
+			err := txManager.Transaction(ctx, func(repo tododomain.Repository) error {
+				todo, err := repo.FetchTodoByID(ctx, id)
+				if err != nil {
+					return err
+				}
+
+				todo.ChangeMemo("updated")
+				_, err = repo.UpdateTodo(ctx, todo)
+				return err
+			})
+			if err != nil {
+				errCh <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		t.Fatal(err)
+	}
+
+	if len(ids) != workers*creates+1 {
+		t.Errorf("unique ids = %d, want %d", len(ids), workers*creates+1)
+	}
+
+	got, err := repo.FetchTodoByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := tododomain.InitialVersion + workers; got.Version() != want {
+		t.Errorf("Version() = %d, want %d", got.Version(), want)
+	}
+}
+
+func TestMemoryTransactionManager_Rollback(t *testing.T) {
+	repo := NewTodoMemoryRepository()
+	ctx := userContext(1)
+	txManager := NewMemoryTransactionManager(repo)
+
+	// 失敗したトランザクションで採番したIDは戻し、次の作成で同じIDを使う
+	err := txManager.Transaction(ctx, func(repo tododomain.Repository) error {
+		id, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, "title"))
+		if err != nil {
+			return err
+		}
+
+		todo, err := repo.FetchTodoByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err = todo.AddChecklistItem("item"); err != nil {
+			return err
+		}
+		if _, err = repo.UpdateTodo(ctx, todo); err != nil {
+			return err
+		}
+
+		return apperrors.PreconditionFailed
+	})
+	if !errors.Is(err, apperrors.PreconditionFailed) {
+		t.Fatalf("Transaction() error = %v, wantErr %v", err, apperrors.PreconditionFailed)
+	}
+
+	id, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, "title"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 1 {
+		t.Errorf("CreateTodo() id = %d, want 1", id)
+	}
+
+	todo, err := repo.FetchTodoByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = todo.AddChecklistItem("item"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = repo.UpdateTodo(ctx, todo); err != nil {
+		t.Fatal(err)
+	}
+
+	todo, err = repo.FetchTodoByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := todo.Checklist()[0].ID(); got != 1 {
+		t.Errorf("Checklist()[0].ID() = %d, want 1", got)
+	}
+}
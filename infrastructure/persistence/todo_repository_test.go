@@ -0,0 +1,504 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+// リポジトリの実装ごとに振る舞いが揃っていることを確認する共通のテスト。
+// 各実装のテストから、ユーザーID 1, 2 のユーザーが登録済みのリポジトリを渡して呼び出す。
+
+type todoRepositoryFactory func(t *testing.T) (tododomain.Repository, tododomain.TransactionManager)
+
+func userContext(id userdomain.ID) context.Context {
+	return userdomain.ContextWithUserID(context.Background(), id)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func newUnCreatedTodo(t *testing.T, title tododomain.Title) *tododomain.Todo {
+	t.Helper()
+
+	todo, err := tododomain.NewTodoWhenUnCreated(title, tododomain.ImplementationDate(date(2022, 4, 1)), tododomain.DueDate(date(2022, 4, 2)), tododomain.LOW, "memo", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return todo
+}
+
+func testTodoRepository(t *testing.T, newRepo todoRepositoryFactory) {
+	t.Run("作成したtodoを取得できる", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		ctx := userContext(1)
+
+		id, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, "title"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := repo.FetchTodoByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID() != id || got.Title() != "title" || got.Memo() != "memo" || got.Status() != tododomain.TODO || got.Priority() != tododomain.LOW {
+			t.Errorf("FetchTodoByID() = %+v", got)
+		}
+		if !got.ImplementationDate().Value().Equal(date(2022, 4, 1)) || !got.DueDate().Value().Equal(date(2022, 4, 2)) {
+			t.Errorf("FetchTodoByID() dates = %v, %v", got.ImplementationDate(), got.DueDate())
+		}
+		if got.Version() != tododomain.InitialVersion || got.IsTrashed() {
+			t.Errorf("FetchTodoByID() version = %d, trashed = %v", got.Version(), got.IsTrashed())
+		}
+	})
+
+	t.Run("IDは作成順に採番し、一覧はIDの昇順で返す", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		ctx := userContext(1)
+
+		var ids []tododomain.ID
+		for _, title := range []tododomain.Title{"a", "b", "c"} {
+			id, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, title))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+
+		todos, err := repo.FetchTodos(ctx, &tododomain.Criteria{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(todos) != len(ids) {
+			t.Fatalf("len(FetchTodos()) = %d, want %d", len(todos), len(ids))
+		}
+		for i, want := range []tododomain.Title{"a", "b", "c"} {
+			if todos[i].ID() != ids[i] || todos[i].Title() != want {
+				t.Errorf("FetchTodos()[%d] = %d %s, want %d %s", i, todos[i].ID(), todos[i].Title(), ids[i], want)
+			}
+		}
+	})
+
+	t.Run("まとめて作成したtodoは引数の順に採番したIDを返す", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		ctx := userContext(1)
+
+		first, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, "first"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids, err := repo.CreateTodos(ctx, []*tododomain.Todo{newUnCreatedTodo(t, "a"), newUnCreatedTodo(t, "b"), newUnCreatedTodo(t, "c")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 3 {
+			t.Fatalf("len(CreateTodos()) = %d, want 3", len(ids))
+		}
+
+		// 採番の間隔はDBの設定によるため、連番であることは求めない
+		prev := first
+		for i, id := range ids {
+			if id <= prev {
+				t.Errorf("CreateTodos() ids[%d] = %d, want greater than %d", i, id, prev)
+			}
+			prev = id
+		}
+
+		// 返したIDと登録した内容が対応している
+		todos, err := repo.FetchTodosByIDs(ctx, ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, want := range []tododomain.Title{"a", "b", "c"} {
+			if todos[i].ID() != ids[i] || todos[i].Title() != want {
+				t.Errorf("FetchTodosByIDs()[%d] = %d %s, want %d %s", i, todos[i].ID(), todos[i].Title(), ids[i], want)
+			}
+		}
+	})
+
+	t.Run("更新するとバージョンが上がり、古いバージョンでの更新はPreconditionFailed", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		ctx := userContext(1)
+
+		id, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, "title"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		todo, err := repo.FetchTodoByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stale, err := repo.FetchTodoByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		todo.ChangeTitle("changed")
+		if _, err = repo.UpdateTodo(ctx, todo); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := repo.FetchTodoByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Title() != "changed" || got.Version() != tododomain.InitialVersion+1 {
+			t.Errorf("FetchTodoByID() = %s version %d, want changed version %d", got.Title(), got.Version(), tododomain.InitialVersion+1)
+		}
+
+		stale.ChangeTitle("stale")
+		if _, err = repo.UpdateTodo(ctx, stale); !errors.Is(err, apperrors.PreconditionFailed) {
+			t.Errorf("UpdateTodo() error = %v, wantErr %v", err, apperrors.PreconditionFailed)
+		}
+
+		// まとめて更新すると古いバージョンのtodoのみ更新せずにIDを返す
+		otherID, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, "other"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		other, err := repo.FetchTodoByID(ctx, otherID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		other.ChangeTitle("other changed")
+
+		staleIDs, err := repo.UpdateTodos(ctx, []*tododomain.Todo{stale, other})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(staleIDs) != 1 || staleIDs[0] != id {
+			t.Errorf("UpdateTodos() stale = %v, want [%d]", staleIDs, id)
+		}
+
+		todos, err := repo.FetchTodosByIDs(ctx, []tododomain.ID{id, otherID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var titles []tododomain.Title
+		for _, todo := range todos {
+			titles = append(titles, todo.Title())
+		}
+		if len(titles) != 2 || titles[0] != "changed" || titles[1] != "other changed" {
+			t.Errorf("FetchTodosByIDs() titles = %v, want [changed other changed]", titles)
+		}
+	})
+
+	t.Run("ゴミ箱に移動したtodoは期限を過ぎると完全に削除する", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		ctx := userContext(1)
+
+		ids := make([]tododomain.ID, 2)
+		for i, title := range []tododomain.Title{"old", "new"} {
+			id, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, title))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids[i] = id
+		}
+
+		now := time.Date(2022, 4, 10, 12, 0, 0, 0, time.UTC)
+		for i, deletedAt := range []time.Time{now.Add(-48 * time.Hour), now} {
+			todo, err := repo.FetchTodoByID(ctx, ids[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			todo.Trash(deletedAt)
+			if _, err = repo.UpdateTodo(ctx, todo); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if _, err := repo.FetchTodoByID(ctx, ids[0]); !errors.Is(err, apperrors.TodoNotFound) {
+			t.Errorf("FetchTodoByID() trashed error = %v, wantErr %v", err, apperrors.TodoNotFound)
+		}
+		trashed, err := repo.FetchTrashedTodoByID(ctx, ids[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if trashed.DeletedAt() == nil || !trashed.DeletedAt().Equal(now.Add(-48*time.Hour)) {
+			t.Errorf("FetchTrashedTodoByID() deletedAt = %v", trashed.DeletedAt())
+		}
+
+		// 他のユーザーのゴミ箱は削除しない
+		if n, err := repo.PurgeTodos(userContext(2), now.Add(-24*time.Hour), nil); err != nil || n != 0 {
+			t.Errorf("PurgeTodos() other user = %d, %v, want 0", n, err)
+		}
+
+		n, err := repo.PurgeTodos(ctx, now.Add(-24*time.Hour), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Errorf("PurgeTodos() = %d, want 1", n)
+		}
+		if _, err = repo.FetchTrashedTodoByID(ctx, ids[0]); !errors.Is(err, apperrors.TodoNotFound) {
+			t.Errorf("FetchTrashedTodoByID() purged error = %v, wantErr %v", err, apperrors.TodoNotFound)
+		}
+		if _, err = repo.FetchTrashedTodoByID(ctx, ids[1]); err != nil {
+			t.Errorf("FetchTrashedTodoByID() error = %v", err)
+		}
+	})
+
+	t.Run("チェックリストは項目のIDと並び順を保ったまま保存する", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		ctx := userContext(1)
+
+		id, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, "title"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		todo, err := repo.FetchTodoByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, title := range []tododomain.ChecklistItemTitle{"a", "b"} {
+			if err = todo.AddChecklistItem(title); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err = repo.UpdateTodo(ctx, todo); err != nil {
+			t.Fatal(err)
+		}
+
+		todo, err = repo.FetchTodoByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		items := todo.Checklist()
+		if len(items) != 2 || items[0].ID() == 0 || items[1].ID() == 0 {
+			t.Fatalf("Checklist() = %v", items)
+		}
+		a, b := items[0].ID(), items[1].ID()
+
+		if err = todo.ReorderChecklist([]tododomain.ChecklistItemID{b, a}); err != nil {
+			t.Fatal(err)
+		}
+		if err = todo.CheckChecklistItem(a, true); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = repo.UpdateTodo(ctx, todo); err != nil {
+			t.Fatal(err)
+		}
+
+		todo, err = repo.FetchTodoByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		items = todo.Checklist()
+		if len(items) != 2 || items[0].ID() != b || items[1].ID() != a || items[0].IsDone() || !items[1].IsDone() {
+			t.Errorf("Checklist() after reorder = %v", items)
+		}
+		if todo.Progress() != 50 {
+			t.Errorf("Progress() = %d, want 50", todo.Progress())
+		}
+	})
+
+	t.Run("変更履歴は古い順に返す", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		ctx := userContext(1)
+
+		id, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, "title"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		createdAt := time.Date(2022, 4, 1, 9, 0, 0, 0, time.UTC)
+		histories := []*tododomain.History{
+			tododomain.NewHistory(id, tododomain.HistoryCreate, []tododomain.FieldChange{{Field: "title", After: "title"}}, "user:1", "req-1", createdAt),
+			tododomain.NewHistory(id, tododomain.HistoryUpdate, []tododomain.FieldChange{{Field: "title", Before: "title", After: "changed"}}, "user:1", "req-2", createdAt.Add(time.Minute)),
+		}
+		if err = repo.CreateHistory(ctx, histories[0]); err != nil {
+			t.Fatal(err)
+		}
+		if err = repo.CreateHistories(ctx, histories[1:]); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := repo.FetchHistories(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("len(FetchHistories()) = %d, want 2", len(got))
+		}
+		for i, want := range histories {
+			if got[i].Action() != want.Action() || got[i].RequestID() != want.RequestID() || !got[i].CreatedAt().Equal(want.CreatedAt()) {
+				t.Errorf("FetchHistories()[%d] = %s %s %v, want %s %s %v", i, got[i].Action(), got[i].RequestID(), got[i].CreatedAt(), want.Action(), want.RequestID(), want.CreatedAt())
+			}
+		}
+		if len(got[1].Changes()) != 1 || got[1].Changes()[0].Before != "title" || got[1].Changes()[0].After != "changed" {
+			t.Errorf("FetchHistories()[1].Changes() = %+v", got[1].Changes())
+		}
+
+		// 他のユーザーには見えない
+		other, err := repo.FetchHistories(userContext(2), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(other) != 0 {
+			t.Errorf("len(FetchHistories()) other user = %d, want 0", len(other))
+		}
+	})
+
+	t.Run("存在しないtodoと他のユーザーのtodoはTodoNotFound", func(t *testing.T) {
+		repo, _ := newRepo(t)
+
+		id, err := repo.CreateTodo(userContext(1), newUnCreatedTodo(t, "title"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			name string
+			ctx  context.Context
+			id   tododomain.ID
+		}{
+			{name: "異常系: 存在しないID", ctx: userContext(1), id: id + 100},
+			{name: "異常系: 他のユーザーのtodo", ctx: userContext(2), id: id},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, err := repo.FetchTodoByID(tt.ctx, tt.id); !errors.Is(err, apperrors.TodoNotFound) {
+					t.Errorf("FetchTodoByID() error = %v, wantErr %v", err, apperrors.TodoNotFound)
+				}
+				if _, err := repo.FetchTrashedTodoByID(tt.ctx, tt.id); !errors.Is(err, apperrors.TodoNotFound) {
+					t.Errorf("FetchTrashedTodoByID() error = %v, wantErr %v", err, apperrors.TodoNotFound)
+				}
+
+				todo := tododomain.NewTodo(tododomain.TodoParams{ID: tt.id, Title: "x", Version: tododomain.InitialVersion})
+				if _, err := repo.UpdateTodo(tt.ctx, todo); !errors.Is(err, apperrors.TodoNotFound) {
+					t.Errorf("UpdateTodo() error = %v, wantErr %v", err, apperrors.TodoNotFound)
+				}
+			})
+		}
+
+		if _, err := repo.FetchTodoByID(userContext(1), id); err != nil {
+			t.Errorf("FetchTodoByID() owner error = %v", err)
+		}
+		if _, err := repo.FetchTodoByID(context.Background(), id); !errors.Is(err, apperrors.Unauthorized) {
+			t.Errorf("FetchTodoByID() without user error = %v, wantErr %v", err, apperrors.Unauthorized)
+		}
+	})
+
+	t.Run("キーセットページングは同じ値をIDで並べ、重複も欠落もなく全件を返す", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		ctx := userContext(1)
+
+		titles := []tododomain.Title{"b", "a", "b", "c", "a", "b"}
+		ids := make([]tododomain.ID, len(titles))
+		for i, title := range titles {
+			id, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, title))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids[i] = id
+		}
+		// 他のユーザーのtodoは含まない
+		if _, err := repo.CreateTodo(userContext(2), newUnCreatedTodo(t, "a")); err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			name  string
+			order tododomain.SortOrder
+			want  []tododomain.ID
+		}{
+			{name: "昇順", order: tododomain.Asc, want: []tododomain.ID{ids[1], ids[4], ids[0], ids[2], ids[5], ids[3]}},
+			{name: "降順", order: tododomain.Desc, want: []tododomain.ID{ids[3], ids[5], ids[2], ids[0], ids[4], ids[1]}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				criteria := &tododomain.Criteria{SortField: tododomain.SortByTitle, SortOrder: tt.order, Limit: 4}
+
+				var got []tododomain.ID
+				for page := 0; page < len(tt.want); page++ {
+					list, err := repo.FetchTodos(ctx, criteria)
+					if err != nil {
+						t.Fatal(err)
+					}
+					for _, todo := range list {
+						got = append(got, todo.ID())
+					}
+					if len(list) < criteria.Limit {
+						break
+					}
+					last := list[len(list)-1]
+					criteria.After = &tododomain.Cursor{ID: last.ID(), Value: last.SortKey(criteria.SortField)}
+				}
+
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("FetchTodos() ids = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("トランザクションが失敗すると書き込みを取り消す", func(t *testing.T) {
+		repo, txManager := newRepo(t)
+		ctx := userContext(1)
+
+		id, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, "title"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		errRollback := errors.New("rollback")
+		var createdID tododomain.ID
+		err = txManager.Transaction(ctx, func(repo tododomain.Repository) error {
+			todo, err := repo.FetchTodoByID(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			todo.ChangeTitle("changed")
+			if _, err = repo.UpdateTodo(ctx, todo); err != nil {
+				return err
+			}
+
+			if createdID, err = repo.CreateTodo(ctx, newUnCreatedTodo(t, "created")); err != nil {
+				return err
+			}
+
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("Transaction() error = %v, wantErr %v", err, errRollback)
+		}
+
+		got, err := repo.FetchTodoByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Title() != "title" || got.Version() != tododomain.InitialVersion {
+			t.Errorf("FetchTodoByID() = %s version %d, want rolled back", got.Title(), got.Version())
+		}
+		if _, err = repo.FetchTodoByID(ctx, createdID); !errors.Is(err, apperrors.TodoNotFound) {
+			t.Errorf("FetchTodoByID() created in rolled back transaction error = %v, wantErr %v", err, apperrors.TodoNotFound)
+		}
+
+		// コミットしたトランザクションの書き込みは残る
+		err = txManager.Transaction(ctx, func(repo tododomain.Repository) error {
+			createdID, err = repo.CreateTodo(ctx, newUnCreatedTodo(t, "committed"))
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = repo.FetchTodoByID(ctx, createdID); err != nil {
+			t.Errorf("FetchTodoByID() committed error = %v", err)
+		}
+	})
+}
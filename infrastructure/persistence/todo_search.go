@@ -0,0 +1,54 @@
+package persistence
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+)
+
+// MySQLのFULLTEXTインデックスを使えないリポジトリ向けの全文検索の代替実装。
+// キーワードの出現回数(タイトルはメモの2倍の重み)を関連度とする。SQLiteは同じ計算をSQLで行う。
+
+const titleScoreWeight = 2
+
+func rankTodos(todos []*tododomain.Todo, query tododomain.SearchQuery, limit int) []*tododomain.SearchResult {
+	terms := query.Terms()
+
+	results := make([]*tododomain.SearchResult, 0, len(todos))
+	for _, todo := range todos {
+		if score := scoreTodo(todo, terms); score > 0 {
+			results = append(results, &tododomain.SearchResult{
+				Todo:  todo,
+				Score: score,
+			})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+
+		return results[i].Todo.ID() < results[j].Todo.ID()
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+func scoreTodo(todo *tododomain.Todo, terms []string) float64 {
+	title := strings.ToLower(todo.Title().Value())
+	memo := strings.ToLower(todo.Memo().Value())
+
+	var score float64
+	for _, term := range terms {
+		term = strings.ToLower(term)
+		score += float64(titleScoreWeight*strings.Count(title, term) + strings.Count(memo, term))
+	}
+
+	return score
+}
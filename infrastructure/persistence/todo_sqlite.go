@@ -0,0 +1,201 @@
+package persistence
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/datasource"
+	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
+)
+
+// MySQLのDATE型に合わせて日付のみを保存する
+const sqliteDateLayout = "2006-01-02"
+
+// DATETIME型のカラムは文字列の大小で比較できるようにUTCで保存する
+const sqliteDateTimeLayout = "2006-01-02 15:04:05"
+
+type todoSQLiteRepository struct {
+	*rdb.SQLiteHandler
+	// トランザクション中のリポジトリではnil以外
+	tx *sqlx.Tx
+}
+
+func NewTodoSQLiteRepository(sqliteHandler *rdb.SQLiteHandler) *todoSQLiteRepository {
+	return &todoSQLiteRepository{SQLiteHandler: sqliteHandler}
+}
+
+// ext はトランザクション中ならトランザクションを、そうでなければ接続を返す
+func (r *todoSQLiteRepository) ext() sqlx.ExtContext {
+	if r.tx != nil {
+		return r.tx
+	}
+
+	return r.Conn
+}
+
+func (r *todoSQLiteRepository) CreateTodo(ctx context.Context, todo *tododomain.Todo) (tododomain.ID, error) {
+	ids, err := createTodos(ctx, r.ext(), []*tododomain.Todo{todo}, sqliteTodoValues)
+	if err != nil {
+		return 0, err
+	}
+
+	return ids[0], nil
+}
+
+func (r *todoSQLiteRepository) FetchTodoByID(ctx context.Context, id tododomain.ID) (*tododomain.Todo, error) {
+	return fetchTodoByID(ctx, r.ext(), id, false)
+}
+
+func (r *todoSQLiteRepository) FetchTrashedTodoByID(ctx context.Context, id tododomain.ID) (*tododomain.Todo, error) {
+	return fetchTodoByID(ctx, r.ext(), id, true)
+}
+
+func (r *todoSQLiteRepository) FetchTodos(ctx context.Context, criteria *tododomain.Criteria) ([]*tododomain.Todo, error) {
+	return fetchTodos(ctx, r.ext(), criteria)
+}
+
+// SQLiteではいずれかのキーワードを含むtodoをLIKEで絞り込み、キーワードの出現回数を関連度として並び替える。
+// 関連度の計算はメモリの実装(rankTodos)と同じ。SQLiteのlowerとLIKEはASCII以外の大文字小文字を区別する。
+func (r *todoSQLiteRepository) SearchTodos(ctx context.Context, query tododomain.SearchQuery, limit int) ([]*tododomain.SearchResult, error) {
+	var (
+		conds     []string
+		scores    []string
+		condArgs  []interface{}
+		scoreArgs []interface{}
+	)
+	for _, term := range query.Terms() {
+		conds = append(conds, "todos.title LIKE ? ESCAPE '"+likeEscape+"' OR todos.memo LIKE ? ESCAPE '"+likeEscape+"'")
+		condArgs = append(condArgs, containsPattern(term), containsPattern(term))
+
+		lower := strings.ToLower(term)
+		scores = append(scores, strconv.Itoa(titleScoreWeight)+" * "+sqliteCountTerm("todos.title")+" + "+sqliteCountTerm("todos.memo"))
+		scoreArgs = append(scoreArgs, lower, lower, lower, lower)
+	}
+
+	searchQuery := `
+        SELECT
+            ` + todoSelectColumns + `,
+            ` + strings.Join(scores, "\n            + ") + ` score
+        FROM
+            todos
+        INNER JOIN
+            statuses
+        ON
+            statuses.id = todos.status_id
+        INNER JOIN
+            priorities
+        ON
+            priorities.id = todos.priority_id
+        WHERE
+            (` + strings.Join(conds, "\n            OR ") + `)
+        AND
+            ` + todoScopeCond + `
+        AND
+            ` + trashedCond(false) + `
+        ORDER BY
+            score DESC,
+            todos.id
+        LIMIT ?`
+
+	ownerID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	args := append(scoreArgs, condArgs...)
+	args = append(args, ownerID.Value(), ownerID.Value(), limit)
+
+	var resultsDto []datasource.TodoSearchResult
+	if err = sqlx.SelectContext(ctx, r.ext(), &resultsDto, searchQuery, args...); err != nil {
+		return nil, dbError(err)
+	}
+
+	todosDto := make([]datasource.Todo, len(resultsDto))
+	for i, resultDto := range resultsDto {
+		todosDto[i] = resultDto.Todo
+	}
+
+	todoDms, err := toTodoDomains(ctx, r.ext(), todosDto)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*tododomain.SearchResult, len(resultsDto))
+	for i, resultDto := range resultsDto {
+		results[i] = &tododomain.SearchResult{
+			Todo:  todoDms[i],
+			Score: resultDto.Score,
+		}
+	}
+
+	return results, nil
+}
+
+// sqliteCountTerm はcolumnに小文字にしたキーワードが出現する回数を求める式を返す。引数にキーワードを2回渡す
+func sqliteCountTerm(column string) string {
+	return "(LENGTH(LOWER(" + column + ")) - LENGTH(REPLACE(LOWER(" + column + "), ?, ''))) / LENGTH(?)"
+}
+
+func (r *todoSQLiteRepository) UpdateTodo(ctx context.Context, todo *tododomain.Todo) (tododomain.ID, error) {
+	return updateTodo(ctx, r.ext(), todo, sqliteTodoValues)
+}
+
+func (r *todoSQLiteRepository) PurgeTodos(ctx context.Context, deletedBefore time.Time, projectIDs []projectdomain.ID) (int, error) {
+	return purgeTodos(ctx, r.ext(), sqliteDateTime(&deletedBefore), projectIDs)
+}
+
+func (r *todoSQLiteRepository) FetchHistories(ctx context.Context, todoID tododomain.ID) ([]*tododomain.History, error) {
+	return fetchHistories(ctx, r.ext(), todoID)
+}
+
+func (r *todoSQLiteRepository) CreateHistory(ctx context.Context, history *tododomain.History) error {
+	return createHistories(ctx, r.ext(), []*tododomain.History{history}, sqliteHistoryCreatedAt)
+}
+
+func (r *todoSQLiteRepository) CreateTodos(ctx context.Context, todos []*tododomain.Todo) ([]tododomain.ID, error) {
+	return createTodos(ctx, r.ext(), todos, sqliteTodoValues)
+}
+
+func (r *todoSQLiteRepository) FetchTodosByIDs(ctx context.Context, ids []tododomain.ID) ([]*tododomain.Todo, error) {
+	// 接続が1本のためトランザクションは直列に実行され、行のロックは不要
+	return fetchTodosByIDs(ctx, r.ext(), ids, false)
+}
+
+func (r *todoSQLiteRepository) UpdateTodos(ctx context.Context, todos []*tododomain.Todo) ([]tododomain.ID, error) {
+	return updateTodos(ctx, r.ext(), todos, sqliteTodoValues)
+}
+
+func (r *todoSQLiteRepository) CreateHistories(ctx context.Context, histories []*tododomain.History) error {
+	return createHistories(ctx, r.ext(), histories, sqliteHistoryCreatedAt)
+}
+
+func sqliteDateTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+
+	return t.UTC().Format(sqliteDateTimeLayout)
+}
+
+func sqliteTodoValues(todo *tododomain.Todo) []interface{} {
+	return []interface{}{
+		todo.Title().Value(),
+		todo.ImplementationDate().Value().Format(sqliteDateLayout),
+		todo.DueDate().Value().Format(sqliteDateLayout),
+		todo.Status().Value(),
+		todo.Priority().Value(),
+		todo.Memo().Value(),
+		sqliteDateTime(todo.DeletedAt()),
+	}
+}
+
+func sqliteHistoryCreatedAt(history *tododomain.History) interface{} {
+	createdAt := history.CreatedAt()
+	return sqliteDateTime(&createdAt)
+}
@@ -0,0 +1,92 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/config"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
+)
+
+// newSQLiteTestHandler はテストごとに別の:memory:のDBを作り、ユーザーID 1, 2 のユーザーを登録する
+func newSQLiteTestHandler(t *testing.T) *rdb.SQLiteHandler {
+	t.Helper()
+
+	handler, err := rdb.NewSQLiteHandler(config.DB{SQLitePath: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = handler.Conn.Close() })
+
+	userRepo := NewUserSQLiteRepository(handler)
+	for _, email := range []userdomain.Email{"a@example.com", "b@example.com"} {
+		if _, err = userRepo.CreateUser(context.Background(), userdomain.NewUserWhenUnCreated(email, "hash", time.Now())); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return handler
+}
+
+func TestTodoSQLiteRepository(t *testing.T) {
+	testTodoRepository(t, func(t *testing.T) (tododomain.Repository, tododomain.TransactionManager) {
+		handler := newSQLiteTestHandler(t)
+		return NewTodoSQLiteRepository(handler), NewSQLiteTransactionManager(handler)
+	})
+}
+
+func TestTodoSQLiteRepository_SearchTodos(t *testing.T) {
+	repo := NewTodoSQLiteRepository(newSQLiteTestHandler(t))
+	ctx := userContext(1)
+
+	for _, title := range []tododomain.Title{"買い物", "100%達成", "買い物リストの買い物", "Go言語"} {
+		if _, err := repo.CreateTodo(ctx, newUnCreatedTodo(t, title)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		query     tododomain.SearchQuery
+		limit     int
+		want      []tododomain.Title
+		wantScore float64
+	}{
+		{name: "正常系: 出現回数の多い順", query: "買い物", limit: 10, want: []tododomain.Title{"買い物リストの買い物", "買い物"}, wantScore: 4},
+		{name: "正常系: 件数の上限", query: "買い物", limit: 1, want: []tododomain.Title{"買い物リストの買い物"}},
+		{name: "正常系: ワイルドカードの文字はそのまま検索する", query: "%", limit: 10, want: []tododomain.Title{"100%達成"}},
+		{name: "正常系: 大文字小文字を区別しない", query: "gO", limit: 10, want: []tododomain.Title{"Go言語"}, wantScore: 2},
+		{name: "正常系: 該当なし", query: "掃除", limit: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := repo.SearchTodos(ctx, tt.query, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(results) != len(tt.want) {
+				t.Fatalf("len(SearchTodos()) = %d, want %d", len(results), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if results[i].Todo.Title() != want {
+					t.Errorf("SearchTodos()[%d] = %s, want %s", i, results[i].Todo.Title(), want)
+				}
+			}
+			if tt.wantScore > 0 && results[0].Score != tt.wantScore {
+				t.Errorf("SearchTodos()[0].Score = %v, want %v", results[0].Score, tt.wantScore)
+			}
+		})
+	}
+
+	results, err := repo.SearchTodos(userContext(2), "買い物", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("len(SearchTodos()) other user = %d, want 0", len(results))
+	}
+}
@@ -0,0 +1,130 @@
+package persistence
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/datasource"
+)
+
+// MySQL・SQLiteで共通のtodoに付けたタグの読み書き。
+// タグはtodoとは別の文で取得するため、todoの行を読み終えてから呼び出す。
+
+// fetchTodoTags はtodoのIDごとに付けたタグを返す
+func fetchTodoTags(ctx context.Context, conn sqlx.QueryerContext, todoIDs []int) (map[int][]*tododomain.Tag, error) {
+	tags := make(map[int][]*tododomain.Tag, len(todoIDs))
+	if len(todoIDs) == 0 {
+		return tags, nil
+	}
+
+	fetchQuery := `
+        SELECT
+            todo_tags.todo_id todo_id,
+            tags.id           id,
+            tags.name         name
+        FROM
+            todo_tags
+        INNER JOIN
+            tags
+        ON
+            tags.id = todo_tags.tag_id
+        WHERE
+            todo_tags.todo_id IN (` + placeholders(len(todoIDs)) + `)`
+
+	args := make([]interface{}, len(todoIDs))
+	for i, id := range todoIDs {
+		args[i] = id
+	}
+
+	var todoTagsDto []datasource.TodoTag
+	if err := sqlx.SelectContext(ctx, conn, &todoTagsDto, fetchQuery, args...); err != nil {
+		return nil, dbError(err)
+	}
+
+	for _, todoTagDto := range todoTagsDto {
+		tagDm, err := toTagDomain(todoTagDto.Tag)
+		if err != nil {
+			return nil, err
+		}
+
+		tags[todoTagDto.TodoID] = append(tags[todoTagDto.TodoID], tagDm)
+	}
+
+	return tags, nil
+}
+
+// saveTodoTags はタグが変わったtodoについて、付けたタグを置き換える。todosとidsは同じ並びで渡す
+func saveTodoTags(ctx context.Context, conn sqlx.ExecerContext, ids []tododomain.ID, todos []*tododomain.Todo) error {
+	var changedIDs []tododomain.ID
+	var changed []*tododomain.Todo
+	for i, todo := range todos {
+		if todo.IsTagsChanged() {
+			changedIDs = append(changedIDs, ids[i])
+			changed = append(changed, todo)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	return replaceTodoTags(ctx, conn, changedIDs, changed)
+}
+
+func replaceTodoTags(ctx context.Context, conn sqlx.ExecerContext, ids []tododomain.ID, todos []*tododomain.Todo) error {
+	deleteArgs := make([]interface{}, len(ids))
+	for i, id := range ids {
+		deleteArgs[i] = id.Value()
+	}
+
+	deleteQuery := "DELETE FROM todo_tags WHERE todo_id IN (" + placeholders(len(ids)) + ")"
+	if _, err := conn.ExecContext(ctx, deleteQuery, deleteArgs...); err != nil {
+		return dbError(err)
+	}
+
+	var (
+		rows []string
+		args []interface{}
+	)
+	for i, todo := range todos {
+		for _, tag := range todo.Tags() {
+			rows = append(rows, "(?, ?)")
+			args = append(args, ids[i].Value(), tag.ID().Value())
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	insertQuery := `
+        INSERT INTO todo_tags
+        (
+          todo_id,
+          tag_id
+        )
+        VALUES
+          ` + strings.Join(rows, ",\n          ")
+
+	if _, err := conn.ExecContext(ctx, insertQuery, args...); err != nil {
+		return dbError(err)
+	}
+
+	return nil
+}
+
+func toTagDomain(tagDto datasource.Tag) (*tododomain.Tag, error) {
+	idVo, err := tododomain.NewTagID(tagDto.ID)
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	nameVo, err := tododomain.NewTagName(tagDto.Name)
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	return tododomain.NewTag(idVo, nameVo), nil
+}
@@ -0,0 +1,190 @@
+package persistence
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
+)
+
+type transactionManager struct {
+	*rdb.MySQLHandler
+}
+
+func NewTransactionManager(mysqlHandler *rdb.MySQLHandler) *transactionManager {
+	return &transactionManager{mysqlHandler}
+}
+
+func (m *transactionManager) Transaction(ctx context.Context, fn func(repo tododomain.Repository) error) error {
+	return runInTx(ctx, m.Conn, func(tx *sqlx.Tx) error {
+		return fn(&todoRepository{MySQLHandler: m.MySQLHandler, tx: tx})
+	})
+}
+
+type sqliteTransactionManager struct {
+	*rdb.SQLiteHandler
+}
+
+func NewSQLiteTransactionManager(sqliteHandler *rdb.SQLiteHandler) *sqliteTransactionManager {
+	return &sqliteTransactionManager{sqliteHandler}
+}
+
+func (m *sqliteTransactionManager) Transaction(ctx context.Context, fn func(repo tododomain.Repository) error) error {
+	return runInTx(ctx, m.Conn, func(tx *sqlx.Tx) error {
+		return fn(&todoSQLiteRepository{SQLiteHandler: m.SQLiteHandler, tx: tx})
+	})
+}
+
+type projectTransactionManager struct {
+	*rdb.MySQLHandler
+}
+
+func NewProjectTransactionManager(mysqlHandler *rdb.MySQLHandler) *projectTransactionManager {
+	return &projectTransactionManager{mysqlHandler}
+}
+
+func (m *projectTransactionManager) Transaction(ctx context.Context, fn func(repo projectdomain.Repository) error) error {
+	return runInTx(ctx, m.Conn, func(tx *sqlx.Tx) error {
+		return fn(&projectRepository{MySQLHandler: m.MySQLHandler, tx: tx})
+	})
+}
+
+type projectSQLiteTransactionManager struct {
+	*rdb.SQLiteHandler
+}
+
+func NewProjectSQLiteTransactionManager(sqliteHandler *rdb.SQLiteHandler) *projectSQLiteTransactionManager {
+	return &projectSQLiteTransactionManager{sqliteHandler}
+}
+
+func (m *projectSQLiteTransactionManager) Transaction(ctx context.Context, fn func(repo projectdomain.Repository) error) error {
+	return runInTx(ctx, m.Conn, func(tx *sqlx.Tx) error {
+		return fn(&projectSQLiteRepository{SQLiteHandler: m.SQLiteHandler, tx: tx})
+	})
+}
+
+// runInTx はfnがエラーを返すかpanicした場合はロールバックし、そうでなければコミットする
+func runInTx(ctx context.Context, conn *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return dbError(err)
+	}
+	// コミット後のロールバックは何もしない
+	defer func() { _ = tx.Rollback() }()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return dbError(err)
+	}
+
+	return nil
+}
+
+// lockClause はforUpdateならSELECTに付けて行ロックを取る句を返す。tablesを指定すると、結合したテーブルのうちそのテーブルの行のみをロックする
+func lockClause(forUpdate bool, tables ...string) string {
+	if !forUpdate {
+		return ""
+	}
+
+	if len(tables) > 0 {
+		return " FOR UPDATE OF " + strings.Join(tables, ", ")
+	}
+
+	return " FOR UPDATE"
+}
+
+type memoryTransactionManager struct {
+	*memoryStore
+}
+
+func NewMemoryTransactionManager(todoMemoryRepository *todoMemoryRepository) *memoryTransactionManager {
+	return &memoryTransactionManager{todoMemoryRepository.memoryStore}
+}
+
+// Transaction は書き込みロックを取ったままfnを実行し、失敗した場合は開始時点の状態に戻す
+func (m *memoryTransactionManager) Transaction(ctx context.Context, fn func(repo tododomain.Repository) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// 保持しているtodoと変更履歴は置き換えるだけで変更しないため、mapの浅いコピーで戻せる
+	lastID, lastChecklistItemID := m.lastID, m.lastChecklistItemID
+	todos := make(map[int]*tododomain.Todo, len(m.todos))
+	for id, todo := range m.todos {
+		todos[id] = todo
+	}
+	histories := make(map[int][]*tododomain.History, len(m.histories))
+	for id, h := range m.histories {
+		histories[id] = h
+	}
+	owners := make(map[int]todoOwner, len(m.owners))
+	for id, owner := range m.owners {
+		owners[id] = owner
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			m.lastID, m.todos, m.histories, m.owners = lastID, todos, histories, owners
+			m.lastChecklistItemID = lastChecklistItemID
+		}
+	}()
+
+	if err := fn(&todoMemoryRepository{memoryStore: m.memoryStore, inTx: true}); err != nil {
+		return err
+	}
+
+	committed = true
+
+	return nil
+}
+
+type projectMemoryTransactionManager struct {
+	*memoryStore
+}
+
+func NewProjectMemoryTransactionManager(todoMemoryRepository *todoMemoryRepository) *projectMemoryTransactionManager {
+	return &projectMemoryTransactionManager{todoMemoryRepository.memoryStore}
+}
+
+// Transaction は書き込みロックを取ったままfnを実行し、失敗した場合は開始時点の状態に戻す
+func (m *projectMemoryTransactionManager) Transaction(ctx context.Context, fn func(repo projectdomain.Repository) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// メンバーはプロジェクトごとのmapを書き換えるため、内側のmapもコピーする
+	lastProjectID := m.lastProjectID
+	projects := make(map[int]*projectdomain.Project, len(m.projects))
+	for id, project := range m.projects {
+		projects[id] = project
+	}
+	members := make(map[int]map[userdomain.ID]projectdomain.Role, len(m.members))
+	for id, roles := range m.members {
+		members[id] = make(map[userdomain.ID]projectdomain.Role, len(roles))
+		for userID, role := range roles {
+			members[id][userID] = role
+		}
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			m.lastProjectID, m.projects, m.members = lastProjectID, projects, members
+		}
+	}()
+
+	if err := fn(&projectMemoryRepository{memoryStore: m.memoryStore, inTx: true}); err != nil {
+		return err
+	}
+
+	committed = true
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/xerrors"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/datasource"
+	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
+)
+
+type userRepository struct {
+	*rdb.MySQLHandler
+}
+
+func NewUserRepository(mysqlHandler *rdb.MySQLHandler) *userRepository {
+	return &userRepository{mysqlHandler}
+}
+
+func (r *userRepository) CreateUser(ctx context.Context, user *userdomain.User) (userdomain.ID, error) {
+	return createUser(ctx, r.Conn, user, user.CreatedAt())
+}
+
+func (r *userRepository) FetchUserByID(ctx context.Context, id userdomain.ID) (*userdomain.User, error) {
+	return fetchUser(ctx, r.Conn, "id = ?", id.Value())
+}
+
+func (r *userRepository) FetchUserByEmail(ctx context.Context, email userdomain.Email) (*userdomain.User, error) {
+	return fetchUser(ctx, r.Conn, "email = ?", email.Value())
+}
+
+func (r *userRepository) ClaimOwnerlessTodos(ctx context.Context, ownerID userdomain.ID) (int64, error) {
+	return claimOwnerlessTodos(ctx, r.Conn, ownerID)
+}
+
+// createUser はMySQL・SQLiteで共通のユーザーの登録
+func createUser(ctx context.Context, conn sqlx.ExecerContext, user *userdomain.User, createdAt interface{}) (userdomain.ID, error) {
+	query := `
+        INSERT INTO users
+        (
+          email,
+          password_hash,
+          created_at
+        )
+        VALUES
+          (?, ?, ?)`
+
+	result, err := conn.ExecContext(ctx, query, user.Email().Value(), user.PasswordHash().Value(), createdAt)
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	idVo, err := userdomain.NewID(int(id))
+	if err != nil {
+		return 0, apperrors.InternalServerError.Wrap(err)
+	}
+
+	return idVo, nil
+}
+
+// claimOwnerlessTodos はユーザー管理の導入前に作成された所有者のないtodoと変更履歴をownerIDの個人のものにし、引き継いだtodoの件数を返す
+func claimOwnerlessTodos(ctx context.Context, conn *sqlx.DB, ownerID userdomain.ID) (int64, error) {
+	var claimed int64
+	err := runInTx(ctx, conn, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, "UPDATE todos SET owner_id = ? WHERE owner_id IS NULL AND project_id IS NULL", ownerID.Value())
+		if err != nil {
+			return dbError(err)
+		}
+
+		if claimed, err = result.RowsAffected(); err != nil {
+			return dbError(err)
+		}
+
+		if _, err = tx.ExecContext(ctx, "UPDATE todo_histories SET owner_id = ? WHERE owner_id IS NULL AND project_id IS NULL", ownerID.Value()); err != nil {
+			return dbError(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return claimed, nil
+}
+
+// fetchUser はMySQL・SQLiteで共通のユーザーの取得
+func fetchUser(ctx context.Context, conn sqlx.QueryerContext, cond string, arg interface{}) (*userdomain.User, error) {
+	fetchQuery := `
+        SELECT
+            id,
+            email,
+            password_hash,
+            created_at
+        FROM
+            users
+        WHERE
+            ` + cond
+
+	var userDto datasource.User
+	if err := conn.QueryRowxContext(ctx, fetchQuery, arg).StructScan(&userDto); err != nil {
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.UserNotFound
+		}
+
+		return nil, dbError(err)
+	}
+
+	return toUserDomain(userDto)
+}
+
+func toUserDomain(userDto datasource.User) (*userdomain.User, error) {
+	idVo, err := userdomain.NewID(userDto.ID)
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	emailVo, err := userdomain.NewEmail(userDto.Email)
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	return userdomain.NewUser(idVo, emailVo, userdomain.PasswordHash(userDto.PasswordHash), userDto.CreatedAt), nil
+}
@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+type userMemoryRepository struct {
+	mu     sync.RWMutex
+	lastID int
+	users  map[int]*userdomain.User
+}
+
+func NewUserMemoryRepository() *userMemoryRepository {
+	return &userMemoryRepository{
+		users: make(map[int]*userdomain.User),
+	}
+}
+
+func (r *userMemoryRepository) CreateUser(ctx context.Context, user *userdomain.User) (userdomain.ID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		if u.Email() == user.Email() {
+			return 0, apperrors.Conflict
+		}
+	}
+
+	r.lastID++
+
+	idVo, err := userdomain.NewID(r.lastID)
+	if err != nil {
+		return 0, apperrors.InternalServerError.Wrap(err)
+	}
+
+	r.users[idVo.Value()] = userdomain.NewUser(idVo, user.Email(), user.PasswordHash(), user.CreatedAt())
+
+	return idVo, nil
+}
+
+func (r *userMemoryRepository) FetchUserByID(ctx context.Context, id userdomain.ID) (*userdomain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id.Value()]
+	if !ok {
+		return nil, apperrors.UserNotFound
+	}
+
+	return user, nil
+}
+
+func (r *userMemoryRepository) FetchUserByEmail(ctx context.Context, email userdomain.Email) (*userdomain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email() == email {
+			return user, nil
+		}
+	}
+
+	return nil, apperrors.UserNotFound
+}
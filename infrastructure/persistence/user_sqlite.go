@@ -0,0 +1,33 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
+)
+
+type userSQLiteRepository struct {
+	*rdb.SQLiteHandler
+}
+
+func NewUserSQLiteRepository(sqliteHandler *rdb.SQLiteHandler) *userSQLiteRepository {
+	return &userSQLiteRepository{sqliteHandler}
+}
+
+func (r *userSQLiteRepository) CreateUser(ctx context.Context, user *userdomain.User) (userdomain.ID, error) {
+	createdAt := user.CreatedAt()
+	return createUser(ctx, r.Conn, user, sqliteDateTime(&createdAt))
+}
+
+func (r *userSQLiteRepository) FetchUserByID(ctx context.Context, id userdomain.ID) (*userdomain.User, error) {
+	return fetchUser(ctx, r.Conn, "id = ?", id.Value())
+}
+
+func (r *userSQLiteRepository) FetchUserByEmail(ctx context.Context, email userdomain.Email) (*userdomain.User, error) {
+	return fetchUser(ctx, r.Conn, "email = ?", email.Value())
+}
+
+func (r *userSQLiteRepository) ClaimOwnerlessTodos(ctx context.Context, ownerID userdomain.ID) (int64, error) {
+	return claimOwnerlessTodos(ctx, r.Conn, ownerID)
+}
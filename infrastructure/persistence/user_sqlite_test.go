@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/config"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
+)
+
+func TestUserSQLiteRepository_CreateUser(t *testing.T) {
+	handler, err := rdb.NewSQLiteHandler(config.DB{SQLitePath: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = handler.Conn.Close() })
+
+	// ユーザー管理の導入前に作成された所有者のないtodoと変更履歴
+	result, err := handler.Conn.Exec(`
+        INSERT INTO todos (title, implementation_date, due_date, status_id, priority_id, memo)
+        VALUES ('legacy', '2022-04-01', '2022-04-02', 1, 1, '')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacyID, err := result.LastInsertId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = handler.Conn.Exec(`
+        INSERT INTO todo_histories (todo_id, action, changes, actor, request_id, created_at)
+        VALUES (?, 'create', '[]', 'anonymous', 'request', '2022-04-01 00:00:00')`, legacyID); err != nil {
+		t.Fatal(err)
+	}
+
+	userRepo := NewUserSQLiteRepository(handler)
+	todoRepo := NewTodoSQLiteRepository(handler)
+	newUser := func(email userdomain.Email) *userdomain.User {
+		return userdomain.NewUserWhenUnCreated(email, "hash", time.Now())
+	}
+
+	first, err := userRepo.CreateUser(context.Background(), newUser("a@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := userRepo.CreateUser(context.Background(), newUser("b@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = userRepo.CreateUser(context.Background(), newUser("a@example.com")); !errors.Is(err, apperrors.Conflict) {
+		t.Errorf("CreateUser() duplicate email error = %v, wantErr %v", err, apperrors.Conflict)
+	}
+
+	// 登録しただけでは引き継がない
+	if _, err = todoRepo.FetchTodoByID(userContext(first), tododomain.ID(legacyID)); !errors.Is(err, apperrors.TodoNotFound) {
+		t.Fatalf("FetchTodoByID() before claim error = %v, wantErr %v", err, apperrors.TodoNotFound)
+	}
+
+	claimed, err := userRepo.ClaimOwnerlessTodos(context.Background(), second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claimed != 1 {
+		t.Errorf("ClaimOwnerlessTodos() = %d, want 1", claimed)
+	}
+
+	tests := []struct {
+		name          string
+		userID        userdomain.ID
+		wantErr       error
+		wantHistories int
+	}{
+		{name: "正常系: 指定したユーザーの個人のtodoになる", userID: second, wantHistories: 1},
+		{name: "異常系: 他のユーザーには見えない", userID: first, wantErr: apperrors.TodoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := userContext(tt.userID)
+
+			_, err := todoRepo.FetchTodoByID(ctx, tododomain.ID(legacyID))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FetchTodoByID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			histories, err := todoRepo.FetchHistories(ctx, tododomain.ID(legacyID))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(histories) != tt.wantHistories {
+				t.Errorf("len(FetchHistories()) = %d, want %d", len(histories), tt.wantHistories)
+			}
+		})
+	}
+
+	// 引き継ぎ済みのtodoは再度引き継がない
+	if claimed, err = userRepo.ClaimOwnerlessTodos(context.Background(), first); err != nil || claimed != 0 {
+		t.Errorf("ClaimOwnerlessTodos() again = %d, %v, want 0", claimed, err)
+	}
+}
@@ -0,0 +1,46 @@
+package rdb
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migration はスキーマを変更する <番号>_<名前>.sql のファイル
+type migration struct {
+	version int
+	file    string
+}
+
+// loadMigrations はdirにあるマイグレーションを番号順に返す
+func loadMigrations(fsys fs.FS, dir string) ([]migration, error) {
+	files, err := fs.Glob(fsys, dir+"/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]migration, 0, len(files))
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(path.Base(file), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s migration file name: %s", dir, file)
+		}
+
+		migrations = append(migrations, migration{version: version, file: file})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].version == migrations[i-1].version {
+			return nil, fmt.Errorf("duplicate %s migration version: %s, %s", dir, migrations[i-1].file, migrations[i].file)
+		}
+	}
+
+	return migrations, nil
+}
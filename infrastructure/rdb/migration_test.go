@@ -0,0 +1,96 @@
+package rdb
+
+import (
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fs.FS
+		want    []migration
+		wantErr string
+	}{
+		{
+			name: "正常系: 番号順に並べる",
+			fsys: fstest.MapFS{
+				"db/10_c.sql":  {},
+				"db/2_b.sql":   {},
+				"db/1_a.sql":   {},
+				"db/README.md": {},
+			},
+			want: []migration{{1, "db/1_a.sql"}, {2, "db/2_b.sql"}, {10, "db/10_c.sql"}},
+		},
+		{
+			name: "正常系: ファイルなし",
+			fsys: fstest.MapFS{},
+			want: []migration{},
+		},
+		{
+			name:    "異常系: 番号のないファイル",
+			fsys:    fstest.MapFS{"db/create_tables.sql": {}},
+			wantErr: "invalid db migration file name: db/create_tables.sql",
+		},
+		{
+			name:    "異常系: 番号の重複",
+			fsys:    fstest.MapFS{"db/1_a.sql": {}, "db/01_b.sql": {}},
+			wantErr: "duplicate db migration version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadMigrations(tt.fsys, "db")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadMigrations() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadMigrations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// 埋め込んだマイグレーションは1から欠番なく並び、空のファイルがないこと
+func TestEmbeddedMigrations(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		fsys fs.ReadFileFS
+	}{
+		{name: "mysql", fsys: mysqlMigrationFS},
+		{name: "sqlite", fsys: sqliteInitFS},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadMigrations(tt.fsys, tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(migrations) == 0 {
+				t.Fatal("no migrations")
+			}
+
+			for i, m := range migrations {
+				if m.version != i+1 {
+					t.Errorf("migrations[%d] = %s, want version %d", i, m.file, i+1)
+				}
+
+				query, err := tt.fsys.ReadFile(m.file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if strings.TrimSpace(string(query)) == "" {
+					t.Errorf("%s is empty", m.file)
+				}
+			}
+		})
+	}
+}
@@ -1,25 +1,126 @@
 package rdb
 
 import (
-	_ "github.com/go-sql-driver/mysql"
+	"embed"
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
+
+	"github.com/kazumakawahara/todo-sample/config"
 )
 
+//go:embed mysql/*.sql
+var mysqlMigrationFS embed.FS
+
+// 複数のプロセスが同時に起動してもマイグレーションを1回だけ実行するためのロック
+const mysqlMigrationLock = "todo-sample.migrations"
+
 type MySQLHandler struct {
 	Conn *sqlx.DB
 }
 
-func NewMySQLHandler() (*MySQLHandler, error) {
-	conn, err := sqlx.Open("mysql", "root:root@tcp(127.0.0.1:3306)/test_db?parseTime=true")
+func NewMySQLHandler(cfg config.DB) (*MySQLHandler, error) {
+	mysqlConfig, err := mysql.ParseDSN(cfg.MySQLDSN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mysql dsn: %w", err)
+	}
+	// DATE型をtime.Timeで受け取るため常に有効にする
+	mysqlConfig.ParseTime = true
+
+	conn, err := sqlx.Open("mysql", mysqlConfig.FormatDSN())
 	if err != nil {
 		return nil, err
 	}
 
+	conn.SetMaxOpenConns(cfg.MaxOpenConns)
+	conn.SetMaxIdleConns(cfg.MaxIdleConns)
+	conn.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
 	if err = conn.Ping(); err != nil {
 		return nil, err
 	}
 
+	if err = migrateMySQL(mysqlConfig); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
 	return &MySQLHandler{
 		Conn: conn,
 	}, nil
 }
+
+// migrateMySQL はマイグレーションのファイルが複数の文からなるため、複数文を許可した専用の接続で initMySQL を実行する
+func migrateMySQL(mysqlConfig *mysql.Config) error {
+	migrationConfig := mysqlConfig.Clone()
+	migrationConfig.MultiStatements = true
+
+	conn, err := sqlx.Open("mysql", migrationConfig.FormatDSN())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// ロックは接続ごとに持つため、接続を1本に絞る
+	conn.SetMaxOpenConns(1)
+
+	return initMySQL(conn)
+}
+
+// initMySQL は mysql/<番号>_<名前>.sql を番号順に実行する。
+// 適用済みの番号は schema_migrations に記録し、既存のDBには未適用の分だけを実行する。
+// MySQLのDDLは暗黙にコミットされるため、ファイルの途中で失敗した場合は適用された分を手動で戻してから起動し直す。
+func initMySQL(conn *sqlx.DB) error {
+	migrations, err := loadMigrations(mysqlMigrationFS, "mysql")
+	if err != nil {
+		return err
+	}
+
+	var locked int
+	if err = conn.Get(&locked, "SELECT GET_LOCK(?, 60)", mysqlMigrationLock); err != nil {
+		return err
+	}
+	if locked != 1 {
+		return fmt.Errorf("failed to get the lock %q for mysql migrations", mysqlMigrationLock)
+	}
+	defer conn.Exec("SELECT RELEASE_LOCK(?)", mysqlMigrationLock)
+
+	createQuery := `
+        CREATE TABLE IF NOT EXISTS schema_migrations
+        (
+          version    INT      NOT NULL,
+          applied_at DATETIME NOT NULL,
+          PRIMARY KEY (version)
+        )`
+	if _, err = conn.Exec(createQuery); err != nil {
+		return err
+	}
+
+	var current int
+	if err = conn.Get(&current, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations"); err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+
+		query, err := mysqlMigrationFS.ReadFile(m.file)
+		if err != nil {
+			return err
+		}
+
+		if _, err = conn.Exec(string(query)); err != nil {
+			return fmt.Errorf("%s: %w", m.file, err)
+		}
+
+		if _, err = conn.Exec("INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)", m.version, time.Now().UTC()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+-- タグはユーザーごとに作成し、名前はユーザーの中で重複できない
+CREATE TABLE IF NOT EXISTS tags
+(
+  id       INT         NOT NULL AUTO_INCREMENT,
+  owner_id INT         NOT NULL,
+  name     VARCHAR(30) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX uq_owner_id_name (owner_id, name),
+
+  FOREIGN KEY fk_owner_id (owner_id)
+    REFERENCES users (id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS todo_tags
+(
+  todo_id INT NOT NULL,
+  tag_id  INT NOT NULL,
+  PRIMARY KEY (todo_id, tag_id),
+  INDEX idx_tag_id (tag_id),
+
+  FOREIGN KEY fk_todo_id (todo_id)
+    REFERENCES todos (id)
+    ON DELETE CASCADE ON UPDATE CASCADE,
+
+  FOREIGN KEY fk_tag_id (tag_id)
+    REFERENCES tags (id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+);
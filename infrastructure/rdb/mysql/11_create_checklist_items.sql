@@ -0,0 +1,15 @@
+-- チェックリストの項目はtodoの更新時にまとめて置き換えるため、並び順はpositionで持つ
+CREATE TABLE IF NOT EXISTS checklist_items
+(
+  id       INT          NOT NULL AUTO_INCREMENT,
+  todo_id  INT          NOT NULL,
+  position INT          NOT NULL,
+  title    VARCHAR(100) NOT NULL,
+  done     BOOLEAN      NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (id),
+  INDEX idx_todo_id_position (todo_id, position),
+
+  FOREIGN KEY fk_todo_id (todo_id)
+    REFERENCES todos (id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,2 @@
+-- 個人のtodoの変更履歴は所有者で絞り込む
+ALTER TABLE todo_histories ADD INDEX idx_owner_id (owner_id);
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS statuses
+(
+  id     INT         NOT NULL AUTO_INCREMENT,
+  status VARCHAR(10) NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS priorities
+(
+  id       INT     NOT NULL AUTO_INCREMENT,
+  priority CHAR(1) NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS todos
+(
+  id                  INT         NOT NULL AUTO_INCREMENT,
+  title               VARCHAR(50) NOT NULL,
+  implementation_date DATE        NOT NULL,
+  due_date            DATE        NOT NULL,
+  status_id           INT         NOT NULL,
+  priority_id         INT         NOT NULL,
+  memo                TEXT        NOT NULL,
+  PRIMARY KEY (id),
+
+  FOREIGN KEY fk_status_id (status_id)
+    REFERENCES statuses (id)
+    ON DELETE RESTRICT ON UPDATE CASCADE,
+
+  FOREIGN KEY fk_priority_id (priority_id)
+    REFERENCES priorities (id)
+    ON DELETE RESTRICT ON UPDATE CASCADE
+);
@@ -0,0 +1,14 @@
+INSERT IGNORE INTO statuses
+    (id, status)
+VALUES
+    (1, '作業前'),
+    (2, '作業中'),
+    (3, '作業完了');
+
+INSERT IGNORE INTO priorities
+    (id, priority)
+VALUES
+    (1, ''),
+    (2, '低'),
+    (3, '中'),
+    (4, '高');
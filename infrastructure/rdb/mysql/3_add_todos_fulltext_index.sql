@@ -0,0 +1,2 @@
+-- 日本語の部分一致で検索するためngramで分割する
+ALTER TABLE todos ADD FULLTEXT INDEX ft_title_memo (title, memo) WITH PARSER ngram;
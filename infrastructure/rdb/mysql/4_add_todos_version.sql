@@ -0,0 +1 @@
+ALTER TABLE todos ADD COLUMN version INT NOT NULL DEFAULT 1 AFTER memo;
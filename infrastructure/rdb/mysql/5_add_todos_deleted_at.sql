@@ -0,0 +1,3 @@
+ALTER TABLE todos
+  ADD COLUMN deleted_at DATETIME NULL DEFAULT NULL AFTER version,
+  ADD INDEX idx_deleted_at (deleted_at);
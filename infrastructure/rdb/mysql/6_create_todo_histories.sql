@@ -0,0 +1,13 @@
+-- todoを完全に削除しても履歴は残すため外部キーは張らない
+CREATE TABLE IF NOT EXISTS todo_histories
+(
+  id         INT          NOT NULL AUTO_INCREMENT,
+  todo_id    INT          NOT NULL,
+  action     VARCHAR(10)  NOT NULL,
+  changes    JSON         NOT NULL,
+  actor      VARCHAR(255) NOT NULL,
+  request_id VARCHAR(64)  NOT NULL,
+  created_at DATETIME     NOT NULL,
+  PRIMARY KEY (id),
+  INDEX idx_todo_id (todo_id)
+);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS users
+(
+  id            INT          NOT NULL AUTO_INCREMENT,
+  email         VARCHAR(255) NOT NULL,
+  password_hash VARCHAR(255) NOT NULL,
+  created_at    DATETIME     NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX uq_email (email)
+);
+
+-- 既存のtodoと変更履歴は所有者がいないため、DB_LEGACY_TODO_OWNER で指定したユーザーが引き継ぐまでどのユーザーからも参照できない
+ALTER TABLE todos
+  ADD COLUMN owner_id INT NULL DEFAULT NULL AFTER deleted_at,
+  ADD INDEX idx_owner_id (owner_id),
+  ADD FOREIGN KEY fk_owner_id (owner_id)
+    REFERENCES users (id)
+    ON DELETE RESTRICT ON UPDATE CASCADE;
+
+ALTER TABLE todo_histories ADD COLUMN owner_id INT NULL DEFAULT NULL;
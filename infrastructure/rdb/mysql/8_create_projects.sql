@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS projects
+(
+  id         INT         NOT NULL AUTO_INCREMENT,
+  name       VARCHAR(50) NOT NULL,
+  created_at DATETIME    NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS project_members
+(
+  project_id INT         NOT NULL,
+  user_id    INT         NOT NULL,
+  role       VARCHAR(10) NOT NULL,
+  PRIMARY KEY (project_id, user_id),
+  INDEX idx_user_id (user_id),
+
+  FOREIGN KEY fk_project_id (project_id)
+    REFERENCES projects (id)
+    ON DELETE CASCADE ON UPDATE CASCADE,
+
+  FOREIGN KEY fk_user_id (user_id)
+    REFERENCES users (id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- project_idがないtodoは所有者の個人のtodo
+ALTER TABLE todos
+  ADD COLUMN project_id INT NULL DEFAULT NULL AFTER owner_id,
+  ADD INDEX idx_project_id (project_id),
+  ADD FOREIGN KEY fk_project_id (project_id)
+    REFERENCES projects (id)
+    ON DELETE RESTRICT ON UPDATE CASCADE;
+
+ALTER TABLE todo_histories ADD COLUMN project_id INT NULL DEFAULT NULL;
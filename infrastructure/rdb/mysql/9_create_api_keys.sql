@@ -0,0 +1,20 @@
+-- キーはハッシュのみを保存する。失効したキーも一覧に残すため削除しない
+CREATE TABLE IF NOT EXISTS api_keys
+(
+  id           INT         NOT NULL AUTO_INCREMENT,
+  user_id      INT         NOT NULL,
+  name         VARCHAR(50) NOT NULL,
+  scope        VARCHAR(10) NOT NULL,
+  prefix       VARCHAR(12) NOT NULL,
+  key_hash     CHAR(64)    NOT NULL,
+  created_at   DATETIME    NOT NULL,
+  last_used_at DATETIME    NULL DEFAULT NULL,
+  revoked_at   DATETIME    NULL DEFAULT NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX uq_key_hash (key_hash),
+  INDEX idx_user_id (user_id),
+
+  FOREIGN KEY fk_user_id (user_id)
+    REFERENCES users (id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,88 @@
+package rdb
+
+import (
+	"embed"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+
+	"github.com/kazumakawahara/todo-sample/config"
+)
+
+//go:embed sqlite/*.sql
+var sqliteInitFS embed.FS // mysql/*.sql と同等のスキーマ・初期データ
+
+type SQLiteHandler struct {
+	Conn *sqlx.DB
+}
+
+func NewSQLiteHandler(cfg config.DB) (*SQLiteHandler, error) {
+	conn, err := sqlx.Open("sqlite", cfg.SQLitePath+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLiteは書き込みが直列化されるため接続を1本に絞る。
+	// :memory:の場合は接続を作り直すとDBが消えるため接続の寿命も設けない。
+	conn.SetMaxOpenConns(1)
+	conn.SetConnMaxLifetime(0)
+
+	if err = conn.Ping(); err != nil {
+		return nil, err
+	}
+
+	if err = initSQLite(conn); err != nil {
+		return nil, err
+	}
+
+	return &SQLiteHandler{
+		Conn: conn,
+	}, nil
+}
+
+// initSQLite は sqlite/<番号>_<名前>.sql を番号順に実行する。
+// 適用済みの番号は PRAGMA user_version に記録し、既存のDBファイルには未適用の分だけを実行する。
+func initSQLite(conn *sqlx.DB) error {
+	migrations, err := loadMigrations(sqliteInitFS, "sqlite")
+	if err != nil {
+		return err
+	}
+
+	var current int
+	if err = conn.Get(&current, "PRAGMA user_version"); err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+
+		query, err := sqliteInitFS.ReadFile(m.file)
+		if err != nil {
+			return err
+		}
+
+		tx, err := conn.Beginx()
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.Exec(string(query)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("%s: %w", m.file, err)
+		}
+
+		if _, err = tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", m.version)); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
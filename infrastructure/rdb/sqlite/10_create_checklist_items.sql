@@ -0,0 +1,11 @@
+-- チェックリストの項目はtodoの更新時にまとめて置き換えるため、並び順はpositionで持つ
+CREATE TABLE IF NOT EXISTS checklist_items
+(
+  id       INTEGER      NOT NULL PRIMARY KEY AUTOINCREMENT,
+  todo_id  INTEGER      NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+  position INTEGER      NOT NULL,
+  title    VARCHAR(100) NOT NULL,
+  done     BOOLEAN      NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_checklist_items_todo_id ON checklist_items (todo_id, position);
@@ -0,0 +1,2 @@
+-- 個人のtodoの変更履歴は所有者で絞り込む
+CREATE INDEX IF NOT EXISTS idx_todo_histories_owner_id ON todo_histories (owner_id);
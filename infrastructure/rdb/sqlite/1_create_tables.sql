@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS statuses
+(
+  id     INTEGER     NOT NULL PRIMARY KEY AUTOINCREMENT,
+  status VARCHAR(10) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS priorities
+(
+  id       INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  priority CHAR(1) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS todos
+(
+  id                  INTEGER     NOT NULL PRIMARY KEY AUTOINCREMENT,
+  title               VARCHAR(50) NOT NULL,
+  implementation_date DATE        NOT NULL,
+  due_date            DATE        NOT NULL,
+  status_id           INTEGER     NOT NULL,
+  priority_id         INTEGER     NOT NULL,
+  memo                TEXT        NOT NULL,
+
+  FOREIGN KEY (status_id)
+    REFERENCES statuses (id)
+    ON DELETE RESTRICT ON UPDATE CASCADE,
+
+  FOREIGN KEY (priority_id)
+    REFERENCES priorities (id)
+    ON DELETE RESTRICT ON UPDATE CASCADE
+);
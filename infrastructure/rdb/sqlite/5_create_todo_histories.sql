@@ -0,0 +1,13 @@
+-- todoを完全に削除しても履歴は残すため外部キーは張らない
+CREATE TABLE IF NOT EXISTS todo_histories
+(
+  id         INTEGER      NOT NULL PRIMARY KEY AUTOINCREMENT,
+  todo_id    INTEGER      NOT NULL,
+  action     VARCHAR(10)  NOT NULL,
+  changes    TEXT         NOT NULL,
+  actor      VARCHAR(255) NOT NULL,
+  request_id VARCHAR(64)  NOT NULL,
+  created_at DATETIME     NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_histories_todo_id ON todo_histories (todo_id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS users
+(
+  id            INTEGER      NOT NULL PRIMARY KEY AUTOINCREMENT,
+  email         VARCHAR(255) NOT NULL UNIQUE,
+  password_hash VARCHAR(255) NOT NULL,
+  created_at    DATETIME     NOT NULL
+);
+
+-- 既存のtodoと変更履歴は所有者がいないため、DB_LEGACY_TODO_OWNER で指定したユーザーが引き継ぐまでどのユーザーからも参照できない
+ALTER TABLE todos ADD COLUMN owner_id INTEGER NULL DEFAULT NULL REFERENCES users (id);
+
+CREATE INDEX IF NOT EXISTS idx_todos_owner_id ON todos (owner_id);
+
+ALTER TABLE todo_histories ADD COLUMN owner_id INTEGER NULL DEFAULT NULL;
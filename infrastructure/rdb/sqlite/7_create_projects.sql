@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS projects
+(
+  id         INTEGER     NOT NULL PRIMARY KEY AUTOINCREMENT,
+  name       VARCHAR(50) NOT NULL,
+  created_at DATETIME    NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS project_members
+(
+  project_id INTEGER     NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+  user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  role       VARCHAR(10) NOT NULL,
+  PRIMARY KEY (project_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_members_user_id ON project_members (user_id);
+
+-- project_idがないtodoは所有者の個人のtodo
+ALTER TABLE todos ADD COLUMN project_id INTEGER NULL DEFAULT NULL REFERENCES projects (id);
+
+CREATE INDEX IF NOT EXISTS idx_todos_project_id ON todos (project_id);
+
+ALTER TABLE todo_histories ADD COLUMN project_id INTEGER NULL DEFAULT NULL;
@@ -0,0 +1,15 @@
+-- キーはハッシュのみを保存する。失効したキーも一覧に残すため削除しない
+CREATE TABLE IF NOT EXISTS api_keys
+(
+  id           INTEGER     NOT NULL PRIMARY KEY AUTOINCREMENT,
+  user_id      INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  name         VARCHAR(50) NOT NULL,
+  scope        VARCHAR(10) NOT NULL,
+  prefix       VARCHAR(12) NOT NULL,
+  key_hash     CHAR(64)    NOT NULL UNIQUE,
+  created_at   DATETIME    NOT NULL,
+  last_used_at DATETIME    NULL DEFAULT NULL,
+  revoked_at   DATETIME    NULL DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys (user_id);
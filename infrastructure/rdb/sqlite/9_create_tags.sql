@@ -0,0 +1,17 @@
+-- タグはユーザーごとに作成し、名前はユーザーの中で重複できない
+CREATE TABLE IF NOT EXISTS tags
+(
+  id       INTEGER     NOT NULL PRIMARY KEY AUTOINCREMENT,
+  owner_id INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  name     VARCHAR(30) NOT NULL,
+  UNIQUE (owner_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS todo_tags
+(
+  todo_id INTEGER NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+  tag_id  INTEGER NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+  PRIMARY KEY (todo_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_tags_tag_id ON todo_tags (tag_id);
@@ -0,0 +1,106 @@
+package rdb
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func openSQLite(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	conn, err := sqlx.Open("sqlite", ":memory:?_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// :memory:は接続ごとに別のDBになる
+	conn.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func latestSQLiteVersion(t *testing.T) int {
+	t.Helper()
+
+	migrations, err := loadMigrations(sqliteInitFS, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return migrations[len(migrations)-1].version
+}
+
+func TestInitSQLite(t *testing.T) {
+	conn := openSQLite(t)
+
+	if err := initSQLite(conn); err != nil {
+		t.Fatalf("initSQLite() error = %v", err)
+	}
+
+	var version int
+	if err := conn.Get(&version, "PRAGMA user_version"); err != nil {
+		t.Fatal(err)
+	}
+	if want := latestSQLiteVersion(t); version != want {
+		t.Errorf("user_version = %d, want %d", version, want)
+	}
+
+	// 全てのマイグレーションを適用したスキーマで登録・参照できる
+	if _, err := conn.Exec("INSERT INTO users (email, password_hash, created_at) VALUES ('a@example.com', 'hash', '2022-04-01 00:00:00')"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec("INSERT INTO todos (title, implementation_date, due_date, status_id, priority_id, memo, owner_id) VALUES ('title', '2022-04-01', '2022-04-02', 1, 1, '', 1)"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 適用済みのDBに再度実行しても、ALTER TABLEなどを実行し直さずデータが残る
+	if err := initSQLite(conn); err != nil {
+		t.Fatalf("initSQLite() second run error = %v", err)
+	}
+
+	var count int
+	if err := conn.Get(&count, "SELECT COUNT(*) FROM todos"); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("todos count = %d, want 1", count)
+	}
+}
+
+func TestInitSQLite_Upgrade(t *testing.T) {
+	conn := openSQLite(t)
+
+	// 最初のマイグレーションのみを適用した古いDBを用意する
+	query, err := sqliteInitFS.ReadFile("sqlite/1_create_tables.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = conn.Exec(string(query)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = conn.Exec("PRAGMA user_version = 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = initSQLite(conn); err != nil {
+		t.Fatalf("initSQLite() error = %v", err)
+	}
+
+	var version int
+	if err = conn.Get(&version, "PRAGMA user_version"); err != nil {
+		t.Fatal(err)
+	}
+	if want := latestSQLiteVersion(t); version != want {
+		t.Errorf("user_version = %d, want %d", version, want)
+	}
+
+	// 2番以降が適用されマスタデータが入っている
+	var statuses int
+	if err = conn.Get(&statuses, "SELECT COUNT(*) FROM statuses"); err != nil {
+		t.Fatal(err)
+	}
+	if statuses != 3 {
+		t.Errorf("statuses count = %d, want 3", statuses)
+	}
+}
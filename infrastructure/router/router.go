@@ -2,16 +2,23 @@ package router
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/kazumakawahara/todo-sample/config"
+	"github.com/kazumakawahara/todo-sample/domain/apikeydomain"
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/auth"
 	"github.com/kazumakawahara/todo-sample/infrastructure/middleware"
 	"github.com/kazumakawahara/todo-sample/infrastructure/persistence"
 	"github.com/kazumakawahara/todo-sample/infrastructure/rdb"
@@ -19,28 +26,160 @@ import (
 	"github.com/kazumakawahara/todo-sample/usecase"
 )
 
-func Run() error {
-	mySQLHandler, err := rdb.NewMySQLHandler()
+func Run(cfg *config.Config) error {
+	var (
+		todoRepository            tododomain.Repository
+		transactionManager        tododomain.TransactionManager
+		userRepository            userdomain.Repository
+		projectRepository         projectdomain.Repository
+		projectTransactionManager projectdomain.TransactionManager
+		apiKeyRepository          apikeydomain.Repository
+		tagRepository             tododomain.TagRepository
+		ownerlessTodoClaimer      ownerlessTodoClaimer
+	)
+	switch cfg.DB.Driver {
+	case config.DriverMySQL:
+		mySQLHandler, err := rdb.NewMySQLHandler(cfg.DB)
+		if err != nil {
+			return err
+		}
+		defer mySQLHandler.Conn.Close()
+
+		todoRepository = persistence.NewTodoRepository(mySQLHandler)
+		transactionManager = persistence.NewTransactionManager(mySQLHandler)
+		userMySQLRepository := persistence.NewUserRepository(mySQLHandler)
+		userRepository = userMySQLRepository
+		ownerlessTodoClaimer = userMySQLRepository
+		projectRepository = persistence.NewProjectRepository(mySQLHandler)
+		projectTransactionManager = persistence.NewProjectTransactionManager(mySQLHandler)
+		apiKeyRepository = persistence.NewAPIKeyRepository(mySQLHandler)
+		tagRepository = persistence.NewTagRepository(mySQLHandler)
+	case config.DriverSQLite:
+		sqliteHandler, err := rdb.NewSQLiteHandler(cfg.DB)
+		if err != nil {
+			return err
+		}
+		defer sqliteHandler.Conn.Close()
+
+		todoRepository = persistence.NewTodoSQLiteRepository(sqliteHandler)
+		transactionManager = persistence.NewSQLiteTransactionManager(sqliteHandler)
+		userSQLiteRepository := persistence.NewUserSQLiteRepository(sqliteHandler)
+		userRepository = userSQLiteRepository
+		ownerlessTodoClaimer = userSQLiteRepository
+		projectRepository = persistence.NewProjectSQLiteRepository(sqliteHandler)
+		projectTransactionManager = persistence.NewProjectSQLiteTransactionManager(sqliteHandler)
+		apiKeyRepository = persistence.NewAPIKeySQLiteRepository(sqliteHandler)
+		tagRepository = persistence.NewTagSQLiteRepository(sqliteHandler)
+	case config.DriverMemory:
+		todoMemoryRepository := persistence.NewTodoMemoryRepository()
+		todoRepository = todoMemoryRepository
+		transactionManager = persistence.NewMemoryTransactionManager(todoMemoryRepository)
+		userRepository = persistence.NewUserMemoryRepository()
+		projectRepository = persistence.NewProjectMemoryRepository(todoMemoryRepository)
+		projectTransactionManager = persistence.NewProjectMemoryTransactionManager(todoMemoryRepository)
+		apiKeyRepository = persistence.NewAPIKeyMemoryRepository()
+		tagRepository = persistence.NewTagMemoryRepository(todoMemoryRepository)
+	default:
+		return fmt.Errorf("unknown driver: %q", cfg.DB.Driver)
+	}
+
+	if cfg.DB.LegacyTodoOwner != "" {
+		if err := claimLegacyTodos(context.Background(), cfg.DB.LegacyTodoOwner, userRepository, ownerlessTodoClaimer); err != nil {
+			return err
+		}
+	}
+
+	tokenSecret, err := authTokenSecret(cfg.Auth)
 	if err != nil {
 		return err
 	}
-	defer mySQLHandler.Conn.Close()
+	tokenManager := auth.NewHMACTokenManager(tokenSecret, cfg.Auth.TokenTTL)
 
-	todoRepository := persistence.NewTodoRepository(mySQLHandler)
-	todoUsecase := usecase.NewTodoUsecase(todoRepository)
+	userUsecase := usecase.NewUserUsecase(userRepository, auth.NewBcryptPasswordHasher(), tokenManager)
+	userHandler := handler.NewUserHandler(userUsecase)
+
+	todoUsecase := usecase.NewTodoUsecase(todoRepository, transactionManager, projectRepository, tagRepository, cfg.Trash.Retention)
 	todoHandler := handler.NewTodoHandler(todoUsecase)
 
+	tagUsecase := usecase.NewTagUsecase(tagRepository)
+	tagHandler := handler.NewTagHandler(tagUsecase)
+
+	projectUsecase := usecase.NewProjectUsecase(projectRepository, projectTransactionManager, userRepository)
+	projectHandler := handler.NewProjectHandler(projectUsecase)
+
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(apiKeyRepository, auth.NewSHA256KeyManager())
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUsecase)
+
 	router := mux.NewRouter()
-	router.HandleFunc("/todos", todoHandler.CreateTodo).Methods(http.MethodPost)
-	router.HandleFunc("/todos/{id:[0-9]+}", todoHandler.FetchTodo).Methods(http.MethodGet)
-	router.HandleFunc("/todos", todoHandler.FetchTodos).Methods(http.MethodGet)
-	router.HandleFunc("/todos/{id:[0-9]+}", todoHandler.UpdateTodo).Methods(http.MethodPut)
-	router.HandleFunc("/todos/{id:[0-9]+}", todoHandler.DeleteTodo).Methods(http.MethodDelete)
+	router.HandleFunc("/users", userHandler.RegisterUser).Methods(http.MethodPost)
+	router.HandleFunc("/login", userHandler.Login).Methods(http.MethodPost)
+
+	// todoの操作は認証したユーザーのみ行える。CIやスクリプトからはAPIキーでも操作できる
+	todoRouter := router.NewRoute().Subrouter()
+	todoRouter.Use(middleware.NewAPIKeyAuthMiddlewareFunc(tokenManager, apiKeyUsecase))
+	todoRouter.HandleFunc("/todos", todoHandler.CreateTodo).Methods(http.MethodPost)
+	todoRouter.HandleFunc("/todos/{id:[0-9]+}", todoHandler.FetchTodo).Methods(http.MethodGet)
+	todoRouter.HandleFunc("/todos", todoHandler.FetchTodos).Methods(http.MethodGet)
+	todoRouter.HandleFunc("/todos/search", todoHandler.SearchTodos).Methods(http.MethodGet)
+	todoRouter.HandleFunc("/todos/trash", todoHandler.FetchTrashedTodos).Methods(http.MethodGet)
+	todoRouter.HandleFunc("/todos/trash", todoHandler.PurgeTodos).Methods(http.MethodDelete)
+	todoRouter.HandleFunc("/todos/{id:[0-9]+}", todoHandler.UpdateTodo).Methods(http.MethodPut)
+	todoRouter.HandleFunc("/todos/{id:[0-9]+}", todoHandler.PatchTodo).Methods(http.MethodPatch)
+	deleteTodo := todoHandler.DeleteTodo
+	if cfg.Server.IdempotentDelete(config.RouteDeleteTodo) {
+		deleteTodo = todoHandler.DeleteTodoIdempotent
+	}
+	todoRouter.HandleFunc("/todos/{id:[0-9]+}", deleteTodo).Methods(http.MethodDelete)
+	todoRouter.HandleFunc("/todos:batch", todoHandler.BatchCreateTodos).Methods(http.MethodPost)
+	todoRouter.HandleFunc("/todos:batch", todoHandler.BatchPatchTodos).Methods(http.MethodPatch)
+	batchDeleteTodos := todoHandler.BatchDeleteTodos
+	if cfg.Server.IdempotentDelete(config.RouteBatchDeleteTodos) {
+		batchDeleteTodos = todoHandler.BatchDeleteTodosIdempotent
+	}
+	todoRouter.HandleFunc("/todos:batch", batchDeleteTodos).Methods(http.MethodDelete)
+	todoRouter.HandleFunc("/todos/{id:[0-9]+}/start", todoHandler.StartTodo).Methods(http.MethodPost)
+	todoRouter.HandleFunc("/todos/{id:[0-9]+}/complete", todoHandler.CompleteTodo).Methods(http.MethodPost)
+	todoRouter.HandleFunc("/todos/{id:[0-9]+}/reopen", todoHandler.ReopenTodo).Methods(http.MethodPost)
+	todoRouter.HandleFunc("/todos/{id:[0-9]+}/restore", todoHandler.RestoreTodo).Methods(http.MethodPost)
+	todoRouter.HandleFunc("/todos/{id:[0-9]+}/history", todoHandler.FetchTodoHistories).Methods(http.MethodGet)
+	todoRouter.HandleFunc("/todos/{id:[0-9]+}/checklist", todoHandler.AddChecklistItem).Methods(http.MethodPost)
+	todoRouter.HandleFunc("/todos/{id:[0-9]+}/checklist/order", todoHandler.ReorderChecklist).Methods(http.MethodPut)
+	todoRouter.HandleFunc("/todos/{id:[0-9]+}/checklist/{itemID:[0-9]+}", todoHandler.CheckChecklistItem).Methods(http.MethodPatch)
+	todoRouter.HandleFunc("/todos/{id:[0-9]+}/checklist/{itemID:[0-9]+}", todoHandler.DeleteChecklistItem).Methods(http.MethodDelete)
+	todoRouter.HandleFunc("/tags", tagHandler.CreateTag).Methods(http.MethodPost)
+	todoRouter.HandleFunc("/tags", tagHandler.FetchTags).Methods(http.MethodGet)
+	todoRouter.HandleFunc("/tags/{id:[0-9]+}", tagHandler.FetchTag).Methods(http.MethodGet)
+	todoRouter.HandleFunc("/tags/{id:[0-9]+}", tagHandler.UpdateTag).Methods(http.MethodPut)
+	todoRouter.HandleFunc("/tags/{id:[0-9]+}", tagHandler.DeleteTag).Methods(http.MethodDelete)
+
+	// プロジェクト・APIキーの管理はログインしたユーザーのみ行える
+	authRouter := router.NewRoute().Subrouter()
+	authRouter.Use(middleware.NewAuthMiddlewareFunc(tokenManager))
+	authRouter.HandleFunc("/projects", projectHandler.CreateProject).Methods(http.MethodPost)
+	authRouter.HandleFunc("/projects", projectHandler.FetchProjects).Methods(http.MethodGet)
+	authRouter.HandleFunc("/projects/{id:[0-9]+}", projectHandler.FetchProject).Methods(http.MethodGet)
+	authRouter.HandleFunc("/projects/{id:[0-9]+}/members", projectHandler.FetchMembers).Methods(http.MethodGet)
+	authRouter.HandleFunc("/projects/{id:[0-9]+}/members/{userID:[0-9]+}", projectHandler.PutMember).Methods(http.MethodPut)
+	authRouter.HandleFunc("/projects/{id:[0-9]+}/members/{userID:[0-9]+}", projectHandler.DeleteMember).Methods(http.MethodDelete)
+	authRouter.HandleFunc("/api-keys", apiKeyHandler.CreateAPIKey).Methods(http.MethodPost)
+	authRouter.HandleFunc("/api-keys", apiKeyHandler.FetchAPIKeys).Methods(http.MethodGet)
+	authRouter.HandleFunc("/api-keys/{id:[0-9]+}", apiKeyHandler.RevokeAPIKey).Methods(http.MethodDelete)
+
+	router.Use(middleware.NewTimeoutMiddlewareFunc(cfg.DB.QueryTimeout))
+
+	// シャットダウンの待ち時間を過ぎたらリクエストのコンテキストをキャンセルし、実行中のクエリを中断する
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
 
-	// Apply cors middleware to top-level router.
 	srv := &http.Server{
-		Addr:    fmt.Sprintf(":%d", 8080),
-		Handler: middleware.NewCorsMiddlewareFunc()(router),
+		Addr:         cfg.Server.Addr(),
+		Handler:      newServerHandler(router, cfg.CORS.AllowedOrigins),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
 	}
 
 	errorCh := make(chan error, 1)
@@ -55,16 +194,69 @@ func Run() error {
 
 	select {
 	case err := <-errorCh:
-		panic(err)
+		return err
 	case s := <-signalCh:
 		log.Printf("SIGNAL %s received", s.String())
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 		defer cancel()
 
 		if err := srv.Shutdown(ctx); err != nil {
-			panic(err)
+			cancelBase()
+			return err
 		}
 	}
 
 	return nil
 }
+
+// newServerHandler はルーターにミドルウェアを適用する。
+// ルートに一致しない404・405やCORSのプリフライトにもリクエストIDを返すため、ルーターのUseではなく外側で包む
+func newServerHandler(router http.Handler, allowedOrigins []string) http.Handler {
+	handler := middleware.NewCorsMiddlewareFunc(allowedOrigins)(router)
+	handler = middleware.NewRecoveryMiddlewareFunc()(handler)
+
+	return middleware.NewRequestIDMiddlewareFunc()(handler)
+}
+
+// ownerlessTodoClaimer はユーザー管理の導入前に作成された所有者のないtodoをユーザーに引き継ぐ
+type ownerlessTodoClaimer interface {
+	ClaimOwnerlessTodos(ctx context.Context, ownerID userdomain.ID) (int64, error)
+}
+
+// claimLegacyTodos は所有者のないtodoと変更履歴を、DB_LEGACY_TODO_OWNER で指定した登録済みのユーザーに引き継ぐ
+func claimLegacyTodos(ctx context.Context, email string, users userdomain.Repository, claimer ownerlessTodoClaimer) error {
+	emailVo, err := userdomain.NewEmail(email)
+	if err != nil {
+		return fmt.Errorf("DB_LEGACY_TODO_OWNER: %w", err)
+	}
+
+	user, err := users.FetchUserByEmail(ctx, emailVo)
+	if err != nil {
+		return fmt.Errorf("DB_LEGACY_TODO_OWNER: user %s must be registered before taking over todos: %w", emailVo.Value(), err)
+	}
+
+	claimed, err := claimer.ClaimOwnerlessTodos(ctx, user.ID())
+	if err != nil {
+		return err
+	}
+	if claimed > 0 {
+		log.Printf("%d todos created before user accounts were assigned to %s", claimed, emailVo.Value())
+	}
+
+	return nil
+}
+
+// authTokenSecret は設定されたトークンの署名鍵を返す。未設定なら起動ごとに生成する
+func authTokenSecret(cfg config.Auth) ([]byte, error) {
+	if cfg.TokenSecret != "" {
+		return []byte(cfg.TokenSecret), nil
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	log.Print("WARNING: AUTH_TOKEN_SECRET is not set; tokens will be invalidated on restart")
+
+	return secret, nil
+}
@@ -0,0 +1,119 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/persistence"
+)
+
+func TestNewServerHandler_RequestID(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/todos", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodGet)
+	router.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("panic")
+	}).Methods(http.MethodGet)
+	handler := newServerHandler(router, []string{"http://example.com"})
+
+	// panicのスタックトレースを出力しない
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		header     http.Header
+		wantStatus int
+	}{
+		{name: "正常系", method: http.MethodGet, target: "/todos", wantStatus: http.StatusOK},
+		{name: "正常系: 一致するルートがない", method: http.MethodGet, target: "/unknown", wantStatus: http.StatusNotFound},
+		{name: "正常系: 許可しないメソッド", method: http.MethodPost, target: "/todos", wantStatus: http.StatusMethodNotAllowed},
+		{name: "正常系: CORSのプリフライト", method: http.MethodOptions, target: "/todos", header: http.Header{
+			"Origin":                        {"http://example.com"},
+			"Access-Control-Request-Method": {http.MethodGet},
+		}, wantStatus: http.StatusNoContent},
+		{name: "異常系: panic", method: http.MethodGet, target: "/panic", wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			for key, values := range tt.header {
+				req.Header[key] = values
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if rec.Header().Get("X-Request-ID") == "" {
+				t.Error("X-Request-ID is empty")
+			}
+		})
+	}
+
+	// 指定されたリクエストIDはそのまま返す
+	req := httptest.NewRequest(http.MethodGet, "/unknown", nil)
+	req.Header.Set("X-Request-ID", "request")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("X-Request-ID"); got != "request" {
+		t.Errorf("X-Request-ID = %q, want %q", got, "request")
+	}
+}
+
+type stubClaimer struct {
+	ownerID userdomain.ID
+}
+
+func (c *stubClaimer) ClaimOwnerlessTodos(ctx context.Context, ownerID userdomain.ID) (int64, error) {
+	c.ownerID = ownerID
+	return 0, nil
+}
+
+func TestClaimLegacyTodos(t *testing.T) {
+	users := persistence.NewUserMemoryRepository()
+	userID, err := users.CreateUser(context.Background(), userdomain.NewUserWhenUnCreated("a@example.com", "hash", time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		email   string
+		want    userdomain.ID
+		wantErr error
+	}{
+		{name: "正常系", email: "A@example.com", want: userID},
+		{name: "異常系: 未登録のユーザー", email: "b@example.com", wantErr: apperrors.UserNotFound},
+		{name: "異常系: メールアドレスの形式が不正", email: "a.example.com", wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claimer := &stubClaimer{}
+
+			err := claimLegacyTodos(context.Background(), tt.email, users, claimer)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("claimLegacyTodos() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if claimer.ownerID != tt.want {
+				t.Errorf("claimed owner = %d, want %d", claimer.ownerID, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/interfaces/presenter"
+	"github.com/kazumakawahara/todo-sample/usecase"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+	"github.com/kazumakawahara/todo-sample/usecase/output"
+)
+
+type apiKeyHandler struct {
+	apiKeyUsecase usecase.APIKeyUsecase
+}
+
+func NewAPIKeyHandler(apiKeyUsecase usecase.APIKeyUsecase) *apiKeyHandler {
+	return &apiKeyHandler{
+		apiKeyUsecase: apiKeyUsecase,
+	}
+}
+
+func (h *apiKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var in input.APIKey
+	if err := decodeJSON(r, &in); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	out, err := h.apiKeyUsecase.CreateAPIKey(r.Context(), &in)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusCreated, out)
+}
+
+func (h *apiKeyHandler) FetchAPIKeys(w http.ResponseWriter, r *http.Request) {
+	out, err := h.apiKeyUsecase.FetchAPIKeys(r.Context())
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusOK, out)
+}
+
+func (h *apiKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	apiKeyID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	if err = h.apiKeyUsecase.RevokeAPIKey(r.Context(), apiKeyID); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	resp := output.DeleteMessage{Message: presenter.Message(r, presenter.APIKeyRevokedMessage)}
+
+	presenter.JSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/kazumakawahara/todo-sample/domain/apikeydomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+
+	anonymousActor = "anonymous"
+)
+
+// newAudit は変更履歴に記録する操作者(認証したユーザー)とリクエストIDをリクエストから取得する。
+// APIキーで認証した場合は user:<ID>/apikey:<ID> とし、どのキーでの操作かを残す。
+func newAudit(r *http.Request) *input.Audit {
+	actor := anonymousActor
+	if userID, ok := userdomain.UserIDFromContext(r.Context()); ok {
+		actor = "user:" + strconv.Itoa(userID.Value())
+	}
+	if apiKeyID, ok := apikeydomain.APIKeyIDFromContext(r.Context()); ok {
+		actor += "/apikey:" + strconv.Itoa(apiKeyID.Value())
+	}
+
+	return &input.Audit{
+		Actor:     actor,
+		RequestID: r.Header.Get(requestIDHeader),
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"sort"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+// decodeJSON はリクエストボディをvにデコードする。
+// 型が一致しない項目があればその項目の違反として返す。
+func decodeJSON(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			var fieldErrs apperrors.FieldErrors
+			fieldErrs.Add(typeErr.Field, apperrors.NewValidationError("type", typeErr.Value, "値の型が不正です"))
+			return fieldErrs.Err()
+		}
+
+		return apperrors.InvalidParameter
+	}
+
+	return nil
+}
+
+const mergePatchContentType = "application/merge-patch+json"
+
+// decodeMergePatch はJSON Merge Patch (RFC 7396) のボディをデコードする。
+// targetsのキーに一致するメンバーのみを対応するポインタに格納し、nullが指定されたキーを返す。
+func decodeMergePatch(r *http.Request, targets map[string]interface{}) ([]string, error) {
+	if !isMergePatchContentType(r) {
+		return nil, apperrors.UnsupportedMediaType
+	}
+
+	// パッチがオブジェクト以外の場合はリソース全体の置き換えになるため受け付けない
+	var members map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&members); err != nil || members == nil {
+		return nil, apperrors.InvalidParameter
+	}
+
+	var fieldErrs apperrors.FieldErrors
+	nullFields := applyMergePatch(members, targets, "", &fieldErrs)
+	if err := fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	return nullFields, nil
+}
+
+// isMergePatchContentType はContent-TypeがJSON Merge PatchかJSONかを返す
+func isMergePatchContentType(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && (mediaType == mergePatchContentType || mediaType == "application/json")
+}
+
+// applyMergePatch はmembersのうちtargetsのキーに一致するメンバーを対応するポインタに格納し、nullが指定されたキーを返す。
+// 型が一致しない項目はprefixを付けた項目名でfieldErrsに追加する。
+func applyMergePatch(members map[string]json.RawMessage, targets map[string]interface{}, prefix string, fieldErrs *apperrors.FieldErrors) []string {
+	var nullFields []string
+	for key, raw := range members {
+		target, ok := targets[key]
+		if !ok {
+			continue
+		}
+
+		if string(raw) == "null" {
+			nullFields = append(nullFields, key)
+			continue
+		}
+
+		if err := json.Unmarshal(raw, target); err != nil {
+			fieldErrs.Add(prefix+key, apperrors.NewValidationError("type", string(raw), "値の型が不正です"))
+		}
+	}
+
+	sort.Strings(nullFields)
+
+	return nullFields
+}
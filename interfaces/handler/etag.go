@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+// todoのバージョンをそのままエンティティタグとして使う
+
+func setETag(w http.ResponseWriter, version uint) {
+	w.Header().Set("ETag", `"`+strconv.FormatUint(uint64(version), 10)+`"`)
+}
+
+// ifMatchVersions はIf-Matchヘッダーで指定されたバージョンを返す。
+// ヘッダーがない場合と "*" の場合はnilを返す。
+// If-Matchは強い比較のため弱いエンティティタグは一致しないものとして扱う。
+func ifMatchVersions(r *http.Request) ([]uint, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	var versions []uint
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) || len(tag) < 2 {
+			continue
+		}
+
+		version, err := strconv.ParseUint(strings.Trim(tag, `"`), 10, 0)
+		if err != nil {
+			continue
+		}
+
+		versions = append(versions, uint(version))
+	}
+
+	// どのバージョンとも一致し得ない
+	if len(versions) == 0 {
+		return nil, apperrors.PreconditionFailed
+	}
+
+	return versions, nil
+}
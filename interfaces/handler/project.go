@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/interfaces/presenter"
+	"github.com/kazumakawahara/todo-sample/usecase"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+	"github.com/kazumakawahara/todo-sample/usecase/output"
+)
+
+type projectHandler struct {
+	projectUsecase usecase.ProjectUsecase
+}
+
+func NewProjectHandler(projectUsecase usecase.ProjectUsecase) *projectHandler {
+	return &projectHandler{
+		projectUsecase: projectUsecase,
+	}
+}
+
+func (h *projectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
+	var in input.Project
+	if err := decodeJSON(r, &in); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	out, err := h.projectUsecase.CreateProject(r.Context(), &in)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusCreated, out)
+}
+
+func (h *projectHandler) FetchProject(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	out, err := h.projectUsecase.FetchProject(r.Context(), projectID)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusOK, out)
+}
+
+func (h *projectHandler) FetchProjects(w http.ResponseWriter, r *http.Request) {
+	out, err := h.projectUsecase.FetchProjects(r.Context())
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusOK, out)
+}
+
+func (h *projectHandler) FetchMembers(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	out, err := h.projectUsecase.FetchMembers(r.Context(), projectID)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusOK, out)
+}
+
+func (h *projectHandler) PutMember(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	userID, err := strconv.Atoi(mux.Vars(r)["userID"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	var in input.ProjectMember
+	if err = decodeJSON(r, &in); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+	in.ProjectID = projectID
+	in.UserID = userID
+
+	out, err := h.projectUsecase.PutMember(r.Context(), &in)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusOK, out)
+}
+
+func (h *projectHandler) DeleteMember(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	userID, err := strconv.Atoi(mux.Vars(r)["userID"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	if err = h.projectUsecase.DeleteMember(r.Context(), projectID, userID); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	resp := output.DeleteMessage{Message: presenter.Message(r, presenter.MemberDeletedMessage)}
+
+	presenter.JSON(w, http.StatusOK, resp)
+}
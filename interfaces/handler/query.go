@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+)
+
+// クエリパラメータの変換。変換できない値はfieldErrsに追加する。
+
+// "1,2" や "status=1&status=2" の形式を受け付ける
+func queryUints(query url.Values, key string, fieldErrs *apperrors.FieldErrors) []uint {
+	var values []uint
+	for _, v := range query[key] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+
+			u, err := strconv.ParseUint(s, 10, 0)
+			if err != nil {
+				fieldErrs.Add(key, apperrors.NewValidationError("type", s, "数値で指定してください"))
+				continue
+			}
+
+			values = append(values, uint(u))
+		}
+	}
+
+	return values
+}
+
+func queryInt(query url.Values, key string, fieldErrs *apperrors.FieldErrors) int {
+	v := query.Get(key)
+	if v == "" {
+		return 0
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		fieldErrs.Add(key, apperrors.NewValidationError("type", v, "数値で指定してください"))
+		return 0
+	}
+
+	return i
+}
+
+// "2006-01-02" またはRFC3339形式を受け付ける
+func queryDate(query url.Values, key string, fieldErrs *apperrors.FieldErrors) time.Time {
+	v := query.Get(key)
+	if v == "" {
+		return time.Time{}
+	}
+
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t
+		}
+	}
+
+	fieldErrs.Add(key, apperrors.NewValidationError("type", v, "日付は YYYY-MM-DD 形式で指定してください"))
+	return time.Time{}
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/interfaces/presenter"
+	"github.com/kazumakawahara/todo-sample/usecase"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+	"github.com/kazumakawahara/todo-sample/usecase/output"
+)
+
+type tagHandler struct {
+	tagUsecase usecase.TagUsecase
+}
+
+func NewTagHandler(tagUsecase usecase.TagUsecase) *tagHandler {
+	return &tagHandler{
+		tagUsecase: tagUsecase,
+	}
+}
+
+func (h *tagHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	var in input.Tag
+	if err := decodeJSON(r, &in); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	out, err := h.tagUsecase.CreateTag(r.Context(), &in)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusCreated, out)
+}
+
+func (h *tagHandler) FetchTag(w http.ResponseWriter, r *http.Request) {
+	tagID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	out, err := h.tagUsecase.FetchTag(r.Context(), tagID)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusOK, out)
+}
+
+func (h *tagHandler) FetchTags(w http.ResponseWriter, r *http.Request) {
+	out, err := h.tagUsecase.FetchTags(r.Context())
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusOK, out)
+}
+
+func (h *tagHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	tagID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	in := input.Tag{ID: tagID}
+	if err := decodeJSON(r, &in); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	out, err := h.tagUsecase.UpdateTag(r.Context(), &in)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusOK, out)
+}
+
+func (h *tagHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	tagID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	if err = h.tagUsecase.DeleteTag(r.Context(), tagID); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	resp := output.DeleteMessage{Message: presenter.Message(r, presenter.TagDeletedMessage)}
+
+	presenter.JSON(w, http.StatusOK, resp)
+}
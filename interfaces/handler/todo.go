@@ -1,7 +1,7 @@
 package handler
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
 	"strconv"
 
@@ -14,6 +14,8 @@ import (
 	"github.com/kazumakawahara/todo-sample/usecase/output"
 )
 
+const nextCursorHeader = "X-Next-Cursor"
+
 type todoHandler struct {
 	todoUsecase usecase.TodoUsecase
 }
@@ -26,40 +28,100 @@ func NewTodoHandler(todoUsecase usecase.TodoUsecase) *todoHandler {
 
 func (h *todoHandler) CreateTodo(w http.ResponseWriter, r *http.Request) {
 	var in input.Todo
-	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
-		presenter.ErrorJSON(w, apperrors.InvalidParameter)
+	if err := decodeJSON(r, &in); err != nil {
+		presenter.ErrorJSON(w, r, err)
 		return
 	}
 
-	out, err := h.todoUsecase.CreateTodo(&in)
+	out, err := h.todoUsecase.CreateTodo(r.Context(), &in, newAudit(r))
 	if err != nil {
-		presenter.ErrorJSON(w, err)
+		presenter.ErrorJSON(w, r, err)
 		return
 	}
 
+	setETag(w, out.Version)
 	presenter.JSON(w, http.StatusCreated, out)
 }
 
 func (h *todoHandler) FetchTodo(w http.ResponseWriter, r *http.Request) {
 	todoID, err := strconv.Atoi(mux.Vars(r)["id"])
 	if err != nil {
-		presenter.ErrorJSON(w, apperrors.InvalidParameter)
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
 		return
 	}
 
-	out, err := h.todoUsecase.FetchTodo(todoID)
+	out, err := h.todoUsecase.FetchTodo(r.Context(), todoID)
 	if err != nil {
-		presenter.ErrorJSON(w, err)
+		presenter.ErrorJSON(w, r, err)
 		return
 	}
 
+	setETag(w, out.Version)
 	presenter.JSON(w, http.StatusOK, out)
 }
 
 func (h *todoHandler) FetchTodos(w http.ResponseWriter, r *http.Request) {
-	out, err := h.todoUsecase.FetchTodos()
+	h.fetchTodos(w, r, h.todoUsecase.FetchTodos)
+}
+
+func (h *todoHandler) FetchTrashedTodos(w http.ResponseWriter, r *http.Request) {
+	h.fetchTodos(w, r, h.todoUsecase.FetchTrashedTodos)
+}
+
+func (h *todoHandler) fetchTodos(w http.ResponseWriter, r *http.Request, fetch func(ctx context.Context, in *input.TodoCriteria) (*output.TodoList, error)) {
+	query := r.URL.Query()
+
+	var fieldErrs apperrors.FieldErrors
+	in := input.TodoCriteria{
+		StatusIDs:   queryUints(query, "status", &fieldErrs),
+		PriorityIDs: queryUints(query, "priority", &fieldErrs),
+		DueDateFrom: queryDate(query, "dueDateFrom", &fieldErrs),
+		DueDateTo:   queryDate(query, "dueDateTo", &fieldErrs),
+		Title:       query.Get("title"),
+		Memo:        query.Get("memo"),
+		ProjectID:   queryInt(query, "project", &fieldErrs),
+		TagIDs:      queryUints(query, "tag", &fieldErrs),
+		TagMatch:    query.Get("tagMatch"),
+		Sort:        query.Get("sort"),
+		Order:       query.Get("order"),
+		Cursor:      query.Get("cursor"),
+		Limit:       queryInt(query, "limit", &fieldErrs),
+	}
+	if err := fieldErrs.Err(); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	out, err := fetch(r.Context(), &in)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	// 次のページがある場合はヘッダーでカーソルを返す
+	if out.NextCursor != "" {
+		w.Header().Set(nextCursorHeader, out.NextCursor)
+	}
+
+	presenter.JSON(w, http.StatusOK, out.Todos)
+}
+
+func (h *todoHandler) SearchTodos(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var fieldErrs apperrors.FieldErrors
+	in := input.TodoSearch{
+		Query: query.Get("q"),
+		Limit: queryInt(query, "limit", &fieldErrs),
+	}
+	if err := fieldErrs.Err(); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	out, err := h.todoUsecase.SearchTodos(r.Context(), &in)
 	if err != nil {
-		presenter.ErrorJSON(w, err)
+		presenter.ErrorJSON(w, r, err)
 		return
 	}
 
@@ -69,40 +131,169 @@ func (h *todoHandler) FetchTodos(w http.ResponseWriter, r *http.Request) {
 func (h *todoHandler) UpdateTodo(w http.ResponseWriter, r *http.Request) {
 	todoID, err := strconv.Atoi(mux.Vars(r)["id"])
 	if err != nil {
-		presenter.ErrorJSON(w, apperrors.InvalidParameter)
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	expectedVersions, err := ifMatchVersions(r)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
 		return
 	}
 
 	in := input.Todo{
-		ID: todoID,
+		ID:               todoID,
+		ExpectedVersions: expectedVersions,
+	}
+	if err := decodeJSON(r, &in); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	out, err := h.todoUsecase.UpdateTodo(r.Context(), &in, newAudit(r))
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	setETag(w, out.Version)
+	presenter.JSON(w, http.StatusOK, out)
+}
+
+func (h *todoHandler) PatchTodo(w http.ResponseWriter, r *http.Request) {
+	todoID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	expectedVersions, err := ifMatchVersions(r)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
-		presenter.ErrorJSON(w, apperrors.InvalidParameter)
+
+	in := input.TodoPatch{
+		ID:               todoID,
+		ExpectedVersions: expectedVersions,
+	}
+	in.NullFields, err = decodeMergePatch(r, map[string]interface{}{
+		"title":              &in.Title,
+		"implementationDate": &in.ImplementationDate,
+		"dueDate":            &in.DueDate,
+		"statusID":           &in.StatusID,
+		"priorityID":         &in.PriorityID,
+		"memo":               &in.Memo,
+		"tagIDs":             &in.TagIDs,
+	})
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
 		return
 	}
 
-	out, err := h.todoUsecase.UpdateTodo(&in)
+	out, err := h.todoUsecase.PatchTodo(r.Context(), &in, newAudit(r))
 	if err != nil {
-		presenter.ErrorJSON(w, err)
+		presenter.ErrorJSON(w, r, err)
 		return
 	}
 
+	setETag(w, out.Version)
 	presenter.JSON(w, http.StatusOK, out)
 }
 
 func (h *todoHandler) DeleteTodo(w http.ResponseWriter, r *http.Request) {
+	h.deleteTodo(w, r, false)
+}
+
+// DeleteTodoIdempotent は存在しないtodoの削除も成功として扱う
+func (h *todoHandler) DeleteTodoIdempotent(w http.ResponseWriter, r *http.Request) {
+	h.deleteTodo(w, r, true)
+}
+
+func (h *todoHandler) deleteTodo(w http.ResponseWriter, r *http.Request, idempotent bool) {
 	todoID, err := strconv.Atoi(mux.Vars(r)["id"])
 	if err != nil {
-		presenter.ErrorJSON(w, apperrors.InvalidParameter)
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	expectedVersions, err := ifMatchVersions(r)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
 		return
 	}
 
-	if err := h.todoUsecase.DeleteTodo(todoID); err != nil {
-		presenter.ErrorJSON(w, err)
+	in := input.TodoDelete{
+		ID:               todoID,
+		ExpectedVersions: expectedVersions,
+		Idempotent:       idempotent,
+	}
+	if err := h.todoUsecase.DeleteTodo(r.Context(), &in, newAudit(r)); err != nil {
+		presenter.ErrorJSON(w, r, err)
 		return
 	}
 
-	resp := output.DeleteMessage{Message: "削除しました。"}
+	resp := output.DeleteMessage{Message: presenter.Message(r, presenter.TodoDeletedMessage)}
 
 	presenter.JSON(w, http.StatusOK, resp)
 }
+
+func (h *todoHandler) StartTodo(w http.ResponseWriter, r *http.Request) {
+	h.transitTodo(w, r, h.todoUsecase.StartTodo)
+}
+
+func (h *todoHandler) CompleteTodo(w http.ResponseWriter, r *http.Request) {
+	h.transitTodo(w, r, h.todoUsecase.CompleteTodo)
+}
+
+func (h *todoHandler) ReopenTodo(w http.ResponseWriter, r *http.Request) {
+	h.transitTodo(w, r, h.todoUsecase.ReopenTodo)
+}
+
+func (h *todoHandler) RestoreTodo(w http.ResponseWriter, r *http.Request) {
+	h.transitTodo(w, r, h.todoUsecase.RestoreTodo)
+}
+
+func (h *todoHandler) FetchTodoHistories(w http.ResponseWriter, r *http.Request) {
+	todoID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	out, err := h.todoUsecase.FetchTodoHistories(r.Context(), todoID)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusOK, out)
+}
+
+// PurgeTodos は保持期間を過ぎたゴミ箱のtodoを完全に削除する
+func (h *todoHandler) PurgeTodos(w http.ResponseWriter, r *http.Request) {
+	out, err := h.todoUsecase.PurgeTodos(r.Context())
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusOK, out)
+}
+
+func (h *todoHandler) transitTodo(w http.ResponseWriter, r *http.Request, transit func(ctx context.Context, id int, audit *input.Audit) (*output.Todo, error)) {
+	todoID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	out, err := transit(r.Context(), todoID, newAudit(r))
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	setETag(w, out.Version)
+	presenter.JSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/interfaces/presenter"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+)
+
+// 一括操作のボディは配列で受け取り、1件ごとの結果を配列の順に返す。
+// 個々の項目の If-Match の代わりに version でバージョンを指定できる。
+
+type batchDeleteItem struct {
+	ID      int   `json:"id"`
+	Version *uint `json:"version"`
+}
+
+func (h *todoHandler) BatchCreateTodos(w http.ResponseWriter, r *http.Request) {
+	var items []input.Todo
+	if err := decodeJSON(r, &items); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	ins := make([]*input.Todo, len(items))
+	for i := range items {
+		ins[i] = &items[i]
+	}
+
+	out, err := h.todoUsecase.BatchCreateTodos(r.Context(), ins, newAudit(r))
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.BatchJSON(w, r, out, http.StatusCreated)
+}
+
+func (h *todoHandler) BatchPatchTodos(w http.ResponseWriter, r *http.Request) {
+	if !isMergePatchContentType(r) {
+		presenter.ErrorJSON(w, r, apperrors.UnsupportedMediaType)
+		return
+	}
+
+	// 各要素はPATCH /todos/{id}と同じくオブジェクトのみ受け付ける
+	var items []map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	var fieldErrs apperrors.FieldErrors
+	ins := make([]*input.TodoPatch, len(items))
+	for i, members := range items {
+		if members == nil {
+			presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+			return
+		}
+
+		in := &input.TodoPatch{}
+		var version *uint
+		prefix := fmt.Sprintf("[%d].", i)
+		applyMergePatch(members, map[string]interface{}{
+			"id":      &in.ID,
+			"version": &version,
+		}, prefix, &fieldErrs)
+		delete(members, "id")
+		delete(members, "version")
+		if version != nil {
+			in.ExpectedVersions = []uint{*version}
+		}
+
+		in.NullFields = applyMergePatch(members, map[string]interface{}{
+			"title":              &in.Title,
+			"implementationDate": &in.ImplementationDate,
+			"dueDate":            &in.DueDate,
+			"statusID":           &in.StatusID,
+			"priorityID":         &in.PriorityID,
+			"memo":               &in.Memo,
+			"tagIDs":             &in.TagIDs,
+		}, prefix, &fieldErrs)
+
+		ins[i] = in
+	}
+	if err := fieldErrs.Err(); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	out, err := h.todoUsecase.BatchPatchTodos(r.Context(), ins, newAudit(r))
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.BatchJSON(w, r, out, http.StatusOK)
+}
+
+func (h *todoHandler) BatchDeleteTodos(w http.ResponseWriter, r *http.Request) {
+	h.batchDeleteTodos(w, r, false)
+}
+
+// BatchDeleteTodosIdempotent は存在しないtodoの削除も成功として扱う
+func (h *todoHandler) BatchDeleteTodosIdempotent(w http.ResponseWriter, r *http.Request) {
+	h.batchDeleteTodos(w, r, true)
+}
+
+func (h *todoHandler) batchDeleteTodos(w http.ResponseWriter, r *http.Request, idempotent bool) {
+	var items []batchDeleteItem
+	if err := decodeJSON(r, &items); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	ins := make([]*input.TodoDelete, len(items))
+	for i, item := range items {
+		ins[i] = &input.TodoDelete{
+			ID:         item.ID,
+			Idempotent: idempotent,
+		}
+		if item.Version != nil {
+			ins[i].ExpectedVersions = []uint{*item.Version}
+		}
+	}
+
+	out, err := h.todoUsecase.BatchDeleteTodos(r.Context(), ins, newAudit(r))
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.BatchJSON(w, r, out, http.StatusOK)
+}
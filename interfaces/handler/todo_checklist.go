@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/interfaces/presenter"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+	"github.com/kazumakawahara/todo-sample/usecase/output"
+)
+
+// チェックリストの変更はtodoのバージョンを上げるため、変更後のtodoのバージョンをETagで返す
+
+func (h *todoHandler) AddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	todoID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	expectedVersions, err := ifMatchVersions(r)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	in := input.ChecklistItem{
+		TodoID:           todoID,
+		ExpectedVersions: expectedVersions,
+	}
+	if err := decodeJSON(r, &in); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	out, err := h.todoUsecase.AddChecklistItem(r.Context(), &in, newAudit(r))
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	setETag(w, out.Version)
+	presenter.JSON(w, http.StatusCreated, out)
+}
+
+func (h *todoHandler) ReorderChecklist(w http.ResponseWriter, r *http.Request) {
+	todoID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		presenter.ErrorJSON(w, r, apperrors.InvalidParameter)
+		return
+	}
+
+	expectedVersions, err := ifMatchVersions(r)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	in := input.ChecklistOrder{
+		TodoID:           todoID,
+		ExpectedVersions: expectedVersions,
+	}
+	if err := decodeJSON(r, &in); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	out, err := h.todoUsecase.ReorderChecklist(r.Context(), &in, newAudit(r))
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	setETag(w, out.Version)
+	presenter.JSON(w, http.StatusOK, out)
+}
+
+func (h *todoHandler) CheckChecklistItem(w http.ResponseWriter, r *http.Request) {
+	todoID, itemID, err := checklistItemIDs(r)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	expectedVersions, err := ifMatchVersions(r)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	in := input.ChecklistItemCheck{
+		TodoID:           todoID,
+		ItemID:           itemID,
+		ExpectedVersions: expectedVersions,
+	}
+	if err := decodeJSON(r, &in); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	out, err := h.todoUsecase.CheckChecklistItem(r.Context(), &in, newAudit(r))
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	setETag(w, out.Version)
+	presenter.JSON(w, http.StatusOK, out)
+}
+
+func (h *todoHandler) DeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	todoID, itemID, err := checklistItemIDs(r)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	expectedVersions, err := ifMatchVersions(r)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	in := input.ChecklistItemDelete{
+		TodoID:           todoID,
+		ItemID:           itemID,
+		ExpectedVersions: expectedVersions,
+	}
+
+	out, err := h.todoUsecase.DeleteChecklistItem(r.Context(), &in, newAudit(r))
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	resp := output.DeleteMessage{Message: presenter.Message(r, presenter.ChecklistItemDeletedMessage)}
+
+	setETag(w, out.Version)
+	presenter.JSON(w, http.StatusOK, resp)
+}
+
+// checklistItemIDs はパスのtodoのIDとチェックリストの項目のIDを返す
+func checklistItemIDs(r *http.Request) (int, int, error) {
+	vars := mux.Vars(r)
+
+	todoID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		return 0, 0, apperrors.InvalidParameter
+	}
+
+	itemID, err := strconv.Atoi(vars["itemID"])
+	if err != nil {
+		return 0, 0, apperrors.InvalidParameter
+	}
+
+	return todoID, itemID, nil
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/persistence"
+	"github.com/kazumakawahara/todo-sample/usecase"
+)
+
+// ハンドラのテストはメモリのリポジトリを使ったユースケースを通して、リクエストからレスポンスまでを確認する
+
+const testTodoJSON = `{"title":"title","implementationDate":"2030-04-01T00:00:00Z","dueDate":"2030-04-02T00:00:00Z","priorityID":1}`
+
+// newTestTodoRouter はユーザーID 1 で認証済みとしてtodoのルーティングを返す
+func newTestTodoRouter() http.Handler {
+	repo := persistence.NewTodoMemoryRepository()
+	h := NewTodoHandler(usecase.NewTodoUsecase(
+		repo,
+		persistence.NewMemoryTransactionManager(repo),
+		persistence.NewProjectMemoryRepository(repo),
+		persistence.NewTagMemoryRepository(repo),
+		24*time.Hour,
+	))
+
+	router := mux.NewRouter()
+	router.HandleFunc("/todos", h.CreateTodo).Methods(http.MethodPost)
+	router.HandleFunc("/todos", h.FetchTodos).Methods(http.MethodGet)
+	router.HandleFunc("/todos/{id:[0-9]+}", h.FetchTodo).Methods(http.MethodGet)
+	router.HandleFunc("/todos/{id:[0-9]+}", h.UpdateTodo).Methods(http.MethodPut)
+	router.HandleFunc("/todos/{id:[0-9]+}", h.PatchTodo).Methods(http.MethodPatch)
+	router.HandleFunc("/todos/{id:[0-9]+}", h.DeleteTodo).Methods(http.MethodDelete)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		router.ServeHTTP(w, r.WithContext(userdomain.ContextWithUserID(r.Context(), 1)))
+	})
+}
+
+func serve(handler http.Handler, method, target, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestTodoHandler_CreateTodo(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantETag string
+	}{
+		{name: "正常系", body: testTodoJSON, wantCode: http.StatusCreated, wantETag: `"1"`},
+		{name: "異常系: JSONが不正", body: `{"title":`, wantCode: http.StatusBadRequest},
+		{name: "異常系: タイトルが空", body: `{"title":"","implementationDate":"2030-04-01T00:00:00Z","dueDate":"2030-04-02T00:00:00Z","priorityID":1}`, wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(newTestTodoRouter(), http.MethodPost, "/todos", tt.body, nil)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if got := rec.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %s, want %s", got, tt.wantETag)
+			}
+			if tt.wantCode >= http.StatusBadRequest && !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/problem+json") {
+				t.Errorf("Content-Type = %s, want application/problem+json", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestTodoHandler_FetchTodo(t *testing.T) {
+	router := newTestTodoRouter()
+	if rec := serve(router, http.MethodPost, "/todos", testTodoJSON, nil); rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d: %s", rec.Code, rec.Body)
+	}
+
+	tests := []struct {
+		name     string
+		target   string
+		wantCode int
+	}{
+		{name: "正常系", target: "/todos/1", wantCode: http.StatusOK},
+		{name: "異常系: 存在しないID", target: "/todos/2", wantCode: http.StatusNotFound},
+		{name: "異常系: 0は不正なID", target: "/todos/0", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(router, http.MethodGet, tt.target, "", nil)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var got struct {
+				ID      int    `json:"id"`
+				Title   string `json:"title"`
+				Version uint   `json:"version"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != 1 || got.Title != "title" || got.Version != 1 {
+				t.Errorf("body = %+v", got)
+			}
+			if etag := rec.Header().Get("ETag"); etag != `"1"` {
+				t.Errorf("ETag = %s, want \"1\"", etag)
+			}
+		})
+	}
+}
+
+func TestTodoHandler_UpdateTodo_IfMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		ifMatch  string
+		wantCode int
+		wantETag string
+	}{
+		{name: "正常系: If-Matchなし", wantCode: http.StatusOK, wantETag: `"2"`},
+		{name: "正常系: 一致するバージョン", ifMatch: `"1"`, wantCode: http.StatusOK, wantETag: `"2"`},
+		{name: "正常系: いずれかが一致", ifMatch: `"3", "1"`, wantCode: http.StatusOK, wantETag: `"2"`},
+		{name: "正常系: *", ifMatch: `*`, wantCode: http.StatusOK, wantETag: `"2"`},
+		{name: "異常系: 一致しないバージョン", ifMatch: `"2"`, wantCode: http.StatusPreconditionFailed},
+		{name: "異常系: 弱いエンティティタグ", ifMatch: `W/"1"`, wantCode: http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestTodoRouter()
+			if rec := serve(router, http.MethodPost, "/todos", testTodoJSON, nil); rec.Code != http.StatusCreated {
+				t.Fatalf("create status = %d: %s", rec.Code, rec.Body)
+			}
+
+			var header map[string]string
+			if tt.ifMatch != "" {
+				header = map[string]string{"If-Match": tt.ifMatch}
+			}
+
+			rec := serve(router, http.MethodPatch, "/todos/1", `{"title":"changed"}`, header)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if got := rec.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %s, want %s", got, tt.wantETag)
+			}
+		})
+	}
+}
+
+func TestTodoHandler_DeleteTodo(t *testing.T) {
+	router := newTestTodoRouter()
+	if rec := serve(router, http.MethodPost, "/todos", testTodoJSON, nil); rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d: %s", rec.Code, rec.Body)
+	}
+
+	if rec := serve(router, http.MethodDelete, "/todos/1", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("delete status = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(router, http.MethodGet, "/todos/1", "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("fetch after delete status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if rec := serve(router, http.MethodDelete, "/todos/1", "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("second delete status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
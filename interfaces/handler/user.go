@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/kazumakawahara/todo-sample/interfaces/presenter"
+	"github.com/kazumakawahara/todo-sample/usecase"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+)
+
+type userHandler struct {
+	userUsecase usecase.UserUsecase
+}
+
+func NewUserHandler(userUsecase usecase.UserUsecase) *userHandler {
+	return &userHandler{
+		userUsecase: userUsecase,
+	}
+}
+
+func (h *userHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
+	var in input.UserRegistration
+	if err := decodeJSON(r, &in); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	out, err := h.userUsecase.RegisterUser(r.Context(), &in)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusCreated, out)
+}
+
+func (h *userHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var in input.Login
+	if err := decodeJSON(r, &in); err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	out, err := h.userUsecase.Login(r.Context(), &in)
+	if err != nil {
+		presenter.ErrorJSON(w, r, err)
+		return
+	}
+
+	presenter.JSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,49 @@
+package presenter
+
+import (
+	"net/http"
+
+	"github.com/kazumakawahara/todo-sample/usecase/output"
+)
+
+type batchResponse struct {
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []*batchItem `json:"results"`
+}
+
+// batchItem は一括操作の1件分の結果。失敗した場合はエラーと同じ形式の error を持つ
+type batchItem struct {
+	Index  int          `json:"index"`
+	Status int          `json:"status"`
+	ID     int          `json:"id,omitempty"`
+	Todo   *output.Todo `json:"todo,omitempty"`
+	Error  *problem     `json:"error,omitempty"`
+}
+
+// BatchJSON は一括操作の結果を返す。1件ごとの成否はstatusで表し、全体は常に200とする
+func BatchJSON(w http.ResponseWriter, r *http.Request, results []*output.BatchResult, successStatus int) {
+	lang := negotiateLanguage(r)
+
+	resp := &batchResponse{Results: make([]*batchItem, len(results))}
+	for i, result := range results {
+		item := &batchItem{
+			Index:  i,
+			Status: successStatus,
+			ID:     result.ID,
+			Todo:   result.Todo,
+		}
+		if result.Err != nil {
+			item.Error = newProblem(lang, result.Err)
+			item.Status = item.Error.Status
+			resp.Failed++
+		} else {
+			resp.Succeeded++
+		}
+
+		resp.Results[i] = item
+	}
+
+	w.Header().Add("Vary", "Accept-Language")
+	JSON(w, http.StatusOK, resp)
+}
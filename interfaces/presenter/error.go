@@ -8,31 +8,69 @@ import (
 	"github.com/kazumakawahara/todo-sample/apperrors"
 )
 
-type httpError struct {
-	StatusCode   int    `json:"status"`
-	ErrorMessage string `json:"error"`
+// problemTypePrefix にコードを付けたURIをRFC 7807のtypeとする
+const problemTypePrefix = "urn:todo-sample:problem:"
+
+// problem は RFC 7807 の application/problem+json のレスポンス
+type problem struct {
+	Type       string                `json:"type"`
+	Title      string                `json:"title"`
+	Status     int                   `json:"status"`
+	Detail     string                `json:"detail,omitempty"`
+	Code       string                `json:"code"`
+	MessageKey string                `json:"messageKey"`
+	Details    apperrors.FieldErrors `json:"details,omitempty"`
 }
 
-func (e *httpError) Error() string {
-	b, err := json.Marshal(&e)
-	if err != nil {
+func ErrorJSON(w http.ResponseWriter, r *http.Request, err error) {
+	lang := negotiateLanguage(r)
+	p := newProblem(lang, err)
+
+	w.Header().Set("Content-Type", "application/problem+json; charset=UTF-8")
+	w.Header().Set("Content-Language", string(lang))
+	w.Header().Add("Vary", "Accept-Language")
+	w.WriteHeader(p.Status)
+	if err = json.NewEncoder(w).Encode(p); err != nil {
 		log.Println(err)
 	}
-
-	return string(b)
 }
 
-func ErrorJSON(w http.ResponseWriter, err error) {
+func newProblem(lang language, err error) *problem {
 	appErr := apperrors.AsAppError(err)
 
-	httpErr := &httpError{
-		StatusCode:   appErr.StatusCode(),
-		ErrorMessage: appErr.Error(),
+	// 原因はレスポンスに含めず、サーバー側のエラーのみログに出力する
+	if appErr.StatusCode() >= http.StatusInternalServerError && appErr.Unwrap() != nil {
+		log.Printf("%d %s", appErr.StatusCode(), appErr.Error())
+	}
+
+	detail, _ := lookup(lang, appErr.MessageKey())
+
+	return &problem{
+		Type:       problemTypePrefix + appErr.Code(),
+		Title:      http.StatusText(appErr.StatusCode()),
+		Status:     appErr.StatusCode(),
+		Detail:     detail,
+		Code:       appErr.Code(),
+		MessageKey: appErr.MessageKey(),
+		Details:    localizeDetails(lang, appErr.Details()),
 	}
+}
 
-	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-	w.WriteHeader(httpErr.StatusCode)
-	if err = json.NewEncoder(w).Encode(httpErr); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+// localizeDetails は項目のエラーのメッセージをルールごとのメッセージに置き換える。
+// カタログにないルールとデフォルトの言語は値オブジェクトのメッセージのままにする。
+func localizeDetails(lang language, details apperrors.FieldErrors) apperrors.FieldErrors {
+	if lang == defaultLanguage || len(details) == 0 {
+		return details
 	}
+
+	localized := make(apperrors.FieldErrors, len(details))
+	for i, d := range details {
+		fe := *d
+		if msg, ok := catalog[lang]["validation."+d.Rule]; ok {
+			fe.Message = msg
+		}
+		localized[i] = &fe
+	}
+
+	return localized
 }
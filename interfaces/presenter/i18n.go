@@ -0,0 +1,155 @@
+package presenter
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type language string
+
+const (
+	japanese language = "ja"
+	english  language = "en"
+
+	// Accept-Languageがない・対応していない場合の言語
+	defaultLanguage = japanese
+)
+
+// レスポンスのメッセージのキー
+const (
+	TodoDeletedMessage          = "messages.TodoDeleted"
+	MemberDeletedMessage        = "messages.MemberDeleted"
+	APIKeyRevokedMessage        = "messages.APIKeyRevoked"
+	TagDeletedMessage           = "messages.TagDeleted"
+	ChecklistItemDeletedMessage = "messages.ChecklistItemDeleted"
+)
+
+// catalog は言語ごとのメッセージ。エラーは apperrors のメッセージキー、項目のエラーは validation.<ルール> で引く。
+var catalog = map[language]map[string]string{
+	japanese: {
+		TodoDeletedMessage:          "削除しました。",
+		MemberDeletedMessage:        "メンバーを削除しました。",
+		APIKeyRevokedMessage:        "APIキーを失効させました。",
+		TagDeletedMessage:           "タグを削除しました。",
+		ChecklistItemDeletedMessage: "チェックリストの項目を削除しました。",
+
+		"errors.InvalidParameter":               "パラメータが不正です。",
+		"errors.InternalServerError":            "サーバーでエラーが発生しました。",
+		"errors.TodoNotFound":                   "todoが見つかりません。",
+		"errors.TagNotFound":                    "タグが見つかりません。",
+		"errors.ChecklistItemNotFound":          "チェックリストの項目が見つかりません。",
+		"errors.UnsupportedMediaType":           "Content-Typeはapplication/jsonで指定してください。",
+		"errors.PreconditionFailed":             "todoは他で更新されています。取得し直してください。",
+		"errors.InvalidReference":               "参照先が存在しません。",
+		"errors.Conflict":                       "既に登録されています。",
+		"errors.ServiceUnavailable":             "一時的に利用できません。時間をおいて再度お試しください。",
+		"errors.ImplementationDateAfterDueDate": "実施日は期限日以前の日付で指定してください。",
+		"errors.DoneTodoDatesLocked":            "作業完了のtodoは日付を変更できません。",
+		"errors.InvalidStatusTransition":        "このステータスには変更できません。",
+		"errors.TooManyChecklistItems":          "チェックリストの項目数が上限に達しています。",
+		"errors.InvalidChecklistOrder":          "チェックリストの全ての項目のIDを1回ずつ指定してください。",
+		"errors.Unauthorized":                   "認証が必要です。ログインし直してください。",
+		"errors.InvalidCredentials":             "メールアドレスまたはパスワードが正しくありません。",
+		"errors.UserNotFound":                   "ユーザーが見つかりません。",
+		"errors.Forbidden":                      "この操作を行う権限がありません。",
+		"errors.ProjectNotFound":                "プロジェクトが見つかりません。",
+		"errors.ProjectOwnerRequired":           "プロジェクトにはオーナーが1人以上必要です。",
+		"errors.APIKeyNotFound":                 "APIキーが見つかりません。",
+	},
+	english: {
+		TodoDeletedMessage:          "Deleted.",
+		MemberDeletedMessage:        "The member has been removed.",
+		APIKeyRevokedMessage:        "The API key has been revoked.",
+		TagDeletedMessage:           "The tag has been deleted.",
+		ChecklistItemDeletedMessage: "The checklist item has been deleted.",
+
+		"errors.InvalidParameter":               "Invalid parameters.",
+		"errors.InternalServerError":            "An internal server error occurred.",
+		"errors.TodoNotFound":                   "Todo not found.",
+		"errors.TagNotFound":                    "Tag not found.",
+		"errors.ChecklistItemNotFound":          "Checklist item not found.",
+		"errors.UnsupportedMediaType":           "Content-Type must be application/json.",
+		"errors.PreconditionFailed":             "The todo has been modified. Fetch it again and retry.",
+		"errors.InvalidReference":               "The referenced resource does not exist.",
+		"errors.Conflict":                       "The resource already exists.",
+		"errors.ServiceUnavailable":             "Temporarily unavailable. Please try again later.",
+		"errors.ImplementationDateAfterDueDate": "The implementation date must be on or before the due date.",
+		"errors.DoneTodoDatesLocked":            "The dates of a done todo cannot be changed.",
+		"errors.InvalidStatusTransition":        "The status cannot be changed to the requested value.",
+		"errors.TooManyChecklistItems":          "The checklist has reached the maximum number of items.",
+		"errors.InvalidChecklistOrder":          "Specify the ID of every checklist item exactly once.",
+		"errors.Unauthorized":                   "Authentication is required. Please log in again.",
+		"errors.InvalidCredentials":             "The email address or password is incorrect.",
+		"errors.UserNotFound":                   "User not found.",
+		"errors.Forbidden":                      "You do not have permission to perform this operation.",
+		"errors.ProjectNotFound":                "Project not found.",
+		"errors.ProjectOwnerRequired":           "A project must have at least one owner.",
+		"errors.APIKeyNotFound":                 "API key not found.",
+
+		// 日本語は値オブジェクトのメッセージをそのまま使う
+		"validation.required":  "This field is required.",
+		"validation.maxLength": "This field is too long.",
+		"validation.minLength": "This field is too short.",
+		"validation.oneOf":     "This field has an unsupported value.",
+		"validation.positive":  "This field must be a positive integer.",
+		"validation.range":     "This field is out of range.",
+		"validation.type":      "This field has an invalid type or format.",
+		"validation.cursor":    "The cursor is invalid.",
+		"validation.unique":    "The same ID is specified more than once.",
+		"validation.email":     "This field must be a valid email address.",
+	},
+}
+
+// Message はリクエストのAccept-Languageに合わせたメッセージを返す
+func Message(r *http.Request, key string) string {
+	msg, _ := lookup(negotiateLanguage(r), key)
+	return msg
+}
+
+// lookup はキーのメッセージを返す。選んだ言語になければデフォルトの言語で引く。
+func lookup(lang language, key string) (string, bool) {
+	if msg, ok := catalog[lang][key]; ok {
+		return msg, true
+	}
+
+	msg, ok := catalog[defaultLanguage][key]
+	return msg, ok
+}
+
+// negotiateLanguage はAccept-Languageのq値が大きい順に対応している言語を選ぶ
+func negotiateLanguage(r *http.Request) language {
+	type candidate struct {
+		lang language
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			f, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+
+		// en-US などは言語部分だけで判定する
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if _, ok := catalog[language(base)]; ok && q > 0 {
+			candidates = append(candidates, candidate{lang: language(base), q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return defaultLanguage
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	return candidates[0].lang
+}
@@ -0,0 +1,84 @@
+package presenter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           language
+	}{
+		{name: "正常系: 指定なしはデフォルトの言語", acceptLanguage: "", want: defaultLanguage},
+		{name: "正常系: 英語", acceptLanguage: "en", want: english},
+		{name: "正常系: 地域のサブタグは言語部分で判定する", acceptLanguage: "en-US", want: english},
+		{name: "正常系: 大文字小文字を区別しない", acceptLanguage: "EN-gb", want: english},
+		{name: "正常系: q値が大きい言語を選ぶ", acceptLanguage: "ja;q=0.5, en;q=0.8", want: english},
+		{name: "正常系: q値の省略は1", acceptLanguage: "ja;q=0.9, en", want: english},
+		{name: "正常系: 同じq値なら先に指定した言語", acceptLanguage: "en;q=0.8, ja;q=0.8", want: english},
+		{name: "正常系: 対応していない言語は無視する", acceptLanguage: "fr, de;q=0.9, en;q=0.1", want: english},
+		{name: "正常系: ワイルドカードは無視する", acceptLanguage: "*, en;q=0.5", want: english},
+		{name: "正常系: q=0の言語は選ばない", acceptLanguage: "en;q=0, ja;q=0.1", want: japanese},
+		{name: "正常系: q=0のみならデフォルトの言語", acceptLanguage: "en;q=0", want: defaultLanguage},
+		{name: "正常系: 不正なq値の言語は無視する", acceptLanguage: "en;q=abc, ja;q=0.1", want: japanese},
+		{name: "正常系: q値以外のパラメータはq=1として扱う", acceptLanguage: "ja;q=0.5, en;level=1", want: english},
+		{name: "正常系: 対応している言語がなければデフォルトの言語", acceptLanguage: "fr-FR, zh;q=0.9", want: defaultLanguage},
+		{name: "正常系: 空の要素は無視する", acceptLanguage: " , ,en", want: english},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			if got := negotiateLanguage(r); got != tt.want {
+				t.Errorf("negotiateLanguage() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		lang   language
+		key    string
+		want   string
+		wantOK bool
+	}{
+		{name: "正常系: 日本語", lang: japanese, key: TodoDeletedMessage, want: "削除しました。", wantOK: true},
+		{name: "正常系: 英語", lang: english, key: TodoDeletedMessage, want: "Deleted.", wantOK: true},
+		{name: "正常系: 英語のみのキー", lang: english, key: "validation.required", want: "This field is required.", wantOK: true},
+		{name: "正常系: 選んだ言語になければデフォルトの言語", lang: "fr", key: TodoDeletedMessage, want: "削除しました。", wantOK: true},
+		{name: "異常系: 存在しないキー", lang: english, key: "errors.Unknown", want: "", wantOK: false},
+		{name: "異常系: デフォルトの言語にないキー", lang: japanese, key: "validation.required", want: "", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := lookup(tt.lang, tt.key)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("lookup() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCatalog_Keys(t *testing.T) {
+	// 日本語のメッセージは全て英語にも用意する
+	for key := range catalog[japanese] {
+		if _, ok := catalog[english][key]; !ok {
+			t.Errorf("catalog[english] has no %q", key)
+		}
+	}
+	// 英語のみのキーは値オブジェクトのメッセージを置き換える項目のエラーに限る
+	for key := range catalog[english] {
+		if _, ok := catalog[japanese][key]; !ok && !strings.HasPrefix(key, "validation.") {
+			t.Errorf("catalog[japanese] has no %q", key)
+		}
+	}
+}
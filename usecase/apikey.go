@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/apikeydomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+	"github.com/kazumakawahara/todo-sample/usecase/output"
+)
+
+type APIKeyUsecase interface {
+	CreateAPIKey(ctx context.Context, in *input.APIKey) (*output.CreatedAPIKey, error)
+	FetchAPIKeys(ctx context.Context) ([]*output.APIKey, error)
+	RevokeAPIKey(ctx context.Context, apiKeyID int) error
+	Authenticate(ctx context.Context, in *input.APIKeyAuthentication) (*output.AuthenticatedAPIKey, error)
+}
+
+type apiKeyUsecase struct {
+	apiKeyRepository apikeydomain.Repository
+	keyManager       apikeydomain.KeyManager
+}
+
+// NewAPIKeyUsecase はCIやスクリプト向けのAPIキーを発行・検証するユースケースを返す。
+// キーは発行したユーザーとして扱い、参照できるtodoはそのユーザーと同じになる。
+func NewAPIKeyUsecase(apiKeyRepository apikeydomain.Repository, keyManager apikeydomain.KeyManager) *apiKeyUsecase {
+	return &apiKeyUsecase{
+		apiKeyRepository: apiKeyRepository,
+		keyManager:       keyManager,
+	}
+}
+
+// CreateAPIKey は認証したユーザーのキーを発行する
+func (u *apiKeyUsecase) CreateAPIKey(ctx context.Context, in *input.APIKey) (*output.CreatedAPIKey, error) {
+	userID, ok := userdomain.UserIDFromContext(ctx)
+	if !ok {
+		return nil, apperrors.Unauthorized
+	}
+
+	var fieldErrs apperrors.FieldErrors
+
+	nameVo, err := apikeydomain.NewName(in.Name)
+	fieldErrs.Add("name", err)
+
+	scopeVo, err := apikeydomain.NewScope(in.Scope)
+	fieldErrs.Add("scope", err)
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	key, prefix, err := u.keyManager.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeyDm := apikeydomain.NewAPIKeyWhenUnCreated(
+		userID,
+		nameVo,
+		scopeVo,
+		prefix,
+		u.keyManager.Hash(key),
+		time.Now().UTC().Truncate(time.Second),
+	)
+
+	idVo, err := u.apiKeyRepository.CreateAPIKey(ctx, apiKeyDm)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeyDm, err = u.apiKeyRepository.FetchAPIKeyByID(ctx, idVo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output.CreatedAPIKey{
+		APIKey: *newAPIKeyOutput(apiKeyDm),
+		Key:    key,
+	}, nil
+}
+
+// FetchAPIKeys は認証したユーザーが発行したキーを失効したものも含めて返す
+func (u *apiKeyUsecase) FetchAPIKeys(ctx context.Context) ([]*output.APIKey, error) {
+	userID, ok := userdomain.UserIDFromContext(ctx)
+	if !ok {
+		return nil, apperrors.Unauthorized
+	}
+
+	apiKeyDms, err := u.apiKeyRepository.FetchAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*output.APIKey, len(apiKeyDms))
+	for i, apiKeyDm := range apiKeyDms {
+		out[i] = newAPIKeyOutput(apiKeyDm)
+	}
+
+	return out, nil
+}
+
+// RevokeAPIKey はキーを失効させる。失効済みのキーはそのまま成功とする
+func (u *apiKeyUsecase) RevokeAPIKey(ctx context.Context, apiKeyID int) error {
+	userID, ok := userdomain.UserIDFromContext(ctx)
+	if !ok {
+		return apperrors.Unauthorized
+	}
+
+	idVo, err := apikeydomain.NewID(apiKeyID)
+	if err != nil {
+		var fieldErrs apperrors.FieldErrors
+		fieldErrs.Add("id", err)
+		return fieldErrs.Err()
+	}
+
+	// 他のユーザーのキーは存在を明かさない
+	if _, err = u.apiKeyRepository.FetchAPIKeyByID(ctx, idVo, userID); err != nil {
+		return err
+	}
+
+	return u.apiKeyRepository.RevokeAPIKey(ctx, idVo, time.Now().UTC().Truncate(time.Second))
+}
+
+// Authenticate はキーを検証し、キーを発行したユーザーを返す。
+// 存在しない・失効したキーは apperrors.Unauthorized、スコープが足りなければ apperrors.Forbidden を返す。
+func (u *apiKeyUsecase) Authenticate(ctx context.Context, in *input.APIKeyAuthentication) (*output.AuthenticatedAPIKey, error) {
+	apiKeyDm, err := u.apiKeyRepository.FetchAPIKeyByHash(ctx, u.keyManager.Hash(in.Key))
+	if errors.Is(err, apperrors.APIKeyNotFound) {
+		return nil, apperrors.Unauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKeyDm.IsRevoked() {
+		return nil, apperrors.Unauthorized
+	}
+
+	if in.Write && !apiKeyDm.Scope().CanWrite() {
+		return nil, apperrors.Forbidden
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if apiKeyDm.NeedsTouch(now) {
+		if err = u.apiKeyRepository.UpdateLastUsedAt(ctx, apiKeyDm.ID(), now); err != nil {
+			return nil, err
+		}
+	}
+
+	return &output.AuthenticatedAPIKey{
+		ID:     apiKeyDm.ID().Value(),
+		UserID: apiKeyDm.UserID().Value(),
+	}, nil
+}
+
+func newAPIKeyOutput(apiKeyDm *apikeydomain.APIKey) *output.APIKey {
+	return &output.APIKey{
+		ID:         apiKeyDm.ID().Value(),
+		Name:       apiKeyDm.Name().Value(),
+		Scope:      apiKeyDm.Scope().Value(),
+		Prefix:     apiKeyDm.Prefix(),
+		CreatedAt:  apiKeyDm.CreatedAt(),
+		LastUsedAt: apiKeyDm.LastUsedAt(),
+		RevokedAt:  apiKeyDm.RevokedAt(),
+	}
+}
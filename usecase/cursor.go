@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+)
+
+// cursor はクライアントに渡すページングカーソルの中身。
+// ソート条件が変わった場合に使い回せないようソート項目と並び順も含める。
+type cursor struct {
+	SortField tododomain.SortField `json:"s"`
+	SortOrder tododomain.SortOrder `json:"o"`
+	ID        int                  `json:"id"`
+	Value     json.RawMessage      `json:"v"`
+}
+
+func encodeCursor(todoDm *tododomain.Todo, sortField tododomain.SortField, sortOrder tododomain.SortOrder) (string, error) {
+	value, err := json.Marshal(todoDm.SortKey(sortField))
+	if err != nil {
+		return "", apperrors.InternalServerError.Wrap(err)
+	}
+
+	b, err := json.Marshal(&cursor{
+		SortField: sortField,
+		SortOrder: sortOrder,
+		ID:        todoDm.ID().Value(),
+		Value:     value,
+	})
+	if err != nil {
+		return "", apperrors.InternalServerError.Wrap(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(s string, sortField tododomain.SortField, sortOrder tododomain.SortOrder) (*tododomain.Cursor, error) {
+	invalid := apperrors.NewValidationError("cursor", s, "カーソルが不正です")
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var c cursor
+	if err = json.Unmarshal(b, &c); err != nil {
+		return nil, invalid
+	}
+
+	if c.SortField != sortField || c.SortOrder != sortOrder {
+		return nil, apperrors.NewValidationError("cursor", s, "カーソルとソート条件が一致しません")
+	}
+
+	idVo, err := tododomain.NewID(c.ID)
+	if err != nil {
+		return nil, invalid
+	}
+
+	// Todo.SortKey と同じ型で復元する
+	var value interface{}
+	switch sortField {
+	case tododomain.SortByTitle:
+		var v string
+		err = json.Unmarshal(c.Value, &v)
+		value = v
+	case tododomain.SortByImplementationDate, tododomain.SortByDueDate:
+		var v time.Time
+		err = json.Unmarshal(c.Value, &v)
+		value = v
+	case tododomain.SortByStatus, tododomain.SortByPriority:
+		var v uint
+		err = json.Unmarshal(c.Value, &v)
+		value = v
+	default:
+		var v int
+		err = json.Unmarshal(c.Value, &v)
+		value = v
+	}
+	if err != nil {
+		return nil, invalid
+	}
+
+	return &tododomain.Cursor{
+		ID:    idVo,
+		Value: value,
+	}, nil
+}
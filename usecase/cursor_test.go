@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	todoDm := tododomain.NewTodo(tododomain.TodoParams{
+		ID:                 7,
+		Title:              "title",
+		ImplementationDate: tododomain.ImplementationDate(time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)),
+		DueDate:            tododomain.DueDate(time.Date(2022, 4, 2, 0, 0, 0, 0, time.UTC)),
+		Status:             tododomain.DOING,
+		Priority:           tododomain.HIGH,
+		Version:            tododomain.InitialVersion,
+	})
+
+	tests := []struct {
+		name      string
+		sortField tododomain.SortField
+		sortOrder tododomain.SortOrder
+	}{
+		{name: "正常系: ID", sortField: tododomain.SortByID, sortOrder: tododomain.Asc},
+		{name: "正常系: タイトル", sortField: tododomain.SortByTitle, sortOrder: tododomain.Desc},
+		{name: "正常系: 実施日", sortField: tododomain.SortByImplementationDate, sortOrder: tododomain.Asc},
+		{name: "正常系: 期限日", sortField: tododomain.SortByDueDate, sortOrder: tododomain.Desc},
+		{name: "正常系: ステータス", sortField: tododomain.SortByStatus, sortOrder: tododomain.Asc},
+		{name: "正常系: 優先度", sortField: tododomain.SortByPriority, sortOrder: tododomain.Desc},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := encodeCursor(todoDm, tt.sortField, tt.sortOrder)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := decodeCursor(s, tt.sortField, tt.sortOrder)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != todoDm.ID() {
+				t.Errorf("ID = %v, want %v", got.ID, todoDm.ID())
+			}
+			// リポジトリでの比較に使うため SortKey と同じ型で復元する
+			want := todoDm.SortKey(tt.sortField)
+			if reflect.TypeOf(got.Value) != reflect.TypeOf(want) {
+				t.Fatalf("Value type = %T, want %T", got.Value, want)
+			}
+			if wantTime, ok := want.(time.Time); ok {
+				if !got.Value.(time.Time).Equal(wantTime) {
+					t.Errorf("Value = %v, want %v", got.Value, want)
+				}
+			} else if got.Value != want {
+				t.Errorf("Value = %v, want %v", got.Value, want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	encode := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+
+	tests := []struct {
+		name        string
+		cursor      string
+		sortField   tododomain.SortField
+		sortOrder   tododomain.SortOrder
+		wantMessage string
+	}{
+		{
+			name:        "異常系: base64でない",
+			cursor:      "!!!",
+			sortField:   tododomain.SortByID,
+			sortOrder:   tododomain.Asc,
+			wantMessage: "カーソルが不正です",
+		},
+		{
+			name:        "異常系: 空文字",
+			cursor:      "",
+			sortField:   tododomain.SortByID,
+			sortOrder:   tododomain.Asc,
+			wantMessage: "カーソルが不正です",
+		},
+		{
+			name:        "異常系: JSONでない",
+			cursor:      encode(`not json`),
+			sortField:   tododomain.SortByID,
+			sortOrder:   tododomain.Asc,
+			wantMessage: "カーソルが不正です",
+		},
+		{
+			name:        "異常系: ソート項目が異なる",
+			cursor:      encode(`{"s":"title","o":"asc","id":1,"v":"a"}`),
+			sortField:   tododomain.SortByID,
+			sortOrder:   tododomain.Asc,
+			wantMessage: "カーソルとソート条件が一致しません",
+		},
+		{
+			name:        "異常系: 並び順が異なる",
+			cursor:      encode(`{"s":"id","o":"desc","id":1,"v":1}`),
+			sortField:   tododomain.SortByID,
+			sortOrder:   tododomain.Asc,
+			wantMessage: "カーソルとソート条件が一致しません",
+		},
+		{
+			name:        "異常系: IDが0",
+			cursor:      encode(`{"s":"id","o":"asc","id":0,"v":0}`),
+			sortField:   tododomain.SortByID,
+			sortOrder:   tododomain.Asc,
+			wantMessage: "カーソルが不正です",
+		},
+		{
+			name:        "異常系: 値の型がソート項目と一致しない",
+			cursor:      encode(`{"s":"title","o":"asc","id":1,"v":1}`),
+			sortField:   tododomain.SortByTitle,
+			sortOrder:   tododomain.Asc,
+			wantMessage: "カーソルが不正です",
+		},
+		{
+			name:        "異常系: 日付でない",
+			cursor:      encode(`{"s":"dueDate","o":"asc","id":1,"v":"2022-04-01"}`),
+			sortField:   tododomain.SortByDueDate,
+			sortOrder:   tododomain.Asc,
+			wantMessage: "カーソルが不正です",
+		},
+		{
+			name:        "異常系: 負のステータス",
+			cursor:      encode(`{"s":"status","o":"asc","id":1,"v":-1}`),
+			sortField:   tododomain.SortByStatus,
+			sortOrder:   tododomain.Asc,
+			wantMessage: "カーソルが不正です",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeCursor(tt.cursor, tt.sortField, tt.sortOrder)
+			if !errors.Is(err, apperrors.InvalidParameter) {
+				t.Fatalf("decodeCursor() error = %v, want InvalidParameter", err)
+			}
+			if err.Error() != tt.wantMessage {
+				t.Errorf("decodeCursor() error = %q, want %q", err.Error(), tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	highlightPreTag  = "<em>"
+	highlightPostTag = "</em>"
+
+	// スニペットに含める最初の一致箇所より前の文字数
+	snippetLeading = 20
+	snippetLength  = 80
+)
+
+// highlight はtext中のtermsに一致する箇所をタグで囲んで返す。
+// タグ以外の部分はHTMLエスケープする。maxLengthが0より大きければ最初の一致箇所周辺に切り詰める。
+// 一致箇所がなければ空文字を返す。
+func highlight(text string, terms []string, maxLength int) string {
+	runes := []rune(text)
+	matched := matchRanges(runes, terms)
+
+	first := -1
+	for i, m := range matched {
+		if m {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	start, end := 0, len(runes)
+	if maxLength > 0 && len(runes) > maxLength {
+		start = first - snippetLeading
+		if start < 0 {
+			start = 0
+		}
+
+		end = start + maxLength
+		if end > len(runes) {
+			end = len(runes)
+			start = end - maxLength
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && matched[j] == matched[i] {
+			j++
+		}
+
+		if matched[i] {
+			b.WriteString(highlightPreTag)
+			b.WriteString(html.EscapeString(string(runes[i:j])))
+			b.WriteString(highlightPostTag)
+		} else {
+			b.WriteString(html.EscapeString(string(runes[i:j])))
+		}
+
+		i = j
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
+
+// highlightResult はキーワードに一致する箇所を強調する。
+// キーワードを含まずにリポジトリが別の語で一致と判定した項目は、その語に一致する箇所を強調する。
+func highlightResult(text string, terms, matchedTerms []string, maxLength int) string {
+	if h := highlight(text, terms, maxLength); h != "" || len(matchedTerms) == 0 {
+		return h
+	}
+
+	return highlight(text, matchedTerms, maxLength)
+}
+
+// matchRanges は大文字小文字を区別せずtermsのいずれかに一致する文字の位置をtrueにして返す
+func matchRanges(runes []rune, terms []string) []bool {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	matched := make([]bool, len(runes))
+	for _, term := range terms {
+		termRunes := []rune(strings.ToLower(term))
+		if len(termRunes) == 0 {
+			continue
+		}
+
+		for i := 0; i+len(termRunes) <= len(lower); i++ {
+			if string(lower[i:i+len(termRunes)]) == string(termRunes) {
+				for j := i; j < i+len(termRunes); j++ {
+					matched[j] = true
+				}
+			}
+		}
+	}
+
+	return matched
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import "testing"
+
+func TestHighlightResult(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		terms        []string
+		matchedTerms []string
+		want         string
+	}{
+		{name: "正常系: キーワードに一致", text: "東京都の<観光>", terms: []string{"東京都"}, matchedTerms: []string{"東京", "京都"}, want: "<em>東京都</em>の&lt;観光&gt;"},
+		{name: "正常系: キーワードを含まなければ一致と判定した語を強調", text: "京都と東京", terms: []string{"東京都"}, matchedTerms: []string{"東京", "京都"}, want: "<em>京都</em>と<em>東京</em>"},
+		{name: "正常系: 一致箇所がない", text: "大阪", terms: []string{"東京都"}, matchedTerms: []string{"東京", "京都"}, want: ""},
+		{name: "正常系: 一致と判定した語がない", text: "京都", terms: []string{"東京都"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightResult(tt.text, tt.terms, tt.matchedTerms, 0); got != tt.want {
+				t.Errorf("highlightResult() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
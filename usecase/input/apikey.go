@@ -0,0 +1,12 @@
+package input
+
+type APIKey struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+}
+
+type APIKeyAuthentication struct {
+	Key string
+	// 変更を伴う操作か。読み取り専用のキーでは拒否する
+	Write bool
+}
@@ -0,0 +1,32 @@
+package input
+
+type ChecklistItem struct {
+	TodoID int    `json:"-"`
+	Title  string `json:"title"`
+	// If-Matchで指定されたバージョン。空なら検証しない
+	ExpectedVersions []uint `json:"-"`
+}
+
+type ChecklistOrder struct {
+	TodoID int `json:"-"`
+	// 並べ替え後の項目のID。全ての項目のIDを1回ずつ指定する
+	ItemIDs []int `json:"itemIDs"`
+	// If-Matchで指定されたバージョン。空なら検証しない
+	ExpectedVersions []uint `json:"-"`
+}
+
+type ChecklistItemCheck struct {
+	TodoID int `json:"-"`
+	ItemID int `json:"-"`
+	// 必須。nilなら未指定
+	Done *bool `json:"done"`
+	// If-Matchで指定されたバージョン。空なら検証しない
+	ExpectedVersions []uint `json:"-"`
+}
+
+type ChecklistItemDelete struct {
+	TodoID int
+	ItemID int
+	// If-Matchで指定されたバージョン。空なら検証しない
+	ExpectedVersions []uint
+}
@@ -0,0 +1,11 @@
+package input
+
+type Project struct {
+	Name string `json:"name"`
+}
+
+type ProjectMember struct {
+	ProjectID int    `json:"-"`
+	UserID    int    `json:"-"`
+	Role      string `json:"role"`
+}
@@ -0,0 +1,6 @@
+package input
+
+type Tag struct {
+	ID   int    `json:"-"`
+	Name string `json:"name"`
+}
@@ -10,4 +10,61 @@ type Todo struct {
 	StatusID           uint      `json:"statusID"`
 	PriorityID         uint      `json:"priorityID"`
 	Memo               string    `json:"memo"`
+	// 所属するプロジェクト。0ならプロジェクトに属さない個人のtodo
+	ProjectID int `json:"projectID"`
+	// 付けるタグ。更新時にnilなら現在のタグを変えず、空なら全て外す
+	TagIDs []int `json:"tagIDs"`
+	// If-Matchで指定されたバージョン。空なら検証しない
+	ExpectedVersions []uint `json:"-"`
+}
+
+type TodoCriteria struct {
+	StatusIDs   []uint
+	PriorityIDs []uint
+	DueDateFrom time.Time
+	DueDateTo   time.Time
+	Title       string
+	Memo        string
+	ProjectID   int
+	TagIDs      []uint
+	TagMatch    string
+	Sort        string
+	Order       string
+	Cursor      string
+	Limit       int
+}
+
+type TodoSearch struct {
+	Query string
+	Limit int
+}
+
+// TodoPatch はJSON Merge Patch (RFC 7396) で指定された項目のみを持つ。nilの項目は変更しない。
+type TodoPatch struct {
+	ID                 int
+	Title              *string
+	ImplementationDate *time.Time
+	DueDate            *time.Time
+	StatusID           *uint
+	PriorityID         *uint
+	Memo               *string
+	TagIDs             *[]int
+	// nullが指定された項目 (RFC 7396では項目の削除を意味する)
+	NullFields []string
+	// If-Matchで指定されたバージョン。空なら検証しない
+	ExpectedVersions []uint
+}
+
+type TodoDelete struct {
+	ID int
+	// If-Matchで指定されたバージョン。空なら検証しない
+	ExpectedVersions []uint
+	// trueなら存在しないtodoの削除も成功とする
+	Idempotent bool
+}
+
+// Audit は変更履歴に記録する操作者とリクエストの情報
+type Audit struct {
+	Actor     string
+	RequestID string
 }
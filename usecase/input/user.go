@@ -0,0 +1,11 @@
+package input
+
+type UserRegistration struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type Login struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
@@ -0,0 +1,25 @@
+package output
+
+import "time"
+
+type APIKey struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Scope      string     `json:"scope"`
+	Prefix     string     `json:"prefix"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// CreatedAPIKey は発行したキー。キーそのものはこのときにしか返さない
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// AuthenticatedAPIKey はAPIキーで認証したユーザー
+type AuthenticatedAPIKey struct {
+	ID     int
+	UserID int
+}
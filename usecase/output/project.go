@@ -0,0 +1,16 @@
+package output
+
+import "time"
+
+type Project struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// 認証したユーザーのプロジェクトでの権限
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ProjectMember struct {
+	UserID int    `json:"userID"`
+	Role   string `json:"role"`
+}
@@ -0,0 +1,6 @@
+package output
+
+type Tag struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
@@ -3,15 +3,73 @@ package output
 import "time"
 
 type Todo struct {
-	ID                 int       `json:"id"`
-	Title              string    `json:"title"`
-	ImplementationDate time.Time `json:"implementationDate"`
-	DueDate            time.Time `json:"dueDate"`
-	StatusID           uint      `json:"statusID"`
-	PriorityID         uint      `json:"priorityID"`
-	Memo               string    `json:"memo"`
+	ID                 int              `json:"id"`
+	Title              string           `json:"title"`
+	ImplementationDate time.Time        `json:"implementationDate"`
+	DueDate            time.Time        `json:"dueDate"`
+	StatusID           uint             `json:"statusID"`
+	PriorityID         uint             `json:"priorityID"`
+	Memo               string           `json:"memo"`
+	Version            uint             `json:"version"`
+	DeletedAt          *time.Time       `json:"deletedAt,omitempty"`
+	ProjectID          int              `json:"projectID,omitempty"`
+	Tags               []*Tag           `json:"tags"`
+	Checklist          []*ChecklistItem `json:"checklist"`
+	// チェックリストの完了した項目の割合(0〜100)。項目がなければ0
+	Progress int `json:"progress"`
+}
+
+type ChecklistItem struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Done  bool   `json:"done"`
 }
 
 type DeleteMessage struct {
 	Message string `json:"message"`
 }
+
+type PurgeResult struct {
+	// 完全に削除したtodoの件数
+	Purged int `json:"purged"`
+}
+
+type TodoList struct {
+	Todos []*Todo
+	// 次のページがなければ空文字
+	NextCursor string
+}
+
+type TodoSearchResult struct {
+	*Todo
+	Score     float64       `json:"score"`
+	Highlight TodoHighlight `json:"highlight"`
+}
+
+// 検索キーワードに一致した箇所を<em>タグで囲んだスニペット。一致箇所のない項目は空になる
+type TodoHighlight struct {
+	Title string `json:"title,omitempty"`
+	Memo  string `json:"memo,omitempty"`
+}
+
+type TodoHistory struct {
+	Action    string             `json:"action"`
+	Changes   []*TodoFieldChange `json:"changes"`
+	Actor     string             `json:"actor"`
+	RequestID string             `json:"requestID"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+type TodoFieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// BatchResult は一括操作の1件分の結果。Errがnilなら成功
+type BatchResult struct {
+	ID int
+	// 削除の結果は持たない
+	Todo *Todo
+	Err  error
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+)
+
+// todoPermission は認証したユーザーのプロジェクトごとの権限。
+// todoの変更はトランザクション中に権限を確認するため、トランザクションの開始前にまとめて取得しておく。
+type todoPermission struct {
+	roles map[projectdomain.ID]projectdomain.Role
+}
+
+func fetchTodoPermission(ctx context.Context, projectRepository projectdomain.Repository) (*todoPermission, error) {
+	userID, ok := userdomain.UserIDFromContext(ctx)
+	if !ok {
+		return nil, apperrors.Unauthorized
+	}
+
+	memberships, err := projectRepository.FetchProjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make(map[projectdomain.ID]projectdomain.Role, len(memberships))
+	for _, membership := range memberships {
+		roles[membership.Project.ID()] = membership.Role
+	}
+
+	return &todoPermission{roles: roles}, nil
+}
+
+// checkCreate はプロジェクトにtodoを作成できるかを確認する。個人のtodoは常に作成できる
+func (p *todoPermission) checkCreate(projectID projectdomain.ID) error {
+	if projectID == 0 {
+		return nil
+	}
+
+	role, ok := p.roles[projectID]
+	if !ok {
+		return apperrors.ProjectNotFound
+	}
+
+	if !role.CanEditTodos() {
+		return apperrors.Forbidden
+	}
+
+	return nil
+}
+
+// checkEdit はtodoを変更できるかを確認する。
+// 参照できないtodoはリポジトリで取得できないため、個人のtodoは常に変更できる。
+func (p *todoPermission) checkEdit(todoDm *tododomain.Todo) error {
+	if !todoDm.InProject() {
+		return nil
+	}
+
+	if !p.roles[todoDm.ProjectID()].CanEditTodos() {
+		return apperrors.Forbidden
+	}
+
+	return nil
+}
+
+// editableProjectIDs はtodoを変更できるプロジェクトのIDを返す
+func (p *todoPermission) editableProjectIDs() []projectdomain.ID {
+	var ids []projectdomain.ID
+	for id, role := range p.roles {
+		if role.CanEditTodos() {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+)
+
+func TestTodoPermission_CheckCreate(t *testing.T) {
+	permission := &todoPermission{roles: map[projectdomain.ID]projectdomain.Role{
+		1: projectdomain.Owner,
+		2: projectdomain.Editor,
+		3: projectdomain.Viewer,
+	}}
+
+	tests := []struct {
+		name      string
+		projectID projectdomain.ID
+		wantErr   error
+	}{
+		{name: "正常系: 個人のtodo", projectID: 0},
+		{name: "正常系: オーナー", projectID: 1},
+		{name: "正常系: 編集者", projectID: 2},
+		{name: "異常系: 閲覧者", projectID: 3, wantErr: apperrors.Forbidden},
+		{name: "異常系: 参加していないプロジェクト", projectID: 4, wantErr: apperrors.ProjectNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := permission.checkCreate(tt.projectID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTodoUsecase_ProjectPermission(t *testing.T) {
+	tests := []struct {
+		name          string
+		actor         userdomain.ID
+		wantCreateErr error
+		wantUpdateErr error
+	}{
+		{name: "正常系: オーナー", actor: 1},
+		{name: "正常系: 編集者", actor: 2},
+		{name: "異常系: 閲覧者", actor: 3, wantCreateErr: apperrors.Forbidden, wantUpdateErr: apperrors.Forbidden},
+		{name: "異常系: 参加していないユーザー", actor: 4, wantCreateErr: apperrors.ProjectNotFound, wantUpdateErr: apperrors.TodoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestTodoUsecase()
+			projectID := newTestProject(t, u.projectRepository)
+
+			in := newTodoInput("title")
+			in.ProjectID = projectID.Value()
+			created, err := u.CreateTodo(userContext(1), in, testAudit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = u.CreateTodo(userContext(tt.actor), in, testAudit)
+			if !errors.Is(err, tt.wantCreateErr) {
+				t.Errorf("CreateTodo() error = %v, wantErr %v", err, tt.wantCreateErr)
+			}
+
+			in = newTodoInput("changed")
+			in.ID = created.ID
+			in.StatusID = 1
+			_, err = u.UpdateTodo(userContext(tt.actor), in, testAudit)
+			if !errors.Is(err, tt.wantUpdateErr) {
+				t.Errorf("UpdateTodo() error = %v, wantErr %v", err, tt.wantUpdateErr)
+			}
+		})
+	}
+}
+
+func TestTodoUsecase_PurgeTodos_ProjectPermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		actor      userdomain.ID
+		wantPurged int
+	}{
+		{name: "正常系: オーナー", actor: 1, wantPurged: 1},
+		{name: "正常系: 編集者", actor: 2, wantPurged: 1},
+		{name: "異常系: 閲覧者", actor: 3},
+		{name: "異常系: 参加していないユーザー", actor: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestTodoUsecase()
+			u.trashRetention = 0
+			projectID := newTestProject(t, u.projectRepository)
+
+			in := newTodoInput("title")
+			in.ProjectID = projectID.Value()
+			created, err := u.CreateTodo(userContext(1), in, testAudit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = u.DeleteTodo(userContext(1), &input.TodoDelete{ID: created.ID}, testAudit); err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := u.PurgeTodos(userContext(tt.actor))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.Purged != tt.wantPurged {
+				t.Errorf("PurgeTodos() purged = %d, want %d", out.Purged, tt.wantPurged)
+			}
+
+			// 完全に削除していなければオーナーが復元できる
+			_, err = u.RestoreTodo(userContext(1), created.ID, testAudit)
+			if purged := errors.Is(err, apperrors.TodoNotFound); purged != (tt.wantPurged > 0) {
+				t.Errorf("RestoreTodo() error = %v, want purged %v", err, tt.wantPurged > 0)
+			}
+		})
+	}
+}
+
+// newTestProject はユーザーID 1がオーナー・2が編集者・3が閲覧者のプロジェクトを作成する
+func newTestProject(t *testing.T, repo projectdomain.Repository) projectdomain.ID {
+	t.Helper()
+
+	ctx := context.Background()
+
+	nameVo, err := projectdomain.NewName("project")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	projectID, err := repo.CreateProject(ctx, projectdomain.NewProjectWhenUnCreated(nameVo, time.Now()), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for userID, role := range map[userdomain.ID]projectdomain.Role{2: projectdomain.Editor, 3: projectdomain.Viewer} {
+		if err = repo.SaveMember(ctx, projectID, projectdomain.NewMember(userID, role)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return projectID
+}
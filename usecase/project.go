@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+	"github.com/kazumakawahara/todo-sample/usecase/output"
+)
+
+type ProjectUsecase interface {
+	CreateProject(ctx context.Context, in *input.Project) (*output.Project, error)
+	FetchProject(ctx context.Context, projectID int) (*output.Project, error)
+	FetchProjects(ctx context.Context) ([]*output.Project, error)
+	FetchMembers(ctx context.Context, projectID int) ([]*output.ProjectMember, error)
+	PutMember(ctx context.Context, in *input.ProjectMember) (*output.ProjectMember, error)
+	DeleteMember(ctx context.Context, projectID int, userID int) error
+}
+
+type projectUsecase struct {
+	projectRepository  projectdomain.Repository
+	transactionManager projectdomain.TransactionManager
+	userRepository     userdomain.Repository
+}
+
+// NewProjectUsecase はプロジェクトとメンバーを管理するユースケースを返す。
+// 参加していないプロジェクトは存在を明かさないよう apperrors.ProjectNotFound とする。
+func NewProjectUsecase(
+	projectRepository projectdomain.Repository,
+	transactionManager projectdomain.TransactionManager,
+	userRepository userdomain.Repository,
+) *projectUsecase {
+	return &projectUsecase{
+		projectRepository:  projectRepository,
+		transactionManager: transactionManager,
+		userRepository:     userRepository,
+	}
+}
+
+// CreateProject はプロジェクトを作成する。作成したユーザーがオーナーになる
+func (u *projectUsecase) CreateProject(ctx context.Context, in *input.Project) (*output.Project, error) {
+	userID, ok := userdomain.UserIDFromContext(ctx)
+	if !ok {
+		return nil, apperrors.Unauthorized
+	}
+
+	var fieldErrs apperrors.FieldErrors
+
+	nameVo, err := projectdomain.NewName(in.Name)
+	fieldErrs.Add("name", err)
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	projectDm := projectdomain.NewProjectWhenUnCreated(nameVo, time.Now().UTC().Truncate(time.Second))
+
+	idVo, err := u.projectRepository.CreateProject(ctx, projectDm, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectDm, err = u.projectRepository.FetchProjectByID(ctx, idVo)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProjectOutput(projectDm, projectdomain.Owner), nil
+}
+
+func (u *projectUsecase) FetchProject(ctx context.Context, projectID int) (*output.Project, error) {
+	idVo, role, err := fetchRole(ctx, u.projectRepository, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectDm, err := u.projectRepository.FetchProjectByID(ctx, idVo)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProjectOutput(projectDm, role), nil
+}
+
+// FetchProjects は認証したユーザーが参加しているプロジェクトを返す
+func (u *projectUsecase) FetchProjects(ctx context.Context) ([]*output.Project, error) {
+	userID, ok := userdomain.UserIDFromContext(ctx)
+	if !ok {
+		return nil, apperrors.Unauthorized
+	}
+
+	memberships, err := u.projectRepository.FetchProjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*output.Project, len(memberships))
+	for i, membership := range memberships {
+		out[i] = newProjectOutput(membership.Project, membership.Role)
+	}
+
+	return out, nil
+}
+
+// FetchMembers はプロジェクトのメンバーを返す。メンバーであれば権限によらず参照できる
+func (u *projectUsecase) FetchMembers(ctx context.Context, projectID int) ([]*output.ProjectMember, error) {
+	idVo, _, err := fetchRole(ctx, u.projectRepository, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberDms, err := u.projectRepository.FetchMembers(ctx, idVo)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*output.ProjectMember, len(memberDms))
+	for i, memberDm := range memberDms {
+		out[i] = newProjectMemberOutput(memberDm)
+	}
+
+	return out, nil
+}
+
+// PutMember はメンバーを追加する。既に参加していれば権限を変更する。オーナーのみ行える
+func (u *projectUsecase) PutMember(ctx context.Context, in *input.ProjectMember) (*output.ProjectMember, error) {
+	var fieldErrs apperrors.FieldErrors
+
+	userIDVo, err := userdomain.NewID(in.UserID)
+	fieldErrs.Add("userID", err)
+
+	roleVo, err := projectdomain.NewRole(in.Role)
+	fieldErrs.Add("role", err)
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	memberDm := projectdomain.NewMember(userIDVo, roleVo)
+
+	// 権限とメンバーの確認から変更までの間に、他のリクエストでオーナーがいなくならないようにする
+	err = u.transactionManager.Transaction(ctx, func(repo projectdomain.Repository) error {
+		idVo, role, err := fetchRole(ctx, repo, in.ProjectID)
+		if err != nil {
+			return err
+		}
+
+		if !role.CanManageMembers() {
+			return apperrors.Forbidden
+		}
+
+		if _, err = u.userRepository.FetchUserByID(ctx, userIDVo); err != nil {
+			return err
+		}
+
+		// オーナーが自分の権限を下げて、オーナーがいなくなるのを防ぐ
+		memberDms, err := repo.FetchMembers(ctx, idVo)
+		if err != nil {
+			return err
+		}
+		if err = projectdomain.EnsureOwnerRemains(replaceMember(memberDms, memberDm)); err != nil {
+			return err
+		}
+
+		return repo.SaveMember(ctx, idVo, memberDm)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newProjectMemberOutput(memberDm), nil
+}
+
+// DeleteMember はメンバーを削除する。オーナーは誰でも、それ以外は自分のみ削除できる
+func (u *projectUsecase) DeleteMember(ctx context.Context, projectID int, userID int) error {
+	userIDVo, err := userdomain.NewID(userID)
+	if err != nil {
+		var fieldErrs apperrors.FieldErrors
+		fieldErrs.Add("userID", err)
+		return fieldErrs.Err()
+	}
+
+	return u.transactionManager.Transaction(ctx, func(repo projectdomain.Repository) error {
+		idVo, role, err := fetchRole(ctx, repo, projectID)
+		if err != nil {
+			return err
+		}
+
+		if self, _ := userdomain.UserIDFromContext(ctx); userIDVo != self && !role.CanManageMembers() {
+			return apperrors.Forbidden
+		}
+
+		memberDms, err := repo.FetchMembers(ctx, idVo)
+		if err != nil {
+			return err
+		}
+		if err = projectdomain.EnsureOwnerRemains(replaceMember(memberDms, projectdomain.NewMember(userIDVo, ""))); err != nil {
+			return err
+		}
+
+		return repo.DeleteMember(ctx, idVo, userIDVo)
+	})
+}
+
+// fetchRole はプロジェクトIDを検証し、認証したユーザーの権限を返す
+func fetchRole(ctx context.Context, repo projectdomain.Repository, projectID int) (projectdomain.ID, projectdomain.Role, error) {
+	userID, ok := userdomain.UserIDFromContext(ctx)
+	if !ok {
+		return 0, "", apperrors.Unauthorized
+	}
+
+	idVo, err := projectdomain.NewID(projectID)
+	if err != nil {
+		var fieldErrs apperrors.FieldErrors
+		fieldErrs.Add("id", err)
+		return 0, "", fieldErrs.Err()
+	}
+
+	role, err := repo.FetchRole(ctx, idVo, userID)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return idVo, role, nil
+}
+
+// replaceMember は同じユーザーのメンバーを置き換えた、または追加したメンバーを返す
+func replaceMember(memberDms []*projectdomain.Member, memberDm *projectdomain.Member) []*projectdomain.Member {
+	replaced := make([]*projectdomain.Member, 0, len(memberDms)+1)
+	for _, m := range memberDms {
+		if m.UserID() != memberDm.UserID() {
+			replaced = append(replaced, m)
+		}
+	}
+
+	return append(replaced, memberDm)
+}
+
+func newProjectOutput(projectDm *projectdomain.Project, role projectdomain.Role) *output.Project {
+	return &output.Project{
+		ID:        projectDm.ID().Value(),
+		Name:      projectDm.Name().Value(),
+		Role:      role.Value(),
+		CreatedAt: projectDm.CreatedAt(),
+	}
+}
+
+func newProjectMemberOutput(memberDm *projectdomain.Member) *output.ProjectMember {
+	return &output.ProjectMember{
+		UserID: memberDm.UserID().Value(),
+		Role:   memberDm.Role().Value(),
+	}
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/persistence"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+)
+
+// newTestProjectUsecase はユーザーID 1〜4 のユーザーと、1がオーナー・2が編集者・3が閲覧者のプロジェクトを用意する。
+// 4はプロジェクトに参加していない。
+func newTestProjectUsecase(t *testing.T) (*projectUsecase, int) {
+	t.Helper()
+
+	todoRepo := persistence.NewTodoMemoryRepository()
+	userRepo := persistence.NewUserMemoryRepository()
+	for _, email := range []userdomain.Email{"a@example.com", "b@example.com", "c@example.com", "d@example.com"} {
+		if _, err := userRepo.CreateUser(context.Background(), userdomain.NewUserWhenUnCreated(email, "hash", time.Now())); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	u := NewProjectUsecase(
+		persistence.NewProjectMemoryRepository(todoRepo),
+		persistence.NewProjectMemoryTransactionManager(todoRepo),
+		userRepo,
+	)
+
+	project, err := u.CreateProject(userContext(1), &input.Project{Name: "project"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for userID, role := range map[int]projectdomain.Role{2: projectdomain.Editor, 3: projectdomain.Viewer} {
+		if _, err = u.PutMember(userContext(1), &input.ProjectMember{ProjectID: project.ID, UserID: userID, Role: role.Value()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return u, project.ID
+}
+
+func TestProjectUsecase_PutMember(t *testing.T) {
+	tests := []struct {
+		name     string
+		actor    userdomain.ID
+		userID   int
+		role     string
+		wantErr  error
+		wantRole projectdomain.Role
+	}{
+		{name: "正常系: オーナーがメンバーを追加", actor: 1, userID: 4, role: "viewer", wantRole: projectdomain.Viewer},
+		{name: "正常系: オーナーが権限を変更", actor: 1, userID: 3, role: "editor", wantRole: projectdomain.Editor},
+		{name: "異常系: 編集者はメンバーを管理できない", actor: 2, userID: 4, role: "viewer", wantErr: apperrors.Forbidden},
+		{name: "異常系: 閲覧者は自分の権限を上げられない", actor: 3, userID: 3, role: "owner", wantErr: apperrors.Forbidden},
+		{name: "異常系: 参加していないプロジェクト", actor: 4, userID: 4, role: "owner", wantErr: apperrors.ProjectNotFound},
+		{name: "異常系: 存在しないユーザー", actor: 1, userID: 100, role: "viewer", wantErr: apperrors.UserNotFound},
+		{name: "異常系: 唯一のオーナーが権限を下げる", actor: 1, userID: 1, role: "editor", wantErr: apperrors.ProjectOwnerRequired},
+		{name: "異常系: 不正な権限", actor: 1, userID: 4, role: "admin", wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, projectID := newTestProjectUsecase(t)
+
+			_, err := u.PutMember(userContext(tt.actor), &input.ProjectMember{ProjectID: projectID, UserID: tt.userID, Role: tt.role})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PutMember() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+			if got := memberRole(t, u, projectID, tt.userID); got != tt.wantRole {
+				t.Errorf("role = %q, want %q", got, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestProjectUsecase_DeleteMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   userdomain.ID
+		userID  int
+		wantErr error
+	}{
+		{name: "正常系: オーナーがメンバーを削除", actor: 1, userID: 2},
+		{name: "正常系: 閲覧者が自分を削除", actor: 3, userID: 3},
+		{name: "異常系: 編集者は他のメンバーを削除できない", actor: 2, userID: 3, wantErr: apperrors.Forbidden},
+		{name: "異常系: 参加していないプロジェクト", actor: 4, userID: 4, wantErr: apperrors.ProjectNotFound},
+		{name: "異常系: 参加していないユーザー", actor: 1, userID: 4, wantErr: apperrors.UserNotFound},
+		{name: "異常系: 唯一のオーナーが自分を削除", actor: 1, userID: 1, wantErr: apperrors.ProjectOwnerRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, projectID := newTestProjectUsecase(t)
+
+			err := u.DeleteMember(userContext(tt.actor), projectID, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteMember() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			got := memberRole(t, u, projectID, tt.userID)
+			if err == nil && got != "" {
+				t.Errorf("role = %q, want deleted", got)
+			}
+		})
+	}
+}
+
+// 複数のオーナーが同時に自分の権限を下げても、オーナーが1人は残る
+func TestProjectUsecase_PutMember_Concurrent(t *testing.T) {
+	u, projectID := newTestProjectUsecase(t)
+
+	owners := []int{1, 2, 3, 4}
+	for _, userID := range owners[1:] {
+		if _, err := u.PutMember(userContext(1), &input.ProjectMember{ProjectID: projectID, UserID: userID, Role: "owner"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errs := make([]error, len(owners))
+	var wg sync.WaitGroup
+	for i, userID := range owners {
+		wg.Add(1)
+		go func(i, userID int) {
+			defer wg.Done()
+			_, errs[i] = u.PutMember(userContext(userdomain.ID(userID)), &input.ProjectMember{ProjectID: projectID, UserID: userID, Role: "editor"})
+		}(i, userID)
+	}
+	wg.Wait()
+
+	var failed int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+		case errors.Is(err, apperrors.ProjectOwnerRequired):
+			failed++
+		default:
+			t.Fatalf("PutMember() error = %v", err)
+		}
+	}
+	if failed != 1 {
+		t.Errorf("failed = %d, want 1", failed)
+	}
+
+	var ownerCount int
+	for _, userID := range owners {
+		if memberRole(t, u, projectID, userID) == projectdomain.Owner {
+			ownerCount++
+		}
+	}
+	if ownerCount != 1 {
+		t.Errorf("owners = %d, want 1", ownerCount)
+	}
+}
+
+// memberRole はプロジェクトでのユーザーの権限を返す。参加していなければ空を返す
+func memberRole(t *testing.T, u *projectUsecase, projectID, userID int) projectdomain.Role {
+	t.Helper()
+
+	role, err := u.projectRepository.FetchRole(context.Background(), projectdomain.ID(projectID), userdomain.ID(userID))
+	if errors.Is(err, apperrors.ProjectNotFound) {
+		return ""
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return role
+}
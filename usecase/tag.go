@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+	"github.com/kazumakawahara/todo-sample/usecase/output"
+)
+
+type TagUsecase interface {
+	CreateTag(ctx context.Context, in *input.Tag) (*output.Tag, error)
+	FetchTag(ctx context.Context, id int) (*output.Tag, error)
+	FetchTags(ctx context.Context) ([]*output.Tag, error)
+	UpdateTag(ctx context.Context, in *input.Tag) (*output.Tag, error)
+	DeleteTag(ctx context.Context, id int) error
+}
+
+type tagUsecase struct {
+	tagRepository tododomain.TagRepository
+}
+
+// NewTagUsecase は認証したユーザーのタグを管理するユースケースを返す
+func NewTagUsecase(tagRepository tododomain.TagRepository) *tagUsecase {
+	return &tagUsecase{
+		tagRepository: tagRepository,
+	}
+}
+
+func (u *tagUsecase) CreateTag(ctx context.Context, in *input.Tag) (*output.Tag, error) {
+	var fieldErrs apperrors.FieldErrors
+
+	nameVo, err := tododomain.NewTagName(in.Name)
+	fieldErrs.Add("name", err)
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	idVo, err := u.tagRepository.CreateTag(ctx, tododomain.NewTagWhenUnCreated(nameVo))
+	if err != nil {
+		return nil, err
+	}
+
+	tagDm, err := u.tagRepository.FetchTagByID(ctx, idVo)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTagOutput(tagDm), nil
+}
+
+func (u *tagUsecase) FetchTag(ctx context.Context, id int) (*output.Tag, error) {
+	idVo, err := newTagIDVo(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tagDm, err := u.tagRepository.FetchTagByID(ctx, idVo)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTagOutput(tagDm), nil
+}
+
+func (u *tagUsecase) FetchTags(ctx context.Context) ([]*output.Tag, error) {
+	tagDms, err := u.tagRepository.FetchTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTagOutputs(tagDms), nil
+}
+
+// UpdateTag はタグの名前を変更する。タグを付けたtodoにもそのまま反映される
+func (u *tagUsecase) UpdateTag(ctx context.Context, in *input.Tag) (*output.Tag, error) {
+	var fieldErrs apperrors.FieldErrors
+
+	idVo, err := tododomain.NewTagID(in.ID)
+	fieldErrs.Add("id", err)
+
+	nameVo, err := tododomain.NewTagName(in.Name)
+	fieldErrs.Add("name", err)
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	tagDm, err := u.tagRepository.FetchTagByID(ctx, idVo)
+	if err != nil {
+		return nil, err
+	}
+
+	tagDm.Rename(nameVo)
+	if err = u.tagRepository.UpdateTag(ctx, tagDm); err != nil {
+		return nil, err
+	}
+
+	return newTagOutput(tagDm), nil
+}
+
+// DeleteTag はタグを削除する。タグを付けたtodoからも外れる
+func (u *tagUsecase) DeleteTag(ctx context.Context, id int) error {
+	idVo, err := newTagIDVo(id)
+	if err != nil {
+		return err
+	}
+
+	return u.tagRepository.DeleteTag(ctx, idVo)
+}
+
+func newTagIDVo(id int) (tododomain.TagID, error) {
+	idVo, err := tododomain.NewTagID(id)
+	if err != nil {
+		var fieldErrs apperrors.FieldErrors
+		fieldErrs.Add("id", err)
+		return 0, fieldErrs.Err()
+	}
+
+	return idVo, nil
+}
+
+func newTagOutput(tagDm *tododomain.Tag) *output.Tag {
+	return &output.Tag{
+		ID:   tagDm.ID().Value(),
+		Name: tagDm.Name().Value(),
+	}
+}
+
+func newTagOutputs(tagDms []*tododomain.Tag) []*output.Tag {
+	out := make([]*output.Tag, len(tagDms))
+	for i, tagDm := range tagDms {
+		out[i] = newTagOutput(tagDm)
+	}
+
+	return out
+}
+
+// tagCatalog はtodoに付けられる認証したユーザーのタグ。
+// todoの変更はトランザクション中にタグを確認するため、トランザクションの開始前にまとめて取得しておく。
+type tagCatalog struct {
+	tags map[tododomain.TagID]*tododomain.Tag
+}
+
+func fetchTagCatalog(ctx context.Context, tagRepository tododomain.TagRepository) (*tagCatalog, error) {
+	tagDms, err := tagRepository.FetchTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[tododomain.TagID]*tododomain.Tag, len(tagDms))
+	for _, tagDm := range tagDms {
+		tags[tagDm.ID()] = tagDm
+	}
+
+	return &tagCatalog{tags: tags}, nil
+}
+
+// resolve はタグのIDをタグに変換する。
+// プロジェクトのtodoには他のメンバーのタグも付いているため、todoに付いているタグは自分のタグでなくてもそのまま残せる。
+func (c *tagCatalog) resolve(todoDm *tododomain.Todo, ids []tododomain.TagID) ([]*tododomain.Tag, error) {
+	current := make(map[tododomain.TagID]*tododomain.Tag)
+	if todoDm != nil {
+		for _, tagDm := range todoDm.Tags() {
+			current[tagDm.ID()] = tagDm
+		}
+	}
+
+	tagDms := make([]*tododomain.Tag, len(ids))
+	for i, id := range ids {
+		tagDm, ok := current[id]
+		if !ok {
+			tagDm, ok = c.tags[id]
+		}
+		if !ok {
+			return nil, apperrors.InvalidReference
+		}
+
+		tagDms[i] = tagDm
+	}
+
+	return tagDms, nil
+}
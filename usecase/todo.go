@@ -1,179 +1,750 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/projectdomain"
 	"github.com/kazumakawahara/todo-sample/domain/tododomain"
 	"github.com/kazumakawahara/todo-sample/usecase/input"
 	"github.com/kazumakawahara/todo-sample/usecase/output"
 )
 
 type TodoUsecase interface {
-	CreateTodo(in *input.Todo) (*output.Todo, error)
-	FetchTodo(id int) (*output.Todo, error)
-	FetchTodos() ([]*output.Todo, error)
-	UpdateTodo(in *input.Todo) (*output.Todo, error)
-	DeleteTodo(id int) error
+	CreateTodo(ctx context.Context, in *input.Todo, audit *input.Audit) (*output.Todo, error)
+	FetchTodo(ctx context.Context, id int) (*output.Todo, error)
+	FetchTodos(ctx context.Context, in *input.TodoCriteria) (*output.TodoList, error)
+	FetchTrashedTodos(ctx context.Context, in *input.TodoCriteria) (*output.TodoList, error)
+	SearchTodos(ctx context.Context, in *input.TodoSearch) ([]*output.TodoSearchResult, error)
+	UpdateTodo(ctx context.Context, in *input.Todo, audit *input.Audit) (*output.Todo, error)
+	PatchTodo(ctx context.Context, in *input.TodoPatch, audit *input.Audit) (*output.Todo, error)
+	DeleteTodo(ctx context.Context, in *input.TodoDelete, audit *input.Audit) error
+	StartTodo(ctx context.Context, id int, audit *input.Audit) (*output.Todo, error)
+	CompleteTodo(ctx context.Context, id int, audit *input.Audit) (*output.Todo, error)
+	ReopenTodo(ctx context.Context, id int, audit *input.Audit) (*output.Todo, error)
+	RestoreTodo(ctx context.Context, id int, audit *input.Audit) (*output.Todo, error)
+	PurgeTodos(ctx context.Context) (*output.PurgeResult, error)
+	FetchTodoHistories(ctx context.Context, id int) ([]*output.TodoHistory, error)
+	BatchCreateTodos(ctx context.Context, ins []*input.Todo, audit *input.Audit) ([]*output.BatchResult, error)
+	BatchPatchTodos(ctx context.Context, ins []*input.TodoPatch, audit *input.Audit) ([]*output.BatchResult, error)
+	BatchDeleteTodos(ctx context.Context, ins []*input.TodoDelete, audit *input.Audit) ([]*output.BatchResult, error)
+	AddChecklistItem(ctx context.Context, in *input.ChecklistItem, audit *input.Audit) (*output.Todo, error)
+	ReorderChecklist(ctx context.Context, in *input.ChecklistOrder, audit *input.Audit) (*output.Todo, error)
+	CheckChecklistItem(ctx context.Context, in *input.ChecklistItemCheck, audit *input.Audit) (*output.Todo, error)
+	DeleteChecklistItem(ctx context.Context, in *input.ChecklistItemDelete, audit *input.Audit) (*output.Todo, error)
 }
 
 type todoUsecase struct {
-	todoRepository tododomain.Repository
+	todoRepository     tododomain.Repository
+	transactionManager tododomain.TransactionManager
+	projectRepository  projectdomain.Repository
+	tagRepository      tododomain.TagRepository
+	// ゴミ箱のtodoを完全に削除するまでの保持期間
+	trashRetention time.Duration
 }
 
-func NewTodoUsecase(todoRepository tododomain.Repository) *todoUsecase {
+// 変更を伴う操作は取得から変更履歴の記録・再取得までを transactionManager のトランザクションで行う。
+// プロジェクトのtodoの変更は projectRepository の権限で確認し、閲覧者は変更できない。
+// todoに付けられるのは tagRepository の自分のタグと、既にtodoに付いているタグのみ。
+func NewTodoUsecase(
+	todoRepository tododomain.Repository,
+	transactionManager tododomain.TransactionManager,
+	projectRepository projectdomain.Repository,
+	tagRepository tododomain.TagRepository,
+	trashRetention time.Duration,
+) *todoUsecase {
 	return &todoUsecase{
-		todoRepository: todoRepository,
+		todoRepository:     todoRepository,
+		transactionManager: transactionManager,
+		projectRepository:  projectRepository,
+		tagRepository:      tagRepository,
+		trashRetention:     trashRetention,
 	}
 }
 
-func (u *todoUsecase) CreateTodo(in *input.Todo) (*output.Todo, error) {
-	titleVo, err := tododomain.NewTitle(in.Title)
+func (u *todoUsecase) CreateTodo(ctx context.Context, in *input.Todo, audit *input.Audit) (*output.Todo, error) {
+	catalog, err := fetchTagCatalog(ctx, u.tagRepository)
 	if err != nil {
-		return nil, apperrors.InvalidParameter
+		return nil, err
 	}
 
-	implementationDateVo, err := tododomain.NewImplementationDate(in.ImplementationDate)
+	todoDm, err := newTodoDm(in, catalog)
 	if err != nil {
-		return nil, apperrors.InvalidParameter
+		return nil, err
 	}
 
-	dueDateVo, err := tododomain.NewDueDate(in.DueDate)
+	permission, err := fetchTodoPermission(ctx, u.projectRepository)
 	if err != nil {
-		return nil, apperrors.InvalidParameter
+		return nil, err
 	}
 
-	priorityVo, err := tododomain.NewPriority(in.PriorityID)
+	if err = permission.checkCreate(todoDm.ProjectID()); err != nil {
+		return nil, err
+	}
+
+	var out *output.Todo
+	err = u.transactionManager.Transaction(ctx, func(repo tododomain.Repository) error {
+		idVo, err := repo.CreateTodo(ctx, todoDm)
+		if err != nil {
+			return err
+		}
+
+		if err = repo.CreateHistory(ctx, newHistory(idVo, todoDm, tododomain.HistoryCreate, audit, time.Now())); err != nil {
+			return err
+		}
+
+		todoDm, err := repo.FetchTodoByID(ctx, idVo)
+		if err != nil {
+			return err
+		}
+
+		out = newTodoOutput(todoDm)
+		return nil
+	})
 	if err != nil {
-		return nil, apperrors.InvalidParameter
+		return nil, err
 	}
 
+	return out, nil
+}
+
+// newTodoDm は入力を値オブジェクトで検証し、作成前のtodoを生成する
+func newTodoDm(in *input.Todo, catalog *tagCatalog) (*tododomain.Todo, error) {
+	var fieldErrs apperrors.FieldErrors
+
+	titleVo, err := tododomain.NewTitle(in.Title)
+	fieldErrs.Add("title", err)
+
+	implementationDateVo, err := tododomain.NewImplementationDate(in.ImplementationDate)
+	fieldErrs.Add("implementationDate", err)
+
+	dueDateVo, err := tododomain.NewDueDate(in.DueDate)
+	fieldErrs.Add("dueDate", err)
+
+	priorityVo, err := tododomain.NewPriority(in.PriorityID)
+	fieldErrs.Add("priorityID", err)
+
 	memoVo, err := tododomain.NewMemo(in.Memo)
+	fieldErrs.Add("memo", err)
+
+	var projectIDVo projectdomain.ID
+	if in.ProjectID != 0 {
+		projectIDVo, err = projectdomain.NewID(in.ProjectID)
+		fieldErrs.Add("projectID", err)
+	}
+
+	tagIDVos, err := tododomain.NewTagIDs(in.TagIDs)
+	fieldErrs.Add("tagIDs", err)
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	tagDms, err := catalog.resolve(nil, tagIDVos)
 	if err != nil {
-		return nil, apperrors.InvalidParameter
+		return nil, err
 	}
 
-	todoDm := tododomain.NewTodoWhenUnCreated(
+	return tododomain.NewTodoWhenUnCreated(
 		titleVo,
 		implementationDateVo,
 		dueDateVo,
 		priorityVo,
 		memoVo,
+		projectIDVo,
+		tagDms,
 	)
+}
 
-	idVo, err := u.todoRepository.CreateTodo(todoDm)
+func (u *todoUsecase) FetchTodo(ctx context.Context, id int) (*output.Todo, error) {
+	idVo, err := newIDVo(id)
 	if err != nil {
 		return nil, err
 	}
 
-	todoDm, err = u.todoRepository.FetchTodoByID(idVo)
+	todoDm, err := u.todoRepository.FetchTodoByID(ctx, idVo)
 	if err != nil {
 		return nil, err
 	}
 
-	return &output.Todo{
-		ID:                 todoDm.ID().Value(),
-		Title:              todoDm.Title().Value(),
-		ImplementationDate: todoDm.ImplementationDate().Value(),
-		DueDate:            todoDm.DueDate().Value(),
-		StatusID:           todoDm.Status().Value(),
-		PriorityID:         todoDm.Priority().Value(),
-		Memo:               todoDm.Memo().Value(),
-	}, nil
+	return newTodoOutput(todoDm), nil
 }
 
-func (u *todoUsecase) FetchTodo(id int) (*output.Todo, error) {
-	idVo, err := tododomain.NewID(id)
-	if err != nil {
-		return nil, apperrors.InvalidParameter
+func (u *todoUsecase) FetchTodos(ctx context.Context, in *input.TodoCriteria) (*output.TodoList, error) {
+	return u.fetchTodos(ctx, in, false)
+}
+
+// FetchTrashedTodos はゴミ箱のtodoを一覧と同じ条件で取得する
+func (u *todoUsecase) FetchTrashedTodos(ctx context.Context, in *input.TodoCriteria) (*output.TodoList, error) {
+	return u.fetchTodos(ctx, in, true)
+}
+
+func (u *todoUsecase) fetchTodos(ctx context.Context, in *input.TodoCriteria, trashed bool) (*output.TodoList, error) {
+	var fieldErrs apperrors.FieldErrors
+
+	statusVos := make([]tododomain.Status, len(in.StatusIDs))
+	for i, statusID := range in.StatusIDs {
+		statusVo, err := tododomain.NewStatus(statusID)
+		fieldErrs.Add("status", err)
+		statusVos[i] = statusVo
+	}
+
+	priorityVos := make([]tododomain.Priority, len(in.PriorityIDs))
+	for i, priorityID := range in.PriorityIDs {
+		priorityVo, err := tododomain.NewPriority(priorityID)
+		fieldErrs.Add("priority", err)
+		priorityVos[i] = priorityVo
+	}
+
+	sortField, err := tododomain.NewSortField(in.Sort)
+	fieldErrs.Add("sort", err)
+
+	sortOrder, err := tododomain.NewSortOrder(in.Order)
+	fieldErrs.Add("order", err)
+
+	limit, err := tododomain.NewLimit(in.Limit)
+	fieldErrs.Add("limit", err)
+
+	var projectIDVo projectdomain.ID
+	if in.ProjectID != 0 {
+		projectIDVo, err = projectdomain.NewID(in.ProjectID)
+		fieldErrs.Add("project", err)
+	}
+
+	tagIDs := make([]int, len(in.TagIDs))
+	for i, tagID := range in.TagIDs {
+		tagIDs[i] = int(tagID)
+	}
+	tagIDVos, err := tododomain.NewTagIDs(tagIDs)
+	fieldErrs.Add("tag", err)
+
+	tagMatch, err := tododomain.NewTagMatch(in.TagMatch)
+	fieldErrs.Add("tagMatch", err)
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	var after *tododomain.Cursor
+	if in.Cursor != "" {
+		after, err = decodeCursor(in.Cursor, sortField, sortOrder)
+		if err != nil {
+			fieldErrs.Add("cursor", err)
+			return nil, fieldErrs.Err()
+		}
+	}
+
+	criteria := &tododomain.Criteria{
+		Statuses:    statusVos,
+		Priorities:  priorityVos,
+		DueDateFrom: in.DueDateFrom,
+		DueDateTo:   in.DueDateTo,
+		Title:       in.Title,
+		Memo:        in.Memo,
+		ProjectID:   projectIDVo,
+		TagIDs:      tagIDVos,
+		TagMatch:    tagMatch,
+		SortField:   sortField,
+		SortOrder:   sortOrder,
+		After:       after,
+		// 次のページの有無を判定するため1件多く取得する
+		Limit:   limit + 1,
+		Trashed: trashed,
 	}
 
-	todoDm, err := u.todoRepository.FetchTodoByID(idVo)
+	todosDm, err := u.todoRepository.FetchTodos(ctx, criteria)
 	if err != nil {
 		return nil, err
 	}
 
-	return &output.Todo{
-		ID:                 todoDm.ID().Value(),
-		Title:              todoDm.Title().Value(),
-		ImplementationDate: todoDm.ImplementationDate().Value(),
-		DueDate:            todoDm.DueDate().Value(),
-		StatusID:           todoDm.Status().Value(),
-		PriorityID:         todoDm.Priority().Value(),
-		Memo:               todoDm.Memo().Value(),
-	}, nil
+	out := &output.TodoList{}
+	if len(todosDm) > limit {
+		todosDm = todosDm[:limit]
+
+		out.NextCursor, err = encodeCursor(todosDm[limit-1], sortField, sortOrder)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out.Todos = make([]*output.Todo, len(todosDm))
+	for i, todoDm := range todosDm {
+		out.Todos[i] = newTodoOutput(todoDm)
+	}
+
+	return out, nil
 }
 
-func (u *todoUsecase) FetchTodos() ([]*output.Todo, error) {
-	todosDm, err := u.todoRepository.FetchTodos()
+func (u *todoUsecase) SearchTodos(ctx context.Context, in *input.TodoSearch) ([]*output.TodoSearchResult, error) {
+	var fieldErrs apperrors.FieldErrors
+
+	queryVo, err := tododomain.NewSearchQuery(in.Query)
+	fieldErrs.Add("q", err)
+
+	limit, err := tododomain.NewLimit(in.Limit)
+	fieldErrs.Add("limit", err)
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	results, err := u.todoRepository.SearchTodos(ctx, queryVo, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	todosDto := make([]*output.Todo, len(todosDm))
-	for i, todoDm := range todosDm {
-		todosDto[i] = &output.Todo{
-			ID:                 todoDm.ID().Value(),
-			Title:              todoDm.Title().Value(),
-			ImplementationDate: todoDm.ImplementationDate().Value(),
-			DueDate:            todoDm.DueDate().Value(),
-			StatusID:           todoDm.Status().Value(),
-			PriorityID:         todoDm.Priority().Value(),
-			Memo:               todoDm.Memo().Value(),
+	terms := queryVo.Terms()
+	out := make([]*output.TodoSearchResult, len(results))
+	for i, result := range results {
+		out[i] = &output.TodoSearchResult{
+			Todo:  newTodoOutput(result.Todo),
+			Score: result.Score,
+			Highlight: output.TodoHighlight{
+				Title: highlightResult(result.Todo.Title().Value(), terms, result.MatchedTerms, 0),
+				Memo:  highlightResult(result.Todo.Memo().Value(), terms, result.MatchedTerms, snippetLength),
+			},
 		}
 	}
 
-	return todosDto, nil
+	return out, nil
 }
 
-func (u *todoUsecase) UpdateTodo(in *input.Todo) (*output.Todo, error) {
+func (u *todoUsecase) UpdateTodo(ctx context.Context, in *input.Todo, audit *input.Audit) (*output.Todo, error) {
+	var fieldErrs apperrors.FieldErrors
+
 	idVo, err := tododomain.NewID(in.ID)
+	fieldErrs.Add("id", err)
+
+	titleVo, err := tododomain.NewTitle(in.Title)
+	fieldErrs.Add("title", err)
+
+	implementationDateVo, err := tododomain.NewImplementationDate(in.ImplementationDate)
+	fieldErrs.Add("implementationDate", err)
+
+	dueDateVo, err := tododomain.NewDueDate(in.DueDate)
+	fieldErrs.Add("dueDate", err)
+
+	statusVo, err := tododomain.NewStatus(in.StatusID)
+	fieldErrs.Add("statusID", err)
+
+	priorityVo, err := tododomain.NewPriority(in.PriorityID)
+	fieldErrs.Add("priorityID", err)
+
+	memoVo, err := tododomain.NewMemo(in.Memo)
+	fieldErrs.Add("memo", err)
+
+	tagIDVos, err := tododomain.NewTagIDs(in.TagIDs)
+	fieldErrs.Add("tagIDs", err)
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	permission, err := fetchTodoPermission(ctx, u.projectRepository)
 	if err != nil {
-		return nil, apperrors.InvalidParameter
+		return nil, err
 	}
 
-	titleVo, err := tododomain.NewTitle(in.Title)
+	catalog, err := fetchTagCatalog(ctx, u.tagRepository)
 	if err != nil {
-		return nil, apperrors.InvalidParameter
+		return nil, err
 	}
 
-	implementationDateVo, err := tododomain.NewImplementationDate(in.ImplementationDate)
+	// 所属するプロジェクトは作成後に変更できないため、入力のprojectIDは使わない
+	var out *output.Todo
+	err = u.transactionManager.Transaction(ctx, func(repo tododomain.Repository) error {
+		todoDm, err := repo.FetchTodoByID(ctx, idVo)
+		if err != nil {
+			return err
+		}
+
+		if err = permission.checkEdit(todoDm); err != nil {
+			return err
+		}
+
+		if err = checkVersion(todoDm, in.ExpectedVersions); err != nil {
+			return err
+		}
+
+		// タグの指定がなければ現在のタグのままにする
+		tagDms := todoDm.Tags()
+		if in.TagIDs != nil {
+			tagDms, err = catalog.resolve(todoDm, tagIDVos)
+			if err != nil {
+				return err
+			}
+		}
+
+		// 日付の変更は変更前のstatusで判定する
+		if err = todoDm.Reschedule(implementationDateVo, dueDateVo); err != nil {
+			return err
+		}
+		if err = todoDm.ChangeStatus(statusVo); err != nil {
+			return err
+		}
+		todoDm.ChangeTitle(titleVo)
+		todoDm.ChangePriority(priorityVo)
+		todoDm.ChangeMemo(memoVo)
+		todoDm.ChangeTags(tagDms)
+
+		out, err = saveTodo(ctx, repo, todoDm, tododomain.HistoryUpdate, audit)
+		return err
+	})
 	if err != nil {
-		return nil, apperrors.InvalidParameter
+		return nil, err
 	}
 
-	dueDateVo, err := tododomain.NewDueDate(in.DueDate)
+	return out, nil
+}
+
+func (u *todoUsecase) PatchTodo(ctx context.Context, in *input.TodoPatch, audit *input.Audit) (*output.Todo, error) {
+	idVo, err := newIDVo(in.ID)
 	if err != nil {
-		return nil, apperrors.InvalidParameter
+		return nil, err
 	}
 
-	statusVo, err := tododomain.NewStatus(in.StatusID)
+	permission, err := fetchTodoPermission(ctx, u.projectRepository)
 	if err != nil {
-		return nil, apperrors.InvalidParameter
+		return nil, err
 	}
 
-	priorityVo, err := tododomain.NewPriority(in.PriorityID)
+	catalog, err := fetchTagCatalog(ctx, u.tagRepository)
 	if err != nil {
-		return nil, apperrors.InvalidParameter
+		return nil, err
 	}
 
-	memoVo, err := tododomain.NewMemo(in.Memo)
+	var out *output.Todo
+	err = u.transactionManager.Transaction(ctx, func(repo tododomain.Repository) error {
+		out, err = patchTodo(ctx, repo, permission, catalog, idVo, in, audit)
+		return err
+	})
 	if err != nil {
-		return nil, apperrors.InvalidParameter
+		return nil, err
 	}
 
-	todoDm := tododomain.NewTodo(
-		idVo,
-		titleVo,
-		implementationDateVo,
-		dueDateVo,
-		statusVo,
-		priorityVo,
-		memoVo,
+	return out, nil
+}
+
+// patchTodo は指定された項目を現在の値に重ねて検証・変更する
+func patchTodo(
+	ctx context.Context,
+	repo tododomain.Repository,
+	permission *todoPermission,
+	catalog *tagCatalog,
+	idVo tododomain.ID,
+	in *input.TodoPatch,
+	audit *input.Audit,
+) (*output.Todo, error) {
+	todoDm, err := repo.FetchTodoByID(ctx, idVo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = permission.checkEdit(todoDm); err != nil {
+		return nil, err
+	}
+
+	if err = checkVersion(todoDm, in.ExpectedVersions); err != nil {
+		return nil, err
+	}
+
+	if err = applyPatch(todoDm, in, catalog); err != nil {
+		return nil, err
+	}
+
+	return saveTodo(ctx, repo, todoDm, tododomain.HistoryUpdate, audit)
+}
+
+// applyPatch は指定された項目を値オブジェクトで検証し、todoに反映する
+func applyPatch(todoDm *tododomain.Todo, in *input.TodoPatch, catalog *tagCatalog) error {
+	var (
+		fieldErrs apperrors.FieldErrors
+		err       error
 	)
 
-	if _, err = u.todoRepository.UpdateTodo(todoDm); err != nil {
+	// 削除できるのはメモ(空文字にする)とタグ(全て外す)のみ。それ以外は必須項目のためnullを指定できない。
+	for _, field := range in.NullFields {
+		switch field {
+		case "memo":
+			empty := ""
+			in.Memo = &empty
+			continue
+		case "tagIDs":
+			in.TagIDs = &[]int{}
+			continue
+		}
+
+		fieldErrs.Add(field, apperrors.NewValidationError("required", nil, "nullは指定できません"))
+	}
+
+	titleVo := todoDm.Title()
+	if in.Title != nil {
+		titleVo, err = tododomain.NewTitle(*in.Title)
+		fieldErrs.Add("title", err)
+	}
+
+	implementationDateVo := todoDm.ImplementationDate()
+	if in.ImplementationDate != nil {
+		implementationDateVo, err = tododomain.NewImplementationDate(*in.ImplementationDate)
+		fieldErrs.Add("implementationDate", err)
+	}
+
+	dueDateVo := todoDm.DueDate()
+	if in.DueDate != nil {
+		dueDateVo, err = tododomain.NewDueDate(*in.DueDate)
+		fieldErrs.Add("dueDate", err)
+	}
+
+	statusVo := todoDm.Status()
+	if in.StatusID != nil {
+		statusVo, err = tododomain.NewStatus(*in.StatusID)
+		fieldErrs.Add("statusID", err)
+	}
+
+	priorityVo := todoDm.Priority()
+	if in.PriorityID != nil {
+		priorityVo, err = tododomain.NewPriority(*in.PriorityID)
+		fieldErrs.Add("priorityID", err)
+	}
+
+	memoVo := todoDm.Memo()
+	if in.Memo != nil {
+		memoVo, err = tododomain.NewMemo(*in.Memo)
+		fieldErrs.Add("memo", err)
+	}
+
+	var tagIDVos []tododomain.TagID
+	if in.TagIDs != nil {
+		tagIDVos, err = tododomain.NewTagIDs(*in.TagIDs)
+		fieldErrs.Add("tagIDs", err)
+	}
+
+	if err = fieldErrs.Err(); err != nil {
+		return err
+	}
+
+	tagDms := todoDm.Tags()
+	if in.TagIDs != nil {
+		tagDms, err = catalog.resolve(todoDm, tagIDVos)
+		if err != nil {
+			return err
+		}
+	}
+
+	// 日付の変更は変更前のstatusで判定する
+	if err = todoDm.Reschedule(implementationDateVo, dueDateVo); err != nil {
+		return err
+	}
+	if err = todoDm.ChangeStatus(statusVo); err != nil {
+		return err
+	}
+	todoDm.ChangeTitle(titleVo)
+	todoDm.ChangePriority(priorityVo)
+	todoDm.ChangeMemo(memoVo)
+	todoDm.ChangeTags(tagDms)
+
+	return nil
+}
+
+func (u *todoUsecase) DeleteTodo(ctx context.Context, in *input.TodoDelete, audit *input.Audit) error {
+	idVo, err := newIDVo(in.ID)
+	if err != nil {
+		return err
+	}
+
+	permission, err := fetchTodoPermission(ctx, u.projectRepository)
+	if err != nil {
+		return err
+	}
+
+	err = u.transactionManager.Transaction(ctx, func(repo tododomain.Repository) error {
+		todoDm, err := repo.FetchTodoByID(ctx, idVo)
+		if err != nil {
+			return err
+		}
+
+		if err = permission.checkEdit(todoDm); err != nil {
+			return err
+		}
+
+		if err = checkVersion(todoDm, in.ExpectedVersions); err != nil {
+			return err
+		}
+
+		// 削除はゴミ箱への移動とし、復元できるようにする
+		now := time.Now()
+		todoDm.Trash(now)
+		_, err = updateTodo(ctx, repo, todoDm, tododomain.HistoryDelete, audit, now)
+		return err
+	})
+
+	// 冪等な削除では既に存在しない場合も成功とする。
+	// ただしIf-Matchが指定された場合は一致する表現がないため失敗とする。
+	if errors.Is(err, apperrors.TodoNotFound) && in.Idempotent {
+		if len(in.ExpectedVersions) > 0 {
+			return apperrors.PreconditionFailed
+		}
+
+		return nil
+	}
+
+	return err
+}
+
+func (u *todoUsecase) StartTodo(ctx context.Context, id int, audit *input.Audit) (*output.Todo, error) {
+	return u.transitTodo(ctx, id, audit, (*tododomain.Todo).Start)
+}
+
+func (u *todoUsecase) CompleteTodo(ctx context.Context, id int, audit *input.Audit) (*output.Todo, error) {
+	return u.transitTodo(ctx, id, audit, (*tododomain.Todo).Complete)
+}
+
+func (u *todoUsecase) ReopenTodo(ctx context.Context, id int, audit *input.Audit) (*output.Todo, error) {
+	return u.transitTodo(ctx, id, audit, (*tododomain.Todo).Reopen)
+}
+
+func (u *todoUsecase) transitTodo(ctx context.Context, id int, audit *input.Audit, transit func(*tododomain.Todo) error) (*output.Todo, error) {
+	idVo, err := newIDVo(id)
+	if err != nil {
 		return nil, err
 	}
 
+	permission, err := fetchTodoPermission(ctx, u.projectRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	var out *output.Todo
+	err = u.transactionManager.Transaction(ctx, func(repo tododomain.Repository) error {
+		todoDm, err := repo.FetchTodoByID(ctx, idVo)
+		if err != nil {
+			return err
+		}
+
+		if err = permission.checkEdit(todoDm); err != nil {
+			return err
+		}
+
+		if err = transit(todoDm); err != nil {
+			return err
+		}
+
+		out, err = saveTodo(ctx, repo, todoDm, tododomain.HistoryUpdate, audit)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (u *todoUsecase) RestoreTodo(ctx context.Context, id int, audit *input.Audit) (*output.Todo, error) {
+	idVo, err := newIDVo(id)
+	if err != nil {
+		return nil, err
+	}
+
+	permission, err := fetchTodoPermission(ctx, u.projectRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	var out *output.Todo
+	err = u.transactionManager.Transaction(ctx, func(repo tododomain.Repository) error {
+		todoDm, err := repo.FetchTrashedTodoByID(ctx, idVo)
+		if err != nil {
+			return err
+		}
+
+		if err = permission.checkEdit(todoDm); err != nil {
+			return err
+		}
+
+		todoDm.Restore()
+
+		out, err = saveTodo(ctx, repo, todoDm, tododomain.HistoryRestore, audit)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// PurgeTodos は保持期間を過ぎたゴミ箱のtodoを完全に削除する。プロジェクトのtodoは編集できる権限を持つもののみが対象になる。
+func (u *todoUsecase) PurgeTodos(ctx context.Context) (*output.PurgeResult, error) {
+	permission, err := fetchTodoPermission(ctx, u.projectRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := u.todoRepository.PurgeTodos(ctx, time.Now().Add(-u.trashRetention), permission.editableProjectIDs())
+	if err != nil {
+		return nil, err
+	}
+
+	return &output.PurgeResult{Purged: count}, nil
+}
+
+// FetchTodoHistories はtodoの変更履歴を古い順に返す。ゴミ箱のtodoや完全に削除したtodoの履歴も返す。
+func (u *todoUsecase) FetchTodoHistories(ctx context.Context, id int) ([]*output.TodoHistory, error) {
+	idVo, err := newIDVo(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 履歴の有無ではなくtodo自体 (ゴミ箱を含む) の存在を確認する
+	if _, err = u.todoRepository.FetchTodoByID(ctx, idVo); errors.Is(err, apperrors.TodoNotFound) {
+		_, err = u.todoRepository.FetchTrashedTodoByID(ctx, idVo)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := u.todoRepository.FetchHistories(ctx, idVo)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*output.TodoHistory, len(histories))
+	for i, history := range histories {
+		changes := make([]*output.TodoFieldChange, len(history.Changes()))
+		for j, change := range history.Changes() {
+			changes[j] = &output.TodoFieldChange{
+				Field:  change.Field,
+				Before: change.Before,
+				After:  change.After,
+			}
+		}
+
+		out[i] = &output.TodoHistory{
+			Action:    history.Action().Value(),
+			Changes:   changes,
+			Actor:     history.Actor(),
+			RequestID: history.RequestID(),
+			CreatedAt: history.CreatedAt(),
+		}
+	}
+
+	return out, nil
+}
+
+func newIDVo(id int) (tododomain.ID, error) {
+	idVo, err := tododomain.NewID(id)
+	if err != nil {
+		var fieldErrs apperrors.FieldErrors
+		fieldErrs.Add("id", err)
+		return 0, fieldErrs.Err()
+	}
+
+	return idVo, nil
+}
+
+func newTodoOutput(todoDm *tododomain.Todo) *output.Todo {
 	return &output.Todo{
 		ID:                 todoDm.ID().Value(),
 		Title:              todoDm.Title().Value(),
@@ -182,18 +753,86 @@ func (u *todoUsecase) UpdateTodo(in *input.Todo) (*output.Todo, error) {
 		StatusID:           todoDm.Status().Value(),
 		PriorityID:         todoDm.Priority().Value(),
 		Memo:               todoDm.Memo().Value(),
-	}, nil
+		Version:            todoDm.Version().Value(),
+		DeletedAt:          todoDm.DeletedAt(),
+		ProjectID:          todoDm.ProjectID().Value(),
+		Tags:               newTagOutputs(todoDm.Tags()),
+		Checklist:          newChecklistOutputs(todoDm.Checklist()),
+		Progress:           todoDm.Progress(),
+	}
 }
 
-func (u *todoUsecase) DeleteTodo(id int) error {
-	idVo, err := tododomain.NewID(id)
+// saveTodo は変更があればtodoを更新して変更履歴を記録し、永続化された値を返す
+func saveTodo(ctx context.Context, repo tododomain.Repository, todoDm *tododomain.Todo, action tododomain.HistoryAction, audit *input.Audit) (*output.Todo, error) {
+	// 変更がなければ更新しない(バージョンも変わらない)
+	if !todoDm.IsChanged() {
+		return newTodoOutput(todoDm), nil
+	}
+
+	idVo, err := updateTodo(ctx, repo, todoDm, action, audit, time.Now())
 	if err != nil {
-		return apperrors.InvalidParameter
+		return nil, err
 	}
 
-	if err = u.todoRepository.DeleteTodo(idVo); err != nil {
-		return err
+	// 永続化された値を返すため再取得する
+	todoDm, err = repo.FetchTodoByID(ctx, idVo)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return newTodoOutput(todoDm), nil
+}
+
+// updateTodo はtodoを更新し、同じトランザクションで変更履歴を記録する
+func updateTodo(
+	ctx context.Context,
+	repo tododomain.Repository,
+	todoDm *tododomain.Todo,
+	action tododomain.HistoryAction,
+	audit *input.Audit,
+	now time.Time,
+) (tododomain.ID, error) {
+	idVo, err := repo.UpdateTodo(ctx, todoDm)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = repo.CreateHistory(ctx, newHistory(idVo, todoDm, action, audit, now)); err != nil {
+		return 0, err
+	}
+
+	return idVo, nil
+}
+
+// newHistory はtodoの生成・取得後の変更内容から変更履歴を生成する
+func newHistory(
+	idVo tododomain.ID,
+	todoDm *tododomain.Todo,
+	action tododomain.HistoryAction,
+	audit *input.Audit,
+	now time.Time,
+) *tododomain.History {
+	return tododomain.NewHistory(
+		idVo,
+		action,
+		todoDm.Changes(),
+		audit.Actor,
+		audit.RequestID,
+		now,
+	)
+}
+
+// checkVersion はIf-Matchで指定されたバージョンのいずれかと一致するかを検証する
+func checkVersion(todoDm *tododomain.Todo, expectedVersions []uint) error {
+	if len(expectedVersions) == 0 {
+		return nil
+	}
+
+	for _, version := range expectedVersions {
+		if todoDm.Version().Value() == version {
+			return nil
+		}
+	}
+
+	return apperrors.PreconditionFailed
 }
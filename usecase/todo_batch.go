@@ -0,0 +1,379 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+	"github.com/kazumakawahara/todo-sample/usecase/output"
+)
+
+// 一括操作で一度に指定できる件数の上限
+const batchMaxSize = 500
+
+// 一括操作は1件ずつ値オブジェクトで検証し、検証を通ったものを1つのトランザクションでまとめて書き込む。
+// 1件ごとの失敗は結果のErrに返し、DBのエラーなど全体の失敗のみをエラーとして返す。
+
+// BatchCreateTodos は複数のtodoを作成する
+func (u *todoUsecase) BatchCreateTodos(ctx context.Context, ins []*input.Todo, audit *input.Audit) ([]*output.BatchResult, error) {
+	if err := validateBatchSize(len(ins)); err != nil {
+		return nil, err
+	}
+
+	permission, err := fetchTodoPermission(ctx, u.projectRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := fetchTagCatalog(ctx, u.tagRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*output.BatchResult, len(ins))
+	var (
+		todoDms []*tododomain.Todo
+		indexes []int
+	)
+	for i, in := range ins {
+		todoDm, err := newTodoDm(in, catalog)
+		if err == nil {
+			err = permission.checkCreate(todoDm.ProjectID())
+		}
+		if err != nil {
+			results[i] = &output.BatchResult{Err: err}
+			continue
+		}
+
+		todoDms = append(todoDms, todoDm)
+		indexes = append(indexes, i)
+	}
+	if len(todoDms) == 0 {
+		return results, nil
+	}
+
+	err = u.transactionManager.Transaction(ctx, func(repo tododomain.Repository) error {
+		idVos, err := repo.CreateTodos(ctx, todoDms)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		histories := make([]*tododomain.History, len(todoDms))
+		for j, todoDm := range todoDms {
+			histories[j] = newHistory(idVos[j], todoDm, tododomain.HistoryCreate, audit, now)
+		}
+		if err = repo.CreateHistories(ctx, histories); err != nil {
+			return err
+		}
+
+		created, err := fetchTodosByIDs(ctx, repo, idVos)
+		if err != nil {
+			return err
+		}
+
+		for j, idVo := range idVos {
+			todoDm, ok := created[idVo]
+			if !ok {
+				return apperrors.InternalServerError
+			}
+
+			results[indexes[j]] = &output.BatchResult{ID: idVo.Value(), Todo: newTodoOutput(todoDm)}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// BatchPatchTodos は複数のtodoにJSON Merge Patchを適用する
+func (u *todoUsecase) BatchPatchTodos(ctx context.Context, ins []*input.TodoPatch, audit *input.Audit) ([]*output.BatchResult, error) {
+	if err := validateBatchSize(len(ins)); err != nil {
+		return nil, err
+	}
+
+	results, idVos, indexes := newBatchTargets(len(ins), func(i int) int { return ins[i].ID })
+	if len(indexes) == 0 {
+		return results, nil
+	}
+
+	permission, err := fetchTodoPermission(ctx, u.projectRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := fetchTagCatalog(ctx, u.tagRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.transactionManager.Transaction(ctx, func(repo tododomain.Repository) error {
+		current, err := fetchTodosByIDs(ctx, repo, batchIDs(idVos, indexes))
+		if err != nil {
+			return err
+		}
+
+		var (
+			changed   []*tododomain.Todo
+			succeeded []tododomain.ID
+		)
+		for _, i := range indexes {
+			todoDm, ok := current[idVos[i]]
+			if !ok {
+				results[i] = &output.BatchResult{ID: ins[i].ID, Err: apperrors.TodoNotFound}
+				continue
+			}
+
+			if err = permission.checkEdit(todoDm); err == nil {
+				err = checkVersion(todoDm, ins[i].ExpectedVersions)
+			}
+			if err == nil {
+				err = applyPatch(todoDm, ins[i], catalog)
+			}
+			if err != nil {
+				results[i] = &output.BatchResult{ID: ins[i].ID, Err: err}
+				continue
+			}
+
+			// 変更がなければ更新しない(バージョンも変わらない)
+			if todoDm.IsChanged() {
+				changed = append(changed, todoDm)
+			}
+			succeeded = append(succeeded, idVos[i])
+		}
+
+		stale, err := updateTodos(ctx, repo, changed, tododomain.HistoryUpdate, audit, time.Now())
+		if err != nil {
+			return err
+		}
+		setStaleResults(results, idVos, indexes, stale, func(i int) int { return ins[i].ID })
+
+		// 永続化された値を返すため再取得する
+		updated, err := fetchTodosByIDs(ctx, repo, succeeded)
+		if err != nil {
+			return err
+		}
+
+		for _, i := range indexes {
+			if results[i] != nil {
+				continue
+			}
+
+			todoDm, ok := updated[idVos[i]]
+			if !ok {
+				return apperrors.InternalServerError
+			}
+
+			results[i] = &output.BatchResult{ID: ins[i].ID, Todo: newTodoOutput(todoDm)}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// BatchDeleteTodos は複数のtodoをゴミ箱に移動する
+func (u *todoUsecase) BatchDeleteTodos(ctx context.Context, ins []*input.TodoDelete, audit *input.Audit) ([]*output.BatchResult, error) {
+	if err := validateBatchSize(len(ins)); err != nil {
+		return nil, err
+	}
+
+	results, idVos, indexes := newBatchTargets(len(ins), func(i int) int { return ins[i].ID })
+	if len(indexes) == 0 {
+		return results, nil
+	}
+
+	permission, err := fetchTodoPermission(ctx, u.projectRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.transactionManager.Transaction(ctx, func(repo tododomain.Repository) error {
+		current, err := fetchTodosByIDs(ctx, repo, batchIDs(idVos, indexes))
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var trashed []*tododomain.Todo
+		for _, i := range indexes {
+			todoDm, ok := current[idVos[i]]
+			if !ok {
+				results[i] = &output.BatchResult{ID: ins[i].ID, Err: notFoundOnDelete(ins[i])}
+				continue
+			}
+
+			if err = permission.checkEdit(todoDm); err == nil {
+				err = checkVersion(todoDm, ins[i].ExpectedVersions)
+			}
+			if err != nil {
+				results[i] = &output.BatchResult{ID: ins[i].ID, Err: err}
+				continue
+			}
+
+			// 削除はゴミ箱への移動とし、復元できるようにする
+			todoDm.Trash(now)
+			trashed = append(trashed, todoDm)
+		}
+
+		stale, err := updateTodos(ctx, repo, trashed, tododomain.HistoryDelete, audit, now)
+		if err != nil {
+			return err
+		}
+		setStaleResults(results, idVos, indexes, stale, func(i int) int { return ins[i].ID })
+
+		for _, i := range indexes {
+			if results[i] == nil {
+				results[i] = &output.BatchResult{ID: ins[i].ID}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// notFoundOnDelete は削除対象が存在しない場合の結果を返す。冪等な削除では成功とする。
+func notFoundOnDelete(in *input.TodoDelete) error {
+	if !in.Idempotent {
+		return apperrors.TodoNotFound
+	}
+
+	if len(in.ExpectedVersions) > 0 {
+		return apperrors.PreconditionFailed
+	}
+
+	return nil
+}
+
+func validateBatchSize(n int) error {
+	if n < 1 || n > batchMaxSize {
+		var fieldErrs apperrors.FieldErrors
+		fieldErrs.Add("items", apperrors.NewValidationError("range", n, fmt.Sprintf("一度に指定できるのは1〜%d件です", batchMaxSize)))
+		return fieldErrs.Err()
+	}
+
+	return nil
+}
+
+// newBatchTargets はIDを検証し、不正なIDと重複したIDの結果を埋めた結果と、対象のIDと添字を返す
+func newBatchTargets(n int, id func(i int) int) ([]*output.BatchResult, []tododomain.ID, []int) {
+	results := make([]*output.BatchResult, n)
+	idVos := make([]tododomain.ID, n)
+	seen := make(map[tododomain.ID]bool, n)
+
+	var indexes []int
+	for i := 0; i < n; i++ {
+		idVo, err := newIDVo(id(i))
+		if err != nil {
+			results[i] = &output.BatchResult{ID: id(i), Err: err}
+			continue
+		}
+
+		// 同じtodoへの操作は1文の更新にまとめられないため最初の1件のみを対象とする
+		if seen[idVo] {
+			var fieldErrs apperrors.FieldErrors
+			fieldErrs.Add("id", apperrors.NewValidationError("unique", id(i), "同じIDが複数指定されています"))
+			results[i] = &output.BatchResult{ID: id(i), Err: fieldErrs.Err()}
+			continue
+		}
+		seen[idVo] = true
+
+		idVos[i] = idVo
+		indexes = append(indexes, i)
+	}
+
+	return results, idVos, indexes
+}
+
+func batchIDs(idVos []tododomain.ID, indexes []int) []tododomain.ID {
+	ids := make([]tododomain.ID, len(indexes))
+	for j, i := range indexes {
+		ids[j] = idVos[i]
+	}
+
+	return ids
+}
+
+func fetchTodosByIDs(ctx context.Context, repo tododomain.Repository, idVos []tododomain.ID) (map[tododomain.ID]*tododomain.Todo, error) {
+	todos := make(map[tododomain.ID]*tododomain.Todo, len(idVos))
+	if len(idVos) == 0 {
+		return todos, nil
+	}
+
+	todoDms, err := repo.FetchTodosByIDs(ctx, idVos)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, todoDm := range todoDms {
+		todos[todoDm.ID()] = todoDm
+	}
+
+	return todos, nil
+}
+
+// updateTodos は複数のtodoをまとめて更新し、同じトランザクションで変更履歴を記録する。
+// 取得後に他の更新が入り更新しなかったtodoは変更履歴を記録せず、そのIDを返す。
+func updateTodos(
+	ctx context.Context,
+	repo tododomain.Repository,
+	todoDms []*tododomain.Todo,
+	action tododomain.HistoryAction,
+	audit *input.Audit,
+	now time.Time,
+) (map[tododomain.ID]bool, error) {
+	if len(todoDms) == 0 {
+		return nil, nil
+	}
+
+	staleIDs, err := repo.UpdateTodos(ctx, todoDms)
+	if err != nil {
+		return nil, err
+	}
+
+	stale := make(map[tododomain.ID]bool, len(staleIDs))
+	for _, id := range staleIDs {
+		stale[id] = true
+	}
+
+	var histories []*tododomain.History
+	for _, todoDm := range todoDms {
+		if !stale[todoDm.ID()] {
+			histories = append(histories, newHistory(todoDm.ID(), todoDm, action, audit, now))
+		}
+	}
+	if len(histories) == 0 {
+		return stale, nil
+	}
+
+	if err = repo.CreateHistories(ctx, histories); err != nil {
+		return nil, err
+	}
+
+	return stale, nil
+}
+
+// setStaleResults は取得後に他の更新が入り更新しなかったtodoの結果をPreconditionFailedにする
+func setStaleResults(results []*output.BatchResult, idVos []tododomain.ID, indexes []int, stale map[tododomain.ID]bool, id func(i int) int) {
+	for _, i := range indexes {
+		if results[i] == nil && stale[idVos[i]] {
+			results[i] = &output.BatchResult{ID: id(i), Err: apperrors.PreconditionFailed}
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+	"github.com/kazumakawahara/todo-sample/usecase/output"
+)
+
+func TestTodoUsecase_BatchCreateTodos_Size(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		wantErr error
+	}{
+		{name: "正常系: 1件", n: 1},
+		{name: "正常系: 上限", n: batchMaxSize},
+		{name: "異常系: 0件", n: 0, wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 上限を超える", n: batchMaxSize + 1, wantErr: apperrors.InvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestTodoUsecase()
+
+			ins := make([]*input.Todo, tt.n)
+			for i := range ins {
+				ins[i] = newTodoInput("title")
+			}
+
+			results, err := u.BatchCreateTodos(userContext(1), ins, testAudit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BatchCreateTodos() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(results) != tt.n {
+				t.Errorf("len(results) = %d, want %d", len(results), tt.n)
+			}
+		})
+	}
+}
+
+func TestTodoUsecase_BatchCreateTodos(t *testing.T) {
+	u := newTestTodoUsecase()
+	ctx := userContext(1)
+
+	results, err := u.BatchCreateTodos(ctx, []*input.Todo{
+		newTodoInput("first"),
+		newTodoInput(""),
+		newTodoInput("third"),
+	}, testAudit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 検証に失敗したものだけが失敗し、他は作成される
+	wantErrs := []error{nil, apperrors.InvalidParameter, nil}
+	for i, result := range results {
+		if !errors.Is(result.Err, wantErrs[i]) {
+			t.Errorf("results[%d].Err = %v, want %v", i, result.Err, wantErrs[i])
+		}
+		if result.Err != nil {
+			continue
+		}
+
+		got, err := u.FetchTodo(ctx, result.ID)
+		if err != nil {
+			t.Fatalf("FetchTodo(%d) error = %v", result.ID, err)
+		}
+		if got.Title != result.Todo.Title {
+			t.Errorf("FetchTodo(%d).Title = %q, want %q", result.ID, got.Title, result.Todo.Title)
+		}
+	}
+}
+
+func TestTodoUsecase_BatchPatchTodos(t *testing.T) {
+	u := newTestTodoUsecase()
+	ctx := userContext(1)
+
+	ids := createTestTodos(t, u, 3)
+	title := "changed"
+	empty := ""
+
+	results, err := u.BatchPatchTodos(ctx, []*input.TodoPatch{
+		{ID: ids[0], Title: &title},
+		{ID: ids[1], Title: &title, ExpectedVersions: []uint{2}},
+		{ID: ids[2], Title: &empty},
+		{ID: ids[0], Title: &title},
+		{ID: 100, Title: &title},
+		{ID: 0, Title: &title},
+	}, testAudit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		wantErr   error
+		wantTitle string
+	}{
+		{name: "正常系", wantTitle: "changed"},
+		{name: "異常系: 一致しないバージョン", wantErr: apperrors.PreconditionFailed, wantTitle: "title"},
+		{name: "異常系: 不正なタイトル", wantErr: apperrors.InvalidParameter, wantTitle: "title"},
+		{name: "異常系: 重複したID", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 存在しないtodo", wantErr: apperrors.TodoNotFound},
+		{name: "異常系: 不正なID", wantErr: apperrors.InvalidParameter},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(results[i].Err, tt.wantErr) {
+				t.Fatalf("results[%d].Err = %v, wantErr %v", i, results[i].Err, tt.wantErr)
+			}
+			if tt.wantTitle == "" {
+				return
+			}
+
+			got, err := u.FetchTodo(ctx, results[i].ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Title != tt.wantTitle {
+				t.Errorf("FetchTodo(%d).Title = %q, want %q", results[i].ID, got.Title, tt.wantTitle)
+			}
+		})
+	}
+}
+
+// staleTransactionManager は取得後に他の更新が入ったものとして、staleIDのtodoを更新しないリポジトリを渡す
+type staleTransactionManager struct {
+	tododomain.TransactionManager
+	staleID tododomain.ID
+}
+
+func (m *staleTransactionManager) Transaction(ctx context.Context, fn func(repo tododomain.Repository) error) error {
+	return m.TransactionManager.Transaction(ctx, func(repo tododomain.Repository) error {
+		return fn(&staleTodoRepository{Repository: repo, staleID: m.staleID})
+	})
+}
+
+type staleTodoRepository struct {
+	tododomain.Repository
+	staleID tododomain.ID
+}
+
+func (r *staleTodoRepository) UpdateTodos(ctx context.Context, todos []*tododomain.Todo) ([]tododomain.ID, error) {
+	var (
+		stale []tododomain.ID
+		fresh []*tododomain.Todo
+	)
+	for _, todo := range todos {
+		if todo.ID() == r.staleID {
+			stale = append(stale, todo.ID())
+			continue
+		}
+		fresh = append(fresh, todo)
+	}
+
+	staleIDs, err := r.Repository.UpdateTodos(ctx, fresh)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(stale, staleIDs...), nil
+}
+
+func TestTodoUsecase_BatchTodos_Stale(t *testing.T) {
+	title := "changed"
+	tests := []struct {
+		name  string
+		batch func(u *todoUsecase, ids []int) ([]*output.BatchResult, error)
+	}{
+		{name: "BatchPatchTodos", batch: func(u *todoUsecase, ids []int) ([]*output.BatchResult, error) {
+			return u.BatchPatchTodos(userContext(1), []*input.TodoPatch{{ID: ids[0], Title: &title}, {ID: ids[1], Title: &title}}, testAudit)
+		}},
+		{name: "BatchDeleteTodos", batch: func(u *todoUsecase, ids []int) ([]*output.BatchResult, error) {
+			return u.BatchDeleteTodos(userContext(1), []*input.TodoDelete{{ID: ids[0]}, {ID: ids[1]}}, testAudit)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestTodoUsecase()
+			ids := createTestTodos(t, u, 2)
+			u.transactionManager = &staleTransactionManager{TransactionManager: u.transactionManager, staleID: tododomain.ID(ids[1])}
+
+			// 取得後に他の更新が入ったtodoのみ失敗し、他は更新する
+			results, err := tt.batch(u, ids)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if results[0].Err != nil {
+				t.Errorf("results[0].Err = %v, want nil", results[0].Err)
+			}
+			if !errors.Is(results[1].Err, apperrors.PreconditionFailed) {
+				t.Errorf("results[1].Err = %v, wantErr %v", results[1].Err, apperrors.PreconditionFailed)
+			}
+
+			// 更新しなかったtodoには変更履歴を残さない
+			for i, want := range []int{2, 1} {
+				histories, err := u.FetchTodoHistories(userContext(1), ids[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(histories) != want {
+					t.Errorf("len(FetchTodoHistories(%d)) = %d, want %d", ids[i], len(histories), want)
+				}
+			}
+		})
+	}
+}
+
+func TestTodoUsecase_BatchDeleteTodos(t *testing.T) {
+	u := newTestTodoUsecase()
+	ctx := userContext(1)
+
+	ids := createTestTodos(t, u, 3)
+
+	results, err := u.BatchDeleteTodos(ctx, []*input.TodoDelete{
+		{ID: ids[0]},
+		{ID: ids[1], ExpectedVersions: []uint{2}},
+		{ID: ids[2], ExpectedVersions: []uint{1}},
+		{ID: ids[0]},
+		{ID: 100},
+		{ID: 101, Idempotent: true},
+		{ID: 102, Idempotent: true, ExpectedVersions: []uint{1}},
+	}, testAudit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		wantErr     error
+		existing    bool
+		wantTrashed bool
+	}{
+		{name: "正常系", existing: true, wantTrashed: true},
+		{name: "異常系: 一致しないバージョン", wantErr: apperrors.PreconditionFailed, existing: true},
+		{name: "正常系: 一致するバージョン", existing: true, wantTrashed: true},
+		{name: "異常系: 重複したID", wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 存在しないtodo", wantErr: apperrors.TodoNotFound},
+		{name: "正常系: 冪等な削除で存在しないtodo"},
+		{name: "異常系: 冪等な削除で存在しないtodoのバージョンを指定", wantErr: apperrors.PreconditionFailed},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(results[i].Err, tt.wantErr) {
+				t.Fatalf("results[%d].Err = %v, wantErr %v", i, results[i].Err, tt.wantErr)
+			}
+			if !tt.existing {
+				return
+			}
+
+			_, err := u.FetchTodo(ctx, results[i].ID)
+			if trashed := errors.Is(err, apperrors.TodoNotFound); trashed != tt.wantTrashed {
+				t.Errorf("FetchTodo(%d) error = %v, want trashed %v", results[i].ID, err, tt.wantTrashed)
+			}
+		})
+	}
+}
+
+func createTestTodos(t *testing.T, u *todoUsecase, n int) []int {
+	t.Helper()
+
+	ids := make([]int, n)
+	for i := range ids {
+		created, err := u.CreateTodo(userContext(1), newTodoInput("title"), testAudit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = created.ID
+	}
+
+	return ids
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+	"github.com/kazumakawahara/todo-sample/usecase/output"
+)
+
+// チェックリストはtodo集約の一部のため、項目の変更もtodoの更新としてバージョンを上げ、変更履歴に記録する。
+
+// AddChecklistItem はtodoのチェックリストの末尾に項目を追加する
+func (u *todoUsecase) AddChecklistItem(ctx context.Context, in *input.ChecklistItem, audit *input.Audit) (*output.Todo, error) {
+	var fieldErrs apperrors.FieldErrors
+
+	idVo, err := tododomain.NewID(in.TodoID)
+	fieldErrs.Add("id", err)
+
+	titleVo, err := tododomain.NewChecklistItemTitle(in.Title)
+	fieldErrs.Add("title", err)
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	return u.changeChecklist(ctx, idVo, in.ExpectedVersions, audit, func(todoDm *tododomain.Todo) error {
+		return todoDm.AddChecklistItem(titleVo)
+	})
+}
+
+// ReorderChecklist はtodoのチェックリストを指定した順に並べ替える
+func (u *todoUsecase) ReorderChecklist(ctx context.Context, in *input.ChecklistOrder, audit *input.Audit) (*output.Todo, error) {
+	var fieldErrs apperrors.FieldErrors
+
+	idVo, err := tododomain.NewID(in.TodoID)
+	fieldErrs.Add("id", err)
+
+	itemIDVos := make([]tododomain.ChecklistItemID, len(in.ItemIDs))
+	for i, itemID := range in.ItemIDs {
+		itemIDVos[i], err = tododomain.NewChecklistItemID(itemID)
+		fieldErrs.Add("itemIDs", err)
+	}
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	return u.changeChecklist(ctx, idVo, in.ExpectedVersions, audit, func(todoDm *tododomain.Todo) error {
+		return todoDm.ReorderChecklist(itemIDVos)
+	})
+}
+
+// CheckChecklistItem はチェックリストの項目の完了を切り替える
+func (u *todoUsecase) CheckChecklistItem(ctx context.Context, in *input.ChecklistItemCheck, audit *input.Audit) (*output.Todo, error) {
+	var fieldErrs apperrors.FieldErrors
+
+	idVo, err := tododomain.NewID(in.TodoID)
+	fieldErrs.Add("id", err)
+
+	itemIDVo, err := tododomain.NewChecklistItemID(in.ItemID)
+	fieldErrs.Add("itemID", err)
+
+	if in.Done == nil {
+		fieldErrs.Add("done", apperrors.NewValidationError("required", nil, "完了は必須です"))
+	}
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	return u.changeChecklist(ctx, idVo, in.ExpectedVersions, audit, func(todoDm *tododomain.Todo) error {
+		return todoDm.CheckChecklistItem(itemIDVo, *in.Done)
+	})
+}
+
+// DeleteChecklistItem はチェックリストから項目を削除し、変更後のtodoを返す
+func (u *todoUsecase) DeleteChecklistItem(ctx context.Context, in *input.ChecklistItemDelete, audit *input.Audit) (*output.Todo, error) {
+	var fieldErrs apperrors.FieldErrors
+
+	idVo, err := tododomain.NewID(in.TodoID)
+	fieldErrs.Add("id", err)
+
+	itemIDVo, err := tododomain.NewChecklistItemID(in.ItemID)
+	fieldErrs.Add("itemID", err)
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	return u.changeChecklist(ctx, idVo, in.ExpectedVersions, audit, func(todoDm *tododomain.Todo) error {
+		return todoDm.RemoveChecklistItem(itemIDVo)
+	})
+}
+
+// changeChecklist はtodoを取得して権限とバージョンを確認し、チェックリストを変更して保存する
+func (u *todoUsecase) changeChecklist(
+	ctx context.Context,
+	idVo tododomain.ID,
+	expectedVersions []uint,
+	audit *input.Audit,
+	change func(todoDm *tododomain.Todo) error,
+) (*output.Todo, error) {
+	permission, err := fetchTodoPermission(ctx, u.projectRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	var out *output.Todo
+	err = u.transactionManager.Transaction(ctx, func(repo tododomain.Repository) error {
+		todoDm, err := repo.FetchTodoByID(ctx, idVo)
+		if err != nil {
+			return err
+		}
+
+		if err = permission.checkEdit(todoDm); err != nil {
+			return err
+		}
+
+		if err = checkVersion(todoDm, expectedVersions); err != nil {
+			return err
+		}
+
+		if err = change(todoDm); err != nil {
+			return err
+		}
+
+		out, err = saveTodo(ctx, repo, todoDm, tododomain.HistoryUpdate, audit)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func newChecklistOutputs(items []*tododomain.ChecklistItem) []*output.ChecklistItem {
+	out := make([]*output.ChecklistItem, len(items))
+	for i, item := range items {
+		out[i] = &output.ChecklistItem{
+			ID:    item.ID().Value(),
+			Title: item.Title().Value(),
+			Done:  item.IsDone(),
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/tododomain"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/persistence"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+)
+
+// ユースケースのテストはDBを使わずメモリのリポジトリで行う
+
+var testAudit = &input.Audit{Actor: "user:1", RequestID: "request"}
+
+func newTestTodoUsecase() *todoUsecase {
+	repo := persistence.NewTodoMemoryRepository()
+
+	return NewTodoUsecase(
+		repo,
+		persistence.NewMemoryTransactionManager(repo),
+		persistence.NewProjectMemoryRepository(repo),
+		persistence.NewTagMemoryRepository(repo),
+		24*time.Hour,
+	)
+}
+
+func userContext(id userdomain.ID) context.Context {
+	return userdomain.ContextWithUserID(context.Background(), id)
+}
+
+func newTodoInput(title string) *input.Todo {
+	return &input.Todo{
+		Title:              title,
+		ImplementationDate: time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC),
+		DueDate:            time.Date(2030, 4, 2, 0, 0, 0, 0, time.UTC),
+		PriorityID:         1,
+	}
+}
+
+func TestTodoUsecase_CreateTodo(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      *input.Todo
+		wantErr error
+	}{
+		{name: "正常系", in: newTodoInput("title")},
+		{name: "異常系: タイトルが空", in: newTodoInput(""), wantErr: apperrors.InvalidParameter},
+		{name: "異常系: 実施日が期限日より後", in: &input.Todo{
+			Title:              "title",
+			ImplementationDate: time.Date(2030, 4, 3, 0, 0, 0, 0, time.UTC),
+			DueDate:            time.Date(2030, 4, 2, 0, 0, 0, 0, time.UTC),
+			PriorityID:         1,
+		}, wantErr: apperrors.ImplementationDateAfterDueDate},
+		{name: "異常系: 参加していないプロジェクト", in: &input.Todo{
+			Title:              "title",
+			ImplementationDate: time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC),
+			DueDate:            time.Date(2030, 4, 2, 0, 0, 0, 0, time.UTC),
+			PriorityID:         1,
+			ProjectID:          1,
+		}, wantErr: apperrors.ProjectNotFound},
+		{name: "異常系: 存在しないタグ", in: &input.Todo{
+			Title:              "title",
+			ImplementationDate: time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC),
+			DueDate:            time.Date(2030, 4, 2, 0, 0, 0, 0, time.UTC),
+			PriorityID:         1,
+			TagIDs:             []int{1},
+		}, wantErr: apperrors.InvalidReference},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestTodoUsecase()
+			ctx := userContext(1)
+
+			out, err := u.CreateTodo(ctx, tt.in, testAudit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateTodo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := u.FetchTodo(ctx, out.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Title != tt.in.Title || got.Version != 1 || got.StatusID != 1 {
+				t.Errorf("FetchTodo() = %+v", got)
+			}
+
+			histories, err := u.FetchTodoHistories(ctx, out.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(histories) != 1 || histories[0].Action != "create" || histories[0].Actor != testAudit.Actor {
+				t.Errorf("FetchTodoHistories() = %+v", histories)
+			}
+		})
+	}
+}
+
+func TestTodoUsecase_UpdateTodo(t *testing.T) {
+	tests := []struct {
+		name             string
+		expectedVersions []uint
+		wantVersion      uint
+		wantErr          error
+	}{
+		{name: "正常系: バージョンの指定なし", wantVersion: 2},
+		{name: "正常系: 一致するバージョン", expectedVersions: []uint{5, 1}, wantVersion: 2},
+		{name: "異常系: 一致しないバージョン", expectedVersions: []uint{2}, wantErr: apperrors.PreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestTodoUsecase()
+			ctx := userContext(1)
+
+			created, err := u.CreateTodo(ctx, newTodoInput("title"), testAudit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			in := newTodoInput("changed")
+			in.ID = created.ID
+			in.StatusID = 2
+			in.ExpectedVersions = tt.expectedVersions
+
+			out, err := u.UpdateTodo(ctx, in, testAudit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateTodo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			got, err := u.FetchTodo(ctx, created.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != nil {
+				if got.Title != "title" || got.Version != 1 {
+					t.Errorf("FetchTodo() = %+v, want unchanged", got)
+				}
+				return
+			}
+			if out.Title != "changed" || out.StatusID != 2 || out.Version != tt.wantVersion || got.Version != tt.wantVersion {
+				t.Errorf("UpdateTodo() = %+v", out)
+			}
+		})
+	}
+}
+
+func TestTodoUsecase_DeleteTodo(t *testing.T) {
+	u := newTestTodoUsecase()
+	ctx := userContext(1)
+
+	created, err := u.CreateTodo(ctx, newTodoInput("title"), testAudit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = u.DeleteTodo(ctx, &input.TodoDelete{ID: created.ID, ExpectedVersions: []uint{2}}, testAudit); !errors.Is(err, apperrors.PreconditionFailed) {
+		t.Fatalf("DeleteTodo() error = %v, wantErr %v", err, apperrors.PreconditionFailed)
+	}
+
+	// 他のユーザーのtodoは存在しないものとして扱う
+	if err = u.DeleteTodo(userContext(2), &input.TodoDelete{ID: created.ID}, testAudit); !errors.Is(err, apperrors.TodoNotFound) {
+		t.Fatalf("DeleteTodo() other user error = %v, wantErr %v", err, apperrors.TodoNotFound)
+	}
+
+	if err = u.DeleteTodo(ctx, &input.TodoDelete{ID: created.ID}, testAudit); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = u.FetchTodo(ctx, created.ID); !errors.Is(err, apperrors.TodoNotFound) {
+		t.Errorf("FetchTodo() error = %v, wantErr %v", err, apperrors.TodoNotFound)
+	}
+
+	tests := []struct {
+		name    string
+		in      *input.TodoDelete
+		wantErr error
+	}{
+		{name: "異常系: 削除済み", in: &input.TodoDelete{ID: created.ID}, wantErr: apperrors.TodoNotFound},
+		{name: "正常系: 冪等な削除", in: &input.TodoDelete{ID: created.ID, Idempotent: true}},
+		{name: "異常系: 冪等な削除でバージョンを指定", in: &input.TodoDelete{ID: created.ID, Idempotent: true, ExpectedVersions: []uint{1}}, wantErr: apperrors.PreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := u.DeleteTodo(ctx, tt.in, testAudit); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteTodo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// ゴミ箱から復元できる
+	restored, err := u.RestoreTodo(ctx, created.ID, testAudit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.DeletedAt != nil || restored.Version != 3 {
+		t.Errorf("RestoreTodo() = %+v", restored)
+	}
+}
+
+func TestTodoUsecase_FetchTodoHistories(t *testing.T) {
+	u := newTestTodoUsecase()
+	ctx := userContext(1)
+
+	created, err := u.CreateTodo(ctx, newTodoInput("title"), testAudit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trashed, err := u.CreateTodo(ctx, newTodoInput("trashed"), testAudit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = u.DeleteTodo(ctx, &input.TodoDelete{ID: trashed.ID}, testAudit); err != nil {
+		t.Fatal(err)
+	}
+
+	// 履歴の記録より前に作成されたtodoには履歴がない
+	todoDm, err := tododomain.NewTodoWhenUnCreated("legacy", tododomain.ImplementationDate(time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC)), tododomain.DueDate(time.Date(2030, 4, 2, 0, 0, 0, 0, time.UTC)), tododomain.LOW, "", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacyID, err := u.todoRepository.CreateTodo(ctx, todoDm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		id      int
+		wantLen int
+		wantErr error
+	}{
+		{name: "正常系", ctx: ctx, id: created.ID, wantLen: 1},
+		{name: "正常系: ゴミ箱のtodo", ctx: ctx, id: trashed.ID, wantLen: 2},
+		{name: "正常系: 履歴のないtodoは空の一覧", ctx: ctx, id: legacyID.Value(), wantLen: 0},
+		{name: "異常系: 存在しないtodo", ctx: ctx, id: 100, wantErr: apperrors.TodoNotFound},
+		{name: "異常系: 他のユーザーのtodo", ctx: userContext(2), id: created.ID, wantErr: apperrors.TodoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := u.FetchTodoHistories(tt.ctx, tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FetchTodoHistories() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got == nil || len(got) != tt.wantLen) {
+				t.Errorf("FetchTodoHistories() = %+v, want %d histories", got, tt.wantLen)
+			}
+		})
+	}
+}
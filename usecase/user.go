@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+	"github.com/kazumakawahara/todo-sample/usecase/output"
+)
+
+type UserUsecase interface {
+	RegisterUser(ctx context.Context, in *input.UserRegistration) (*output.User, error)
+	Login(ctx context.Context, in *input.Login) (*output.Token, error)
+}
+
+type userUsecase struct {
+	userRepository userdomain.Repository
+	passwordHasher userdomain.PasswordHasher
+	tokenManager   userdomain.TokenManager
+
+	// 存在しないメールアドレスでも照合にかかる時間を揃えるためのハッシュ
+	dummyHashOnce sync.Once
+	dummyHash     userdomain.PasswordHash
+	dummyHashErr  error
+}
+
+func NewUserUsecase(
+	userRepository userdomain.Repository,
+	passwordHasher userdomain.PasswordHasher,
+	tokenManager userdomain.TokenManager,
+) *userUsecase {
+	return &userUsecase{
+		userRepository: userRepository,
+		passwordHasher: passwordHasher,
+		tokenManager:   tokenManager,
+	}
+}
+
+func (u *userUsecase) RegisterUser(ctx context.Context, in *input.UserRegistration) (*output.User, error) {
+	var fieldErrs apperrors.FieldErrors
+
+	emailVo, err := userdomain.NewEmail(in.Email)
+	fieldErrs.Add("email", err)
+
+	passwordVo, err := userdomain.NewPassword(in.Password)
+	fieldErrs.Add("password", err)
+
+	if err = fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	hash, err := u.passwordHasher.Hash(passwordVo)
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	userDm := userdomain.NewUserWhenUnCreated(emailVo, hash, time.Now().UTC().Truncate(time.Second))
+
+	idVo, err := u.userRepository.CreateUser(ctx, userDm)
+	if err != nil {
+		return nil, err
+	}
+
+	userDm, err = u.userRepository.FetchUserByID(ctx, idVo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output.User{
+		ID:        userDm.ID().Value(),
+		Email:     userDm.Email().Value(),
+		CreatedAt: userDm.CreatedAt(),
+	}, nil
+}
+
+// Login はメールアドレスとパスワードを確かめてトークンを発行する。
+// どちらが誤っているかは返さず、いずれも apperrors.InvalidCredentials とする。
+func (u *userUsecase) Login(ctx context.Context, in *input.Login) (*output.Token, error) {
+	emailVo, err := userdomain.NewEmail(in.Email)
+	if err != nil {
+		return nil, apperrors.InvalidCredentials
+	}
+
+	userDm, err := u.userRepository.FetchUserByEmail(ctx, emailVo)
+	if errors.Is(err, apperrors.UserNotFound) {
+		// 応答時間から登録済みのメールアドレスを推測されないよう、ユーザーがいなくても照合する
+		dummyHash, err := u.fetchDummyHash()
+		if err != nil {
+			return nil, err
+		}
+		u.passwordHasher.Matches(dummyHash, userdomain.Password(in.Password))
+
+		return nil, apperrors.InvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.passwordHasher.Matches(userDm.PasswordHash(), userdomain.Password(in.Password)) {
+		return nil, apperrors.InvalidCredentials
+	}
+
+	token, expiresAt, err := u.tokenManager.Issue(userDm.ID(), time.Now())
+	if err != nil {
+		return nil, apperrors.InternalServerError.Wrap(err)
+	}
+
+	return &output.Token{
+		Token:     token,
+		TokenType: "Bearer",
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// fetchDummyHash は照合用のダミーのハッシュを返す。ハッシュの方式・コストを揃えるため passwordHasher で初回のみ作成する
+func (u *userUsecase) fetchDummyHash() (userdomain.PasswordHash, error) {
+	u.dummyHashOnce.Do(func() {
+		u.dummyHash, u.dummyHashErr = u.passwordHasher.Hash("dummy-password")
+	})
+	if u.dummyHashErr != nil {
+		return "", apperrors.InternalServerError.Wrap(u.dummyHashErr)
+	}
+
+	return u.dummyHash, nil
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kazumakawahara/todo-sample/apperrors"
+	"github.com/kazumakawahara/todo-sample/domain/userdomain"
+	"github.com/kazumakawahara/todo-sample/infrastructure/auth"
+	"github.com/kazumakawahara/todo-sample/infrastructure/persistence"
+	"github.com/kazumakawahara/todo-sample/usecase/input"
+)
+
+// countingPasswordHasher は照合の回数を数える。ハッシュはパスワードに接頭辞を付けただけのもの
+type countingPasswordHasher struct {
+	matches int
+}
+
+func (h *countingPasswordHasher) Hash(password userdomain.Password) (userdomain.PasswordHash, error) {
+	return userdomain.PasswordHash("hash:" + password.Value()), nil
+}
+
+func (h *countingPasswordHasher) Matches(hash userdomain.PasswordHash, password userdomain.Password) bool {
+	h.matches++
+	return hash.Value() == "hash:"+password.Value()
+}
+
+func TestUserUsecase_Login(t *testing.T) {
+	hasher := &countingPasswordHasher{}
+	tokenManager := auth.NewHMACTokenManager([]byte("secret"), time.Hour)
+	u := NewUserUsecase(persistence.NewUserMemoryRepository(), hasher, tokenManager)
+
+	registered, err := u.RegisterUser(context.Background(), &input.UserRegistration{Email: "user@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		in          *input.Login
+		wantMatches int
+		wantErr     error
+	}{
+		{name: "正常系", in: &input.Login{Email: "user@example.com", Password: "password123"}, wantMatches: 1},
+		{name: "異常系: パスワードが誤っている", in: &input.Login{Email: "user@example.com", Password: "wrong"}, wantMatches: 1, wantErr: apperrors.InvalidCredentials},
+		// 登録済みかどうかが応答時間でわからないよう、ユーザーがいなくても照合する
+		{name: "異常系: 登録されていないメールアドレス", in: &input.Login{Email: "other@example.com", Password: "password123"}, wantMatches: 1, wantErr: apperrors.InvalidCredentials},
+		{name: "異常系: 不正なメールアドレス", in: &input.Login{Email: "invalid", Password: "password123"}, wantErr: apperrors.InvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher.matches = 0
+
+			out, err := u.Login(context.Background(), tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Login() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if hasher.matches != tt.wantMatches {
+				t.Errorf("Matches() called %d times, want %d", hasher.matches, tt.wantMatches)
+			}
+			if err != nil {
+				return
+			}
+
+			userID, err := tokenManager.Verify(out.Token, time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if userID.Value() != registered.ID || out.TokenType != "Bearer" {
+				t.Errorf("Login() = %+v, user %d", out, userID)
+			}
+		})
+	}
+}